@@ -0,0 +1,78 @@
+package pro
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// RangeArray is a slice of ranges that can be scanned from and encoded to a PostgreSQL array of
+// ranges. Scanned ranges get the operator of the array, multidimensional arrays are flattened.
+// A NULL array results in nil Ranges.
+type RangeArray[T any, S Number] struct {
+	Ranges []Range[T, S]
+	ro     operator[T, S]
+}
+
+type TimeRangeArray = RangeArray[time.Time, time.Duration]
+type IntegerRangeArray = RangeArray[int, int]
+
+func NewRangeArray[T any, S Number](ro operator[T, S], ranges ...Range[T, S]) RangeArray[T, S] {
+	return RangeArray[T, S]{
+		Ranges: ranges,
+		ro:     ro,
+	}
+}
+
+func NewIntegerRangeArray(ranges ...IntegerRange) IntegerRangeArray {
+	return NewRangeArray(NewInteger(), ranges...)
+}
+
+func NewTimeRangeArray(ranges ...TimeRange) TimeRangeArray {
+	return NewRangeArray(NewTime(), ranges...)
+}
+
+// Implement ArrayGetter interface
+func (a RangeArray[T, S]) Dimensions() []pgtype.ArrayDimension {
+	if a.Ranges == nil {
+		return nil
+	}
+	return []pgtype.ArrayDimension{{Length: int32(len(a.Ranges)), LowerBound: 1}}
+}
+
+func (a RangeArray[T, S]) Index(i int) any {
+	return a.Ranges[i]
+}
+
+func (a RangeArray[T, S]) IndexType() any {
+	return Range[T, S]{ro: a.ro}
+}
+
+// Implement ArraySetter interface
+func (a *RangeArray[T, S]) SetDimensions(dimensions []pgtype.ArrayDimension) error {
+	if dimensions == nil {
+		a.Ranges = nil
+		return nil
+	}
+
+	n := 0
+	if len(dimensions) > 0 {
+		n = 1
+		for _, d := range dimensions {
+			n *= int(d.Length)
+		}
+	}
+	a.Ranges = make([]Range[T, S], n)
+	for i := range a.Ranges {
+		a.Ranges[i].ro = a.ro
+	}
+	return nil
+}
+
+func (a *RangeArray[T, S]) ScanIndex(i int) any {
+	return &a.Ranges[i]
+}
+
+func (a RangeArray[T, S]) ScanIndexType() any {
+	return &Range[T, S]{ro: a.ro}
+}
@@ -0,0 +1,96 @@
+package pro
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestRangeArray(t *testing.T) {
+	m := pgtype.NewMap()
+	tests := []struct {
+		name  string
+		array IntegerRangeArray
+	}{
+		{
+			name: "ranges",
+			array: NewIntegerRangeArray(
+				NewIntegerRange(1, 5),
+				NewIntegerRange(-3, 3, WithLowerType[int, int](pgtype.Exclusive), WithUpperType[int, int](pgtype.Inclusive)),
+				NewEmptyIntegerRange(),
+				NewIntegerRange(0, 10, WithLowerInf[int, int]()),
+			),
+		},
+		{
+			name:  "empty",
+			array: NewIntegerRangeArray([]IntegerRange{}...),
+		},
+		{
+			name:  "null",
+			array: NewIntegerRangeArray(),
+		},
+	}
+
+	for _, tt := range tests {
+		for _, format := range []int16{pgtype.TextFormatCode, pgtype.BinaryFormatCode} {
+			buf, err := m.Encode(pgtype.Int8rangeArrayOID, format, tt.array, nil)
+			if err != nil {
+				t.Errorf("%s encode in format %d: expected no error, got `%v`", tt.name, format, err)
+				continue
+			}
+
+			result := NewIntegerRangeArray()
+			if err := m.Scan(pgtype.Int8rangeArrayOID, format, buf, &result); err != nil {
+				t.Errorf("%s scan in format %d: expected no error, got `%v`", tt.name, format, err)
+				continue
+			}
+			if (result.Ranges == nil) != (tt.array.Ranges == nil) || len(result.Ranges) != len(tt.array.Ranges) {
+				t.Errorf("%s in format %d: expected `%v`, got `%v`", tt.name, format, tt.array.Ranges, result.Ranges)
+				continue
+			}
+			for i, r := range result.Ranges {
+				// the operator must be set to be able to compare the ranges
+				if equal, err := r.Equal(tt.array.Ranges[i]); err != nil || !equal {
+					t.Errorf("%s in format %d: expected range `%v`, got `%v` (error `%v`)", tt.name, format, tt.array.Ranges[i].r, r.r, err)
+				}
+			}
+		}
+	}
+}
+
+func TestRangeArrayDatabase(t *testing.T) {
+	ctx := context.Background()
+	if _, err := conn.Exec(ctx, `CREATE TEMPORARY TABLE range_array (i int8range, t tstzrange)`); err != nil {
+		t.Fatalf("create table: expected no error, got `%v`", err)
+	}
+	defer conn.Exec(ctx, `DROP TABLE range_array`)
+
+	integerRanges := []IntegerRange{NewIntegerRange(1, 5), NewIntegerRange(10, 20), NewIntegerRange(0, 3, WithLowerInf[int, int]())}
+	timeRanges := []TimeRange{NewTimeRange(time.Unix(0, 0), time.Unix(60, 0)), NewTimeRange(time.Unix(60, 0), time.Unix(120, 0)), NewEmptyTimeRange()}
+	for i := range integerRanges {
+		if _, err := conn.Exec(ctx, `INSERT INTO range_array VALUES ($1::int8range, $2::tstzrange)`, integerRanges[i], timeRanges[i]); err != nil {
+			t.Fatalf("insert: expected no error, got `%v`", err)
+		}
+	}
+
+	integerResult := NewIntegerRangeArray()
+	timeResult := NewTimeRangeArray()
+	if err := conn.QueryRow(ctx, `SELECT array_agg(i ORDER BY i), array_agg(t ORDER BY t) FROM range_array`).Scan(&integerResult, &timeResult); err != nil {
+		t.Fatalf("select: expected no error, got `%v`", err)
+	}
+	if len(integerResult.Ranges) != len(integerRanges) || len(timeResult.Ranges) != len(timeRanges) {
+		t.Fatalf("select: expected `%d` ranges, got `%d` and `%d`", len(integerRanges), len(integerResult.Ranges), len(timeResult.Ranges))
+	}
+	for i, expected := range []IntegerRange{integerRanges[2], integerRanges[0], integerRanges[1]} {
+		if equal, err := expected.Equal(integerResult.Ranges[i]); err != nil || !equal {
+			t.Errorf("int8range[]: expected range `%v`, got `%v` (error `%v`)", expected, integerResult.Ranges[i], err)
+		}
+	}
+	for i, expected := range []TimeRange{timeRanges[2], timeRanges[0], timeRanges[1]} {
+		if equal, err := expected.Equal(timeResult.Ranges[i]); err != nil || !equal {
+			t.Errorf("tstzrange[]: expected range `%v`, got `%v` (error `%v`)", expected, timeResult.Ranges[i], err)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package pro
+
+import (
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// RangeBuilder constructs a Range step by step. Every method returns a new builder, so a builder
+// can be used as a template for several ranges.
+type RangeBuilder[T any, S Number] struct {
+	r  pgtype.Range[T]
+	ro operator[T, S]
+}
+
+// NewRangeBuilder creates a builder for ranges using the operator. Like the range constructors the
+// lower bound is inclusive and the upper bound is exclusive unless changed.
+func NewRangeBuilder[T any, S Number](ro operator[T, S]) RangeBuilder[T, S] {
+	return RangeBuilder[T, S]{
+		r: pgtype.Range[T]{
+			LowerType: pgtype.Inclusive,
+			UpperType: pgtype.Exclusive,
+			Valid:     true,
+		},
+		ro: ro,
+	}
+}
+
+// Lower sets the value of the lower bound, an unbounded lower bound becomes inclusive.
+func (b RangeBuilder[T, S]) Lower(v T) RangeBuilder[T, S] {
+	b.r.Lower = v
+	if b.r.LowerType == pgtype.Unbounded {
+		b.r.LowerType = pgtype.Inclusive
+	}
+	return b
+}
+
+func (b RangeBuilder[T, S]) LowerInclusive() RangeBuilder[T, S] {
+	b.r.LowerType = pgtype.Inclusive
+	return b
+}
+
+func (b RangeBuilder[T, S]) LowerExclusive() RangeBuilder[T, S] {
+	b.r.LowerType = pgtype.Exclusive
+	return b
+}
+
+func (b RangeBuilder[T, S]) LowerInf() RangeBuilder[T, S] {
+	b.r.Lower = b.ro.zero
+	b.r.LowerType = pgtype.Unbounded
+	return b
+}
+
+// Upper sets the value of the upper bound, an unbounded upper bound becomes exclusive.
+func (b RangeBuilder[T, S]) Upper(v T) RangeBuilder[T, S] {
+	b.r.Upper = v
+	if b.r.UpperType == pgtype.Unbounded {
+		b.r.UpperType = pgtype.Exclusive
+	}
+	return b
+}
+
+func (b RangeBuilder[T, S]) UpperInclusive() RangeBuilder[T, S] {
+	b.r.UpperType = pgtype.Inclusive
+	return b
+}
+
+func (b RangeBuilder[T, S]) UpperExclusive() RangeBuilder[T, S] {
+	b.r.UpperType = pgtype.Exclusive
+	return b
+}
+
+func (b RangeBuilder[T, S]) UpperInf() RangeBuilder[T, S] {
+	b.r.Upper = b.ro.zero
+	b.r.UpperType = pgtype.Unbounded
+	return b
+}
+
+// Build returns the range, or an error if the range is malformed.
+func (b RangeBuilder[T, S]) Build() (Range[T, S], error) {
+	result := FromPgtypeRange(b.r, b.ro)
+	if err := result.Validate(); err != nil {
+		return Range[T, S]{}, err
+	}
+	return result, nil
+}
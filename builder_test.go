@@ -0,0 +1,86 @@
+package pro
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestRangeBuilder(t *testing.T) {
+	builder := NewRangeBuilder(NewInteger()).Lower(1).Upper(5)
+	tests := []struct {
+		builder     RangeBuilder[int, int]
+		expected    pgtype.Range[int]
+		expectedErr error
+	}{
+		{
+			builder:  builder,
+			expected: pgtype.Range[int]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+		},
+		{
+			builder:  builder.LowerExclusive().UpperInclusive(),
+			expected: pgtype.Range[int]{Lower: 1, LowerType: pgtype.Exclusive, Upper: 5, UpperType: pgtype.Inclusive, Valid: true},
+		},
+		{
+			builder:  builder.LowerExclusive().UpperExclusive(),
+			expected: pgtype.Range[int]{Lower: 1, LowerType: pgtype.Exclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+		},
+		{
+			builder:  builder.LowerInclusive().UpperInclusive(),
+			expected: pgtype.Range[int]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Inclusive, Valid: true},
+		},
+		{
+			builder:  builder.LowerInf(),
+			expected: pgtype.Range[int]{LowerType: pgtype.Unbounded, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+		},
+		{
+			builder:  builder.UpperInf(),
+			expected: pgtype.Range[int]{Lower: 1, LowerType: pgtype.Inclusive, UpperType: pgtype.Unbounded, Valid: true},
+		},
+		{
+			builder:  builder.LowerInf().UpperInf(),
+			expected: pgtype.Range[int]{LowerType: pgtype.Unbounded, UpperType: pgtype.Unbounded, Valid: true},
+		},
+		{
+			builder:  builder.LowerInf().Lower(3),
+			expected: pgtype.Range[int]{Lower: 3, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+		},
+		{
+			builder:     builder.Lower(10),
+			expectedErr: ErrMalformedRange,
+		},
+	}
+
+	for _, tt := range tests {
+		result, err := tt.builder.Build()
+		if tt.expectedErr != nil {
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("build `%v`: expected error `%v`, got `%v`", tt.builder.r, tt.expectedErr, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("build `%v`: expected no error, got `%v`", tt.builder.r, err)
+			continue
+		}
+		if result.r != tt.expected {
+			t.Errorf("build `%v`: expected result `%v`, got `%v`", tt.builder.r, tt.expected, result.r)
+		}
+		if err := result.Validate(); err != nil {
+			t.Errorf("build `%v`: expected a valid range, got `%v`", tt.builder.r, err)
+		}
+	}
+
+	if builder.r.Lower != 1 || builder.r.LowerType != pgtype.Inclusive {
+		t.Errorf("builder: expected the template to be unchanged, got `%v`", builder.r)
+	}
+
+	r, err := NewRangeBuilder(NewTime()).Lower(time.Unix(0, 0)).Upper(time.Unix(60, 0)).UpperInclusive().Build()
+	if err != nil {
+		t.Errorf("build time range: expected no error, got `%v`", err)
+	} else if contains, _ := r.ContainElement(time.Unix(60, 0)); !contains {
+		t.Errorf("build time range: expected `%v` to contain the upper bound", r.r)
+	}
+}
@@ -0,0 +1,170 @@
+package pro
+
+import (
+	"cmp"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// DebugOperator wraps an operator and checks every comparison, it is meant for development of
+// custom operators. The operations return an error wrapping [ErrInconsistentCmp] when cmp(a, b)
+// and cmp(b, a) don't have opposite signs, when cmp(a, a) is not zero or when cmp panics, instead
+// of silently producing a wrong result. Every comparison calls cmp three times, so the operations
+// are slower.
+type DebugOperator[T any, S Number] struct {
+	ro operator[T, S]
+}
+
+// NewDebug creates a debug operator that checks the cmp function of ro.
+func NewDebug[T any, S Number](ro operator[T, S]) DebugOperator[T, S] {
+	if ro.IsZero() {
+		return DebugOperator[T, S]{ro: ro}
+	}
+	unchecked := ro.cmp
+	ro.cmp = func(a, b T) (result int) {
+		defer func() {
+			if r := recover(); r != nil {
+				if _, ok := r.(cmpViolation); ok {
+					panic(r)
+				}
+				panic(cmpViolation{fmt.Errorf("cmp(%v, %v) panicked with %v: %w", a, b, r, ErrInconsistentCmp)})
+			}
+		}()
+		result = unchecked(a, b)
+		reverse := unchecked(b, a)
+		if sign(result) != -sign(reverse) {
+			panic(cmpViolation{fmt.Errorf("cmp(%v, %v) = %d but cmp(%v, %v) = %d: %w", a, b, result, b, a, reverse, ErrInconsistentCmp)})
+		}
+		if self := unchecked(a, a); self != 0 {
+			panic(cmpViolation{fmt.Errorf("cmp(%v, %v) = %d: %w", a, a, self, ErrInconsistentCmp)})
+		}
+		return result
+	}
+	return DebugOperator[T, S]{ro: ro}
+}
+
+// Operator returns the operator with the checked cmp function, for the operations the debug
+// operator doesn't wrap. Its methods panic with an error wrapping [ErrInconsistentCmp] instead of
+// returning it.
+func (d DebugOperator[T, S]) Operator() operator[T, S] {
+	return d.ro
+}
+
+func (d DebugOperator[T, S]) Empty(r pgtype.Range[T]) (bool, error) {
+	return debugCall(func() (bool, error) { return d.ro.Empty(r) })
+}
+
+func (d DebugOperator[T, S]) Validate(r pgtype.Range[T]) error {
+	_, err := debugCall(func() (struct{}, error) { return struct{}{}, d.ro.Validate(r) })
+	return err
+}
+
+func (d DebugOperator[T, S]) Size(r pgtype.Range[T]) (S, error) {
+	return debugCall(func() (S, error) { return d.ro.Size(r) })
+}
+
+func (d DebugOperator[T, S]) Equal(first, second pgtype.Range[T]) (bool, error) {
+	return debugCall(func() (bool, error) { return d.ro.Equal(first, second) })
+}
+
+func (d DebugOperator[T, S]) LessThan(first, second pgtype.Range[T]) (bool, error) {
+	return debugCall(func() (bool, error) { return d.ro.LessThan(first, second) })
+}
+
+func (d DebugOperator[T, S]) LessThanOrEqualTo(first, second pgtype.Range[T]) (bool, error) {
+	return debugCall(func() (bool, error) { return d.ro.LessThanOrEqualTo(first, second) })
+}
+
+func (d DebugOperator[T, S]) GreaterThan(first, second pgtype.Range[T]) (bool, error) {
+	return debugCall(func() (bool, error) { return d.ro.GreaterThan(first, second) })
+}
+
+func (d DebugOperator[T, S]) GreaterThanOrEqualTo(first, second pgtype.Range[T]) (bool, error) {
+	return debugCall(func() (bool, error) { return d.ro.GreaterThanOrEqualTo(first, second) })
+}
+
+func (d DebugOperator[T, S]) Contain(first, second pgtype.Range[T]) (bool, error) {
+	return debugCall(func() (bool, error) { return d.ro.Contain(first, second) })
+}
+
+func (d DebugOperator[T, S]) ContainElement(first pgtype.Range[T], elem T) (bool, error) {
+	return debugCall(func() (bool, error) { return d.ro.ContainElement(first, elem) })
+}
+
+func (d DebugOperator[T, S]) ContainedBy(first, second pgtype.Range[T]) (bool, error) {
+	return debugCall(func() (bool, error) { return d.ro.ContainedBy(first, second) })
+}
+
+func (d DebugOperator[T, S]) ElementContainedBy(elem T, r pgtype.Range[T]) (bool, error) {
+	return debugCall(func() (bool, error) { return d.ro.ElementContainedBy(elem, r) })
+}
+
+func (d DebugOperator[T, S]) Overlap(first, second pgtype.Range[T]) (bool, error) {
+	return debugCall(func() (bool, error) { return d.ro.Overlap(first, second) })
+}
+
+func (d DebugOperator[T, S]) LeftOf(first, second pgtype.Range[T]) (bool, error) {
+	return debugCall(func() (bool, error) { return d.ro.LeftOf(first, second) })
+}
+
+func (d DebugOperator[T, S]) RightOf(first, second pgtype.Range[T]) (bool, error) {
+	return debugCall(func() (bool, error) { return d.ro.RightOf(first, second) })
+}
+
+func (d DebugOperator[T, S]) NotExtendRight(first, second pgtype.Range[T]) (bool, error) {
+	return debugCall(func() (bool, error) { return d.ro.NotExtendRight(first, second) })
+}
+
+func (d DebugOperator[T, S]) NotExtendLeft(first, second pgtype.Range[T]) (bool, error) {
+	return debugCall(func() (bool, error) { return d.ro.NotExtendLeft(first, second) })
+}
+
+func (d DebugOperator[T, S]) Adjacent(first, second pgtype.Range[T]) (bool, error) {
+	return debugCall(func() (bool, error) { return d.ro.Adjacent(first, second) })
+}
+
+func (d DebugOperator[T, S]) Union(first, second pgtype.Range[T]) (pgtype.Range[T], error) {
+	return debugCall(func() (pgtype.Range[T], error) { return d.ro.Union(first, second) })
+}
+
+func (d DebugOperator[T, S]) Merge(first, second pgtype.Range[T]) (pgtype.Range[T], error) {
+	return debugCall(func() (pgtype.Range[T], error) { return d.ro.Merge(first, second) })
+}
+
+func (d DebugOperator[T, S]) Intersect(first, second pgtype.Range[T]) (pgtype.Range[T], error) {
+	return debugCall(func() (pgtype.Range[T], error) { return d.ro.Intersect(first, second) })
+}
+
+func (d DebugOperator[T, S]) Difference(first, second pgtype.Range[T]) (pgtype.Range[T], error) {
+	return debugCall(func() (pgtype.Range[T], error) { return d.ro.Difference(first, second) })
+}
+
+func (d DebugOperator[T, S]) MergeOverlapping(rs []pgtype.Range[T]) ([]pgtype.Range[T], error) {
+	return debugCall(func() ([]pgtype.Range[T], error) { return d.ro.MergeOverlapping(rs) })
+}
+
+// cmpViolation is the panic value of the checked cmp function of a debug operator
+type cmpViolation struct {
+	error
+}
+
+// debugCall calls fn and turns the panic of a checked cmp function into an error, other panics
+// are not recovered
+func debugCall[R any](fn func() (R, error)) (result R, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			v, ok := r.(cmpViolation)
+			if !ok {
+				panic(r)
+			}
+			var zero R
+			result, err = zero, v.error
+		}
+	}()
+	return fn()
+}
+
+func sign(i int) int {
+	return cmp.Compare(i, 0)
+}
@@ -0,0 +1,77 @@
+package pro
+
+import (
+	"cmp"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestDebug(t *testing.T) {
+	first := pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true}
+	second := pgtype.Range[int64]{Lower: 3, LowerType: pgtype.Inclusive, Upper: 9, UpperType: pgtype.Exclusive, Valid: true}
+
+	// a consistent cmp gives the same results
+	dro := NewDebug(iro)
+	if result, err := dro.Overlap(first, second); err != nil || !result {
+		t.Errorf("`%v` && `%v`: expected result `true`, got `%v` (error `%v`)", first, second, result, err)
+	}
+	if result, err := dro.Union(first, second); err != nil || !reflect.DeepEqual(result, pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 9, UpperType: pgtype.Exclusive, Valid: true}) {
+		t.Errorf("`%v` + `%v`: expected result `[1,9)`, got `%v` (error `%v`)", first, second, result, err)
+	}
+
+	broken := []struct {
+		description string
+		cmp         func(a, b int64) int
+	}{
+		{
+			description: "always less",
+			cmp: func(a, b int64) int {
+				return -1
+			},
+		},
+		{
+			description: "not reflexive",
+			cmp: func(a, b int64) int {
+				if a == b {
+					return 1
+				}
+				return cmp.Compare(a, b)
+			},
+		},
+		{
+			description: "panics",
+			cmp: func(a, b int64) int {
+				if a == 3 || b == 3 {
+					panic("three")
+				}
+				return cmp.Compare(a, b)
+			},
+		},
+	}
+	for _, tt := range broken {
+		ro := iro
+		ro.cmp = tt.cmp
+		dro := NewDebug(ro)
+		if _, err := dro.Overlap(first, second); !errors.Is(err, ErrInconsistentCmp) {
+			t.Errorf("%s: `%v` && `%v`: expected error `%v`, got `%v`", tt.description, first, second, ErrInconsistentCmp, err)
+		}
+		if _, err := dro.Union(first, second); !errors.Is(err, ErrInconsistentCmp) {
+			t.Errorf("%s: `%v` + `%v`: expected error `%v`, got `%v`", tt.description, first, second, ErrInconsistentCmp, err)
+		}
+		if _, err := dro.MergeOverlapping([]pgtype.Range[int64]{second, first}); !errors.Is(err, ErrInconsistentCmp) {
+			t.Errorf("%s: merge overlapping: expected error `%v`, got `%v`", tt.description, ErrInconsistentCmp, err)
+		}
+		// the operations of the checked operator panic
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("%s: less `%v` `%v`: expected panic, got none", tt.description, first, second)
+				}
+			}()
+			dro.Operator().Less(first, second)
+		}()
+	}
+}
@@ -0,0 +1,42 @@
+package pro
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+type DecimalRange = Range[decimal.Decimal, float64]
+
+// NewDecimal creates a continuous operator for decimal values, the equivalent of numrange. Sizes
+// and distances are lengths with a float64 difference type like NewFloat64, so they don't depend
+// on the bound types and a range with different bounds is never empty. The difference is computed
+// exactly and then rounded to a float64, so a size can lose precision.
+func NewDecimal() operator[decimal.Decimal, float64] {
+	return operator[decimal.Decimal, float64]{
+		cmp: func(a, b decimal.Decimal) int {
+			return a.Cmp(b)
+		},
+		diff: func(a, b decimal.Decimal) float64 {
+			return a.Sub(b).InexactFloat64()
+		},
+		add: func(a decimal.Decimal, d float64) decimal.Decimal {
+			return a.Add(decimal.NewFromFloat(d))
+		},
+		addOne: func(a decimal.Decimal) decimal.Decimal {
+			return a.Add(decimal.NewFromInt(1))
+		},
+		zero:     decimal.Zero,
+		discrete: false,
+		format: func(v decimal.Decimal) string {
+			return v.String()
+		},
+		parse: decimal.NewFromString,
+		// the text drops trailing zeros, so 1.0 and 1 have the same key
+		hashKey: func(v decimal.Decimal) any {
+			return v.String()
+		},
+	}
+}
+
+func NewDecimalRange(lower, upper decimal.Decimal, opts ...RangeOption[decimal.Decimal, float64]) DecimalRange {
+	return NewRange(NewDecimal(), lower, upper, opts...)
+}
@@ -0,0 +1,155 @@
+package pro
+
+import (
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/munnik/pgx_range_operator/protest"
+	"github.com/shopspring/decimal"
+)
+
+func TestDecimal(t *testing.T) {
+	dro := NewDecimal()
+	d := decimal.RequireFromString
+	r := pgtype.Range[decimal.Decimal]{Lower: d("0.5"), LowerType: pgtype.Inclusive, Upper: d("1.5"), UpperType: pgtype.Exclusive, Valid: true}
+
+	tests := []struct {
+		other           pgtype.Range[decimal.Decimal]
+		expectedOverlap bool
+		expectedContain bool
+	}{
+		{
+			other:           pgtype.Range[decimal.Decimal]{Lower: d("0.75"), LowerType: pgtype.Inclusive, Upper: d("1.25"), UpperType: pgtype.Inclusive, Valid: true},
+			expectedOverlap: true,
+			expectedContain: true,
+		},
+		{
+			// the same values with a different scale
+			other:           pgtype.Range[decimal.Decimal]{Lower: d("0.500"), LowerType: pgtype.Inclusive, Upper: d("1.50"), UpperType: pgtype.Exclusive, Valid: true},
+			expectedOverlap: true,
+			expectedContain: true,
+		},
+		{
+			other:           pgtype.Range[decimal.Decimal]{Lower: d("1.5"), LowerType: pgtype.Inclusive, Upper: d("2.5"), UpperType: pgtype.Exclusive, Valid: true},
+			expectedOverlap: false,
+			expectedContain: false,
+		},
+		{
+			other:           pgtype.Range[decimal.Decimal]{Lower: d("1.4999999999999"), LowerType: pgtype.Inclusive, Upper: d("2.5"), UpperType: pgtype.Exclusive, Valid: true},
+			expectedOverlap: true,
+			expectedContain: false,
+		},
+		{
+			other:           pgtype.Range[decimal.Decimal]{Lower: d("-1"), LowerType: pgtype.Inclusive, Upper: d("0.5"), UpperType: pgtype.Inclusive, Valid: true},
+			expectedOverlap: true,
+			expectedContain: false,
+		},
+		{
+			other:           pgtype.Range[decimal.Decimal]{LowerType: pgtype.Unbounded, Upper: d("0.5"), UpperType: pgtype.Exclusive, Valid: true},
+			expectedOverlap: false,
+			expectedContain: false,
+		},
+	}
+
+	for _, tt := range tests {
+		if result, err := dro.Overlap(r, tt.other); err != nil || result != tt.expectedOverlap {
+			t.Errorf("`%v` && `%v`: expected result `%v`, got `%v` (error `%v`)", r, tt.other, tt.expectedOverlap, result, err)
+		}
+		if result, err := dro.Contain(r, tt.other); err != nil || result != tt.expectedContain {
+			t.Errorf("`%v` @> `%v`: expected result `%v`, got `%v` (error `%v`)", r, tt.other, tt.expectedContain, result, err)
+		}
+	}
+
+	for _, tt := range []struct {
+		elem     decimal.Decimal
+		expected bool
+	}{
+		{elem: d("0.5"), expected: true},
+		{elem: d("1.4999999999999"), expected: true},
+		{elem: d("1.5"), expected: false},
+		{elem: d("0.4999999999999"), expected: false},
+	} {
+		if result, err := dro.ContainElement(r, tt.elem); err != nil || result != tt.expected {
+			t.Errorf("`%v` @> `%v`: expected result `%v`, got `%v` (error `%v`)", r, tt.elem, tt.expected, result, err)
+		}
+	}
+
+	// the size is the length of the range
+	for _, tt := range []struct {
+		r        pgtype.Range[decimal.Decimal]
+		expected float64
+	}{
+		{r: r, expected: 1},
+		{r: pgtype.Range[decimal.Decimal]{Lower: d("1"), LowerType: pgtype.Inclusive, Upper: d("1.0000000000001"), UpperType: pgtype.Exclusive, Valid: true}, expected: 1e-13},
+		{r: pgtype.Range[decimal.Decimal]{Lower: d("-1e30"), LowerType: pgtype.Inclusive, Upper: d("1e30"), UpperType: pgtype.Exclusive, Valid: true}, expected: 2e30},
+		{r: pgtype.Range[decimal.Decimal]{Lower: d("1"), LowerType: pgtype.Exclusive, Upper: d("1"), UpperType: pgtype.Inclusive, Valid: true}, expected: 0},
+	} {
+		if result, err := dro.Size(tt.r); err != nil || result != tt.expected {
+			t.Errorf("size `%v`: expected result `%v`, got `%v` (error `%v`)", tt.r, tt.expected, result, err)
+		}
+	}
+
+	// very narrow exclusive ranges are not empty
+	for _, other := range []pgtype.Range[decimal.Decimal]{
+		{Lower: d("1"), LowerType: pgtype.Exclusive, Upper: d("1.000000001"), UpperType: pgtype.Exclusive, Valid: true},
+		{Lower: d("1"), LowerType: pgtype.Exclusive, Upper: d("1.0000000001"), UpperType: pgtype.Exclusive, Valid: true},
+		{Lower: d("1"), LowerType: pgtype.Exclusive, Upper: d("1e-400").Add(d("1")), UpperType: pgtype.Exclusive, Valid: true},
+	} {
+		if result, err := dro.Empty(other); err != nil || result {
+			t.Errorf("isempty `%v`: expected result `false`, got `%v` (error `%v`)", other, result, err)
+		}
+	}
+
+	// the same values with a different scale hash equally
+	other := pgtype.Range[decimal.Decimal]{Lower: d("0.500"), LowerType: pgtype.Inclusive, Upper: d("1.50"), UpperType: pgtype.Exclusive, Valid: true}
+	if first, err := dro.Hash(r); err != nil {
+		t.Errorf("hash `%v`: expected no error, got `%v`", r, err)
+	} else if second, _ := dro.Hash(other); first != second {
+		t.Errorf("hash `%v` and `%v`: expected equal hashes, got `%v` and `%v`", r, other, first, second)
+	}
+
+	// bounds are formatted as decimals
+	if result := NewDecimalRange(d("0.5"), d("1.50")).String(); result != "[0.5,1.5)" {
+		t.Errorf("string: expected result `[0.5,1.5)`, got `%v`", result)
+	}
+	result := NewDecimalRange(decimal.Zero, decimal.Zero)
+	if err := result.UnmarshalText([]byte("(-2.25,)")); err != nil {
+		t.Errorf("unmarshal text `(-2.25,)`: expected no error, got `%v`", err)
+	} else if !result.r.Lower.Equal(d("-2.25")) || result.r.LowerType != pgtype.Exclusive || result.r.UpperType != pgtype.Unbounded {
+		t.Errorf("unmarshal text `(-2.25,)`: expected result `(-2.25,)`, got `%v`", result)
+	}
+
+	// pgx has no type for decimal.Decimal, the operator formats and parses the bounds
+	value, err := NewDecimalRange(d("0.5"), d("1.50")).Value()
+	if err != nil || value != "[0.5,1.5)" {
+		t.Errorf("value: expected result `[0.5,1.5)`, got `%v` (error `%v`)", value, err)
+	}
+	if err := result.Scan("[-1,2.5]"); err != nil {
+		t.Errorf("scan `[-1,2.5]`: expected no error, got `%v`", err)
+	} else if !result.r.Lower.Equal(d("-1")) || !result.r.Upper.Equal(d("2.5")) || result.r.LowerType != pgtype.Inclusive || result.r.UpperType != pgtype.Inclusive {
+		t.Errorf("scan `[-1,2.5]`: expected result `[-1,2.5]`, got `%v`", result)
+	}
+}
+
+func TestDecimalEmptyDatabase(t *testing.T) {
+	dro := NewDecimal()
+	d := decimal.RequireFromString
+	// very narrow exclusive ranges are not empty for numrange
+	ranges := []pgtype.Range[decimal.Decimal]{
+		{Lower: d("1"), LowerType: pgtype.Exclusive, Upper: d("1.000000001"), UpperType: pgtype.Exclusive, Valid: true},
+		{Lower: d("1"), LowerType: pgtype.Exclusive, Upper: d("1.0000000001"), UpperType: pgtype.Exclusive, Valid: true},
+		{Lower: d("1"), LowerType: pgtype.Exclusive, Upper: d("1.00000000000000000001"), UpperType: pgtype.Inclusive, Valid: true},
+		{Lower: d("1"), LowerType: pgtype.Exclusive, Upper: d("1"), UpperType: pgtype.Inclusive, Valid: true},
+		{Lower: d("1"), LowerType: pgtype.Inclusive, Upper: d("1.0"), UpperType: pgtype.Inclusive, Valid: true},
+	}
+	for _, r := range ranges {
+		expected, expectedErr := protest.RetrieveExpected[bool](conn, "SELECT isempty(@r::numrange)", pgx.NamedArgs{"r": r})
+		if expectedErr != nil {
+			t.Fatalf("isempty `%v`: expected no error, got `%v`", r, expectedErr)
+		}
+		if result, err := dro.Empty(r); err != nil || result != expected {
+			t.Errorf("isempty `%v`: expected result `%v`, got `%v` (error `%v`)", r, expected, result, err)
+		}
+	}
+}
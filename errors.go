@@ -0,0 +1,28 @@
+package pro
+
+import "errors"
+
+var (
+	// ErrInvalidRange is returned when an operand has Valid set to false.
+	ErrInvalidRange = errors.New("range is not valid")
+	// ErrUnboundedRange is returned when a bound is needed but the range is unbounded on that side.
+	ErrUnboundedRange = errors.New("range is unbounded")
+	// ErrNotContiguous is returned when the result can not be represented as a single range.
+	ErrNotContiguous = errors.New("result would not be contiguous")
+	// ErrEmptyUndefined is returned when an operation is undefined for an empty range.
+	ErrEmptyUndefined = errors.New("operation is undefined for an empty range")
+	// ErrMalformedRange is returned when the bounds of a range are inconsistent.
+	ErrMalformedRange = errors.New("range is malformed")
+	// ErrOutOfRange is returned when a value is not contained by the range.
+	ErrOutOfRange = errors.New("value is out of range")
+	// ErrNaN is returned when a bound of a range is not a number.
+	ErrNaN = errors.New("bound is not a number")
+	// ErrNoAdd is returned when an operation needs the add function of an operator created with New.
+	ErrNoAdd = errors.New("operator has no add function")
+	// ErrInconsistentCmp is returned by an operator created with NewDebug when its cmp function
+	// is inconsistent.
+	ErrInconsistentCmp = errors.New("cmp function is inconsistent")
+	// ErrUninitialized is returned by the zero value of an operator, operators must be created
+	// with one of the constructors like New or NewInteger.
+	ErrUninitialized = errors.New("operator not initialized")
+)
@@ -5,6 +5,7 @@ go 1.23.5
 require (
 	github.com/jackc/pgx/v5 v5.7.2
 	github.com/ory/dockertest/v3 v3.11.0
+	github.com/shopspring/decimal v1.4.0
 	golang.org/x/exp v0.0.0-20250207012021-f9890c6ad9f3
 )
 
@@ -2,19 +2,55 @@ package pro
 
 import (
 	"cmp"
+	"encoding"
+	"encoding/binary"
 	"fmt"
+	"hash"
+	"hash/fnv"
+	"iter"
+	"math"
+	"math/big"
+	"slices"
 	"time"
+	"unsafe"
 
 	"github.com/jackc/pgx/v5/pgtype"
 	"golang.org/x/exp/constraints"
 )
 
-type operator[T any, S constraints.Integer] struct {
+// Number is the constraint for the difference type of an operator, the size of a range and the
+// distance between values are expressed in this type.
+type Number interface {
+	constraints.Integer | constraints.Float
+}
+
+// operator implements the range operators and functions for ranges of T, differences between
+// values of T are of type S. An operator is not changed after it is created, the methods that
+// configure it return a copy, and its methods don't modify their arguments. A single operator can
+// be used by multiple goroutines concurrently, as long as the functions it was created with are
+// safe for concurrent use. The pointer methods of the Range wrapper, like Scan and SetLower, do
+// modify the range and need synchronization like any other value.
+type operator[T any, S Number] struct {
 	cmp      func(a, b T) int
 	diff     func(a, b T) S
+	add      func(a T, d S) T
 	addOne   func(a T) T
 	zero     T
 	discrete bool
+	// special is optional, it reports if a value is not a number and returns -1 for negative
+	// infinity and 1 for positive infinity
+	special func(v T) (nan bool, inf int)
+	// format and parse are optional, they convert a bound to and from its text representation
+	// when the range is converted to text
+	format func(v T) string
+	parse  func(text string) (T, error)
+	// marshalBinary and unmarshalBinary are optional, they convert a bound to and from its binary
+	// representation when the range is converted to binary
+	marshalBinary   func(v T) ([]byte, error)
+	unmarshalBinary func(data []byte) (T, error)
+	// hashKey is optional, it maps a bound to a value that is the same for bounds that compare
+	// equal, Hash hashes the key instead of the bound
+	hashKey func(v T) any
 }
 
 // Create a new operator for the Range[T] type
@@ -25,8 +61,11 @@ type operator[T any, S constraints.Integer] struct {
 // The diff function is used to calculate the difference between to values of type T, the
 // function should return a -b. The return type of this function is S.
 //
+// The operator has no add function, the operations that need one, like Shift and Midpoint,
+// return [ErrNoAdd]. Use [NewWithAdd] to create an operator that supports them.
+//
 // Also see the functions [pgxrangeoperator.NewInteger] and [pgxrangeoperator.NewTime]
-func New[T any, S constraints.Integer](cmp func(a, b T) int, diff func(a, b T) S, addOne func(a T) T, discrete bool) operator[T, S] {
+func New[T any, S Number](cmp func(a, b T) int, diff func(a, b T) S, addOne func(a T) T, discrete bool) operator[T, S] {
 	return operator[T, S]{
 		cmp:      cmp,
 		diff:     diff,
@@ -36,16 +75,134 @@ func New[T any, S constraints.Integer](cmp func(a, b T) int, diff func(a, b T) S
 	}
 }
 
+// Create a new operator for the Range[T] type with an add function, see [New] for the cmp
+// and diff functions.
+//
+// The add function is the inverse of the diff function, it should return a + d. The next
+// value of a discrete type is add(a, 1).
+func NewWithAdd[T any, S Number](cmp func(a, b T) int, diff func(a, b T) S, add func(a T, d S) T, discrete bool) operator[T, S] {
+	return operator[T, S]{
+		cmp:      cmp,
+		diff:     diff,
+		add:      add,
+		addOne:   func(a T) T { return add(a, 1) },
+		zero:     *new(T),
+		discrete: discrete,
+	}
+}
+
 func NewInteger() operator[int, int] {
 	return operator[int, int]{
 		cmp:      cmp.Compare[int],
 		diff:     func(a, b int) int { return a - b },
+		add:      func(a, d int) int { return a + d },
 		addOne:   func(a int) int { return a + 1 },
 		zero:     0,
 		discrete: true,
 	}
 }
 
+func NewInt32() operator[int32, int32] {
+	return operator[int32, int32]{
+		cmp:      cmp.Compare[int32],
+		diff:     func(a, b int32) int32 { return a - b },
+		add:      func(a, d int32) int32 { return a + d },
+		addOne:   func(a int32) int32 { return a + 1 },
+		zero:     0,
+		discrete: true,
+	}
+}
+
+func NewInt16() operator[int16, int16] {
+	return operator[int16, int16]{
+		cmp:      cmp.Compare[int16],
+		diff:     func(a, b int16) int16 { return a - b },
+		add:      func(a, d int16) int16 { return a + d },
+		addOne:   func(a int16) int16 { return a + 1 },
+		zero:     0,
+		discrete: true,
+	}
+}
+
+// The difference between two uint32 values can be negative, so the difference type is int64
+func NewUint32() operator[uint32, int64] {
+	return operator[uint32, int64]{
+		cmp:      cmp.Compare[uint32],
+		diff:     func(a, b uint32) int64 { return int64(a) - int64(b) },
+		add:      func(a uint32, d int64) uint32 { return uint32(int64(a) + d) },
+		addOne:   func(a uint32) uint32 { return a + 1 },
+		zero:     0,
+		discrete: true,
+	}
+}
+
+// NewFloat32 creates a continuous operator for float32 values with a float32 difference type, so
+// the size of a range is its length and doesn't depend on the bound types. A negative infinite
+// lower bound and a positive infinite upper bound are treated as unbounded, like PostgreSQL allows
+// infinity in numeric ranges. Operators return an error for ranges with a NaN bound, comparing NaN
+// values directly orders NaN before all other values like cmp.Compare does.
+func NewFloat32() operator[float32, float32] {
+	return operator[float32, float32]{
+		cmp: cmp.Compare[float32],
+		diff: func(a, b float32) float32 {
+			return a - b
+		},
+		add: func(a, d float32) float32 {
+			return a + d
+		},
+		addOne: func(a float32) float32 {
+			return math.Nextafter32(a, float32(math.Inf(1)))
+		},
+		zero:     0,
+		discrete: false,
+		special:  floatSpecial[float32],
+		hashKey:  floatHashKey[float32],
+	}
+}
+
+// NewFloat64 creates a continuous operator for float64 values, see NewFloat32. The difference type
+// is float64.
+func NewFloat64() operator[float64, float64] {
+	return operator[float64, float64]{
+		cmp: cmp.Compare[float64],
+		diff: func(a, b float64) float64 {
+			return a - b
+		},
+		add: func(a, d float64) float64 {
+			return a + d
+		},
+		addOne: func(a float64) float64 {
+			return math.Nextafter(a, math.Inf(1))
+		},
+		zero:     0,
+		discrete: false,
+		special:  floatSpecial[float64],
+		hashKey:  floatHashKey[float64],
+	}
+}
+
+// floatHashKey maps negative zero to zero, they compare equal
+func floatHashKey[F float32 | float64](v F) any {
+	if v == 0 {
+		return F(0)
+	}
+	return v
+}
+
+// floatSpecial reports if a float is NaN or infinite
+func floatSpecial[F float32 | float64](v F) (bool, int) {
+	f := float64(v)
+	switch {
+	case math.IsNaN(f):
+		return true, 0
+	case math.IsInf(f, -1):
+		return false, -1
+	case math.IsInf(f, 1):
+		return false, 1
+	}
+	return false, 0
+}
+
 func NewTime() operator[time.Time, time.Duration] {
 	return operator[time.Time, time.Duration]{
 		cmp: func(a, b time.Time) int {
@@ -59,23 +216,231 @@ func NewTime() operator[time.Time, time.Duration] {
 		diff: func(a, b time.Time) time.Duration {
 			return a.Sub(b)
 		},
+		add: func(a time.Time, d time.Duration) time.Time {
+			return a.Add(d)
+		},
 		addOne: func(a time.Time) time.Time {
 			return a.Add(time.Duration(1))
 		},
 		zero:     *new(time.Time),
 		discrete: false,
+		format: func(v time.Time) string {
+			return v.Format(time.RFC3339Nano)
+		},
+		parse: func(text string) (time.Time, error) {
+			return time.Parse(time.RFC3339Nano, text)
+		},
+		// equal instants in different locations have the same key
+		hashKey: func(v time.Time) any {
+			return v.UTC()
+		},
+	}
+}
+
+// NewTimeWithStep creates a discrete time operator where step is the smallest distance between two
+// time values. Sizes and distances are a number of steps instead of a duration, and so are the
+// amounts of Shift, Expand, Contract and MergeWithTolerance. The counts are stored in a
+// time.Duration, so one step prints as 1ns. A number of steps that is too large for a
+// time.Duration once multiplied by step saturates, like time.Time.Sub does. Like
+// time.Time.Truncate, a step that is not positive has no effect, the result is the continuous
+// operator of NewTime.
+func NewTimeWithStep(step time.Duration) operator[time.Time, time.Duration] {
+	result := NewTime()
+	if step <= 0 {
+		return result
+	}
+	result.diff = func(a, b time.Time) time.Duration {
+		return a.Sub(b) / step
+	}
+	result.add = func(a time.Time, d time.Duration) time.Time {
+		switch {
+		case d > math.MaxInt64/step:
+			return a.Add(math.MaxInt64)
+		case d < math.MinInt64/step:
+			return a.Add(math.MinInt64)
+		}
+		return a.Add(d * step)
+	}
+	result.addOne = func(a time.Time) time.Time {
+		return a.Add(step)
+	}
+	result.discrete = true
+	return result
+}
+
+// NewDate creates a discrete operator for dates, the equivalent of daterange. Time values are
+// compared by their date in UTC, so the time of day is ignored. Sizes and distances are a number
+// of days.
+func NewDate() operator[time.Time, int] {
+	return operator[time.Time, int]{
+		cmp: func(a, b time.Time) int {
+			return cmp.Compare(dayNumber(a), dayNumber(b))
+		},
+		diff: func(a, b time.Time) int {
+			return int(dayNumber(a) - dayNumber(b))
+		},
+		add: func(a time.Time, d int) time.Time {
+			return truncateToDate(a).AddDate(0, 0, d)
+		},
+		addOne: func(a time.Time) time.Time {
+			return truncateToDate(a).AddDate(0, 0, 1)
+		},
+		zero:     *new(time.Time),
+		discrete: true,
+		format: func(v time.Time) string {
+			return v.UTC().Format(time.DateOnly)
+		},
+		parse: func(text string) (time.Time, error) {
+			return time.Parse(time.DateOnly, text)
+		},
+		hashKey: func(v time.Time) any {
+			return dayNumber(v)
+		},
+	}
+}
+
+// NewDuration creates a discrete operator for durations, the step between two durations is a
+// nanosecond. Sizes and distances are durations as well. PostgreSQL has no range of intervals, a
+// duration range matches an int8range of nanoseconds.
+func NewDuration() operator[time.Duration, time.Duration] {
+	return operator[time.Duration, time.Duration]{
+		cmp: cmp.Compare[time.Duration],
+		diff: func(a, b time.Duration) time.Duration {
+			return a - b
+		},
+		add: func(a, d time.Duration) time.Duration {
+			return a + d
+		},
+		addOne: func(a time.Duration) time.Duration {
+			return a + 1
+		},
+		zero:     0,
+		discrete: true,
+		format: func(v time.Duration) string {
+			return v.String()
+		},
+		parse: time.ParseDuration,
+	}
+}
+
+// dayNumber returns the number of days since the Unix epoch of the date of t in UTC
+func dayNumber(t time.Time) int64 {
+	s := t.Unix()
+	days := s / 86400
+	if s%86400 < 0 {
+		days--
+	}
+	return days
+}
+
+// truncateToDate returns midnight UTC of the date of t in UTC
+func truncateToDate(t time.Time) time.Time {
+	year, month, day := t.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// SnapToStep widens a time range to whole steps, the lower bound is rounded down and the upper
+// bound is rounded up to a multiple of step since the zero time. The result includes the lower
+// bound and excludes the upper bound, so snapped ranges of adjacent steps are adjacent. Unbounded
+// sides are left untouched, invalid ranges are returned as is and empty ranges are returned as
+// the empty range. Like time.Time.Truncate, the range is returned unchanged if step is not
+// positive. Methods can't be declared for a single element type, so this is a function instead of
+// an operator method.
+func SnapToStep(r pgtype.Range[time.Time], step time.Duration) pgtype.Range[time.Time] {
+	if step <= 0 {
+		return r
+	}
+	if !r.Valid || hasEmptyBound(r) {
+		return normalizeEmpty(r)
+	}
+
+	if r.LowerType != pgtype.Unbounded {
+		r.Lower = r.Lower.Truncate(step)
+		r.LowerType = pgtype.Inclusive
+	}
+	if r.UpperType != pgtype.Unbounded {
+		upper := r.Upper.Truncate(step)
+		// an inclusive upper bound on a step boundary belongs to the next step
+		if upper.Before(r.Upper) || r.UpperType == pgtype.Inclusive {
+			upper = upper.Add(step)
+		}
+		r.Upper = upper
+		r.UpperType = pgtype.Exclusive
 	}
+	return r
+}
+
+// WithHashKey returns a copy of the operator that hashes key(v) instead of a bound v in Hash. The
+// key must be the same for values that compare equal, for example a normalized form of v.
+func (ro operator[T, S]) WithHashKey(key func(v T) any) operator[T, S] {
+	ro.hashKey = key
+	return ro
+}
+
+// WithBinary returns a copy of the operator that uses marshal and unmarshal to convert the bounds
+// to and from binary in MarshalBinary and UnmarshalBinary. By default fixed-size types, int,
+// uint and types that implement encoding.BinaryMarshaler, like time.Time, are supported.
+func (ro operator[T, S]) WithBinary(marshal func(v T) ([]byte, error), unmarshal func(data []byte) (T, error)) operator[T, S] {
+	ro.marshalBinary = marshal
+	ro.unmarshalBinary = unmarshal
+	return ro
+}
+
+// IsZero reports whether the operator is the zero value, it is not created by one of the
+// constructors. The methods of such an operator that return an error return ErrUninitialized,
+// the methods that compare bounds without returning an error, like Compare and Rewrite, panic
+// with ErrUninitialized.
+func (ro operator[T, S]) IsZero() bool {
+	return ro.cmp == nil
+}
+
+// errNoAdd is the error of an operation that needs the add function, a zero operator has no add
+// function either but it is not initialized at all
+func (ro operator[T, S]) errNoAdd(operation string) error {
+	if ro.IsZero() {
+		return fmt.Errorf("%s: %w", operation, ErrUninitialized)
+	}
+	return fmt.Errorf("%s: %w", operation, ErrNoAdd)
+}
+
+// WithFormat returns a copy of the operator that uses format to convert the bounds to text in
+// String and MarshalText. The parse function is the inverse of format and is used by
+// UnmarshalText, when it is nil the text is decoded like PostgreSQL text. By default the bounds
+// are converted like pgx does, the operator created by NewTime uses RFC 3339.
+func (ro operator[T, S]) WithFormat(format func(v T) string, parse func(text string) (T, error)) operator[T, S] {
+	ro.format = format
+	ro.parse = parse
+	return ro
 }
 
 func (ro operator[T, S]) Empty(r pgtype.Range[T]) (bool, error) {
 	if !r.Valid {
-		return false, fmt.Errorf("range is not valid")
+		return false, ErrInvalidRange
+	}
+	r, err := ro.normalizeSpecial(r)
+	if err != nil {
+		return false, err
+	}
+	if hasEmptyBound(r) {
+		return true, nil
 	}
 	if r.LowerType == pgtype.Unbounded || r.UpperType == pgtype.Unbounded {
 		return false, nil
 	}
-	s, _ := ro.Size(r)
-	return r.LowerType == pgtype.Empty || r.UpperType == pgtype.Empty || s <= 0, nil
+	s, err := ro.Size(r)
+	if err != nil {
+		return false, fmt.Errorf("empty: %w", err)
+	}
+	if ro.fractional() && !ro.discrete {
+		// the bounds are compared instead of the size, a difference rounded to S can be zero
+		c := ro.cmp(r.Upper, r.Lower)
+		return c < 0 || (c == 0 && (r.LowerType != pgtype.Inclusive || r.UpperType != pgtype.Inclusive)), nil
+	}
+	// the difference of a range wider than S can represent wraps around
+	if s <= 0 && ro.diff(r.Upper, r.Lower) < 0 && ro.cmp(r.Upper, r.Lower) > 0 {
+		return false, nil
+	}
+	return s <= 0, nil
 }
 
 func (ro operator[T, S]) LowerInf(r pgtype.Range[T]) bool {
@@ -86,18 +451,41 @@ func (ro operator[T, S]) UpperInf(r pgtype.Range[T]) bool {
 	return r.LowerType == pgtype.Unbounded
 }
 
-// Is the first range equal to the second?
+// Does the range cover every value, that is, is it valid and unbounded on both sides?
+func (ro operator[T, S]) IsFull(r pgtype.Range[T]) bool {
+	r, err := ro.normalizeSpecial(r)
+	return err == nil && r.Valid && r.LowerType == pgtype.Unbounded && r.UpperType == pgtype.Unbounded
+}
+
+// Is the lower bound of the canonicalized range inclusive? Always false for invalid, empty and
+// unbounded ranges.
+// PostgreSQL equivalent: lower_inc(anyrange) → boolean
+func (ro operator[T, S]) LowerInc(r pgtype.Range[T]) bool {
+	return r.Valid && ro.Rewrite(r).LowerType == pgtype.Inclusive
+}
+
+// Is the upper bound of the canonicalized range inclusive? Always false for invalid, empty and
+// unbounded ranges.
+// PostgreSQL equivalent: upper_inc(anyrange) → boolean
+func (ro operator[T, S]) UpperInc(r pgtype.Range[T]) bool {
+	return r.Valid && ro.Rewrite(r).UpperType == pgtype.Inclusive
+}
+
+// Is the first range equal to the second? All empty ranges are equal, no matter the bounds they
+// are written with, for example [5,5) and (3,3] both equal the empty range.
 // PostgreSQL equivalent: anyrange = anyrange → boolean
 func (ro operator[T, S]) Equal(first, second pgtype.Range[T]) (bool, error) {
 	if !first.Valid {
-		return false, fmt.Errorf("first range is not valid")
+		return false, fmt.Errorf("first %w", ErrInvalidRange)
 	}
 	if !second.Valid {
-		return false, fmt.Errorf("second range is not valid")
+		return false, fmt.Errorf("second %w", ErrInvalidRange)
 	}
 
-	firstEmpty, _ := ro.Empty(first)
-	secondEmpty, _ := ro.Empty(second)
+	firstEmpty, secondEmpty, err := ro.emptyBoth(first, second)
+	if err != nil {
+		return false, err
+	}
 	if firstEmpty && secondEmpty {
 		return true, nil
 	}
@@ -123,23 +511,51 @@ func (ro operator[T, S]) Equal(first, second pgtype.Range[T]) (bool, error) {
 // PostgreSQL equivalent: anyrange < anyrange → boolean
 func (ro operator[T, S]) LessThan(first, second pgtype.Range[T]) (bool, error) {
 	if !first.Valid {
-		return false, fmt.Errorf("first range is not valid")
+		return false, fmt.Errorf("first %w", ErrInvalidRange)
 	}
 	if !second.Valid {
-		return false, fmt.Errorf("second range is not valid")
+		return false, fmt.Errorf("second %w", ErrInvalidRange)
+	}
+	if _, _, err := ro.emptyBoth(first, second); err != nil {
+		return false, err
 	}
 
 	return ro.compareRanges(first, second) < 0, nil
 }
 
+// Compare orders the ranges like the comparison operators, it returns a negative number when
+// a is less than b, a positive number when a is greater than b and zero when they are equal. It
+// can be used with slices.SortFunc. Invalid ranges are ordered after valid ranges, like
+// PostgreSQL orders NULL values by default.
+func (ro operator[T, S]) Compare(a, b pgtype.Range[T]) int {
+	if !a.Valid || !b.Valid {
+		if a.Valid {
+			return -1
+		}
+		if b.Valid {
+			return 1
+		}
+		return 0
+	}
+	return ro.compareRanges(a, b)
+}
+
+// Less reports if a is ordered before b, see Compare. It can be used with sort.Slice.
+func (ro operator[T, S]) Less(a, b pgtype.Range[T]) bool {
+	return ro.Compare(a, b) < 0
+}
+
 // Is the first range ess than or equal to the second?
 // PostgreSQL equivalent: anyrange <= anyrange → boolean
 func (ro operator[T, S]) LessThanOrEqualTo(first, second pgtype.Range[T]) (bool, error) {
 	if !first.Valid {
-		return false, fmt.Errorf("first range is not valid")
+		return false, fmt.Errorf("first %w", ErrInvalidRange)
 	}
 	if !second.Valid {
-		return false, fmt.Errorf("second range is not valid")
+		return false, fmt.Errorf("second %w", ErrInvalidRange)
+	}
+	if _, _, err := ro.emptyBoth(first, second); err != nil {
+		return false, err
 	}
 
 	return ro.compareRanges(first, second) <= 0, nil
@@ -149,10 +565,13 @@ func (ro operator[T, S]) LessThanOrEqualTo(first, second pgtype.Range[T]) (bool,
 // PostgreSQL equivalent: anyrange > anyrange → boolean
 func (ro operator[T, S]) GreaterThan(first, second pgtype.Range[T]) (bool, error) {
 	if !first.Valid {
-		return false, fmt.Errorf("first range is not valid")
+		return false, fmt.Errorf("first %w", ErrInvalidRange)
 	}
 	if !second.Valid {
-		return false, fmt.Errorf("second range is not valid")
+		return false, fmt.Errorf("second %w", ErrInvalidRange)
+	}
+	if _, _, err := ro.emptyBoth(first, second); err != nil {
+		return false, err
 	}
 
 	return ro.compareRanges(first, second) > 0, nil
@@ -162,10 +581,13 @@ func (ro operator[T, S]) GreaterThan(first, second pgtype.Range[T]) (bool, error
 // PostgreSQL equivalent: anyrange >= anyrange → boolean
 func (ro operator[T, S]) GreaterThanOrEqualTo(first, second pgtype.Range[T]) (bool, error) {
 	if !first.Valid {
-		return false, fmt.Errorf("first range is not valid")
+		return false, fmt.Errorf("first %w", ErrInvalidRange)
 	}
 	if !second.Valid {
-		return false, fmt.Errorf("second range is not valid")
+		return false, fmt.Errorf("second %w", ErrInvalidRange)
+	}
+	if _, _, err := ro.emptyBoth(first, second); err != nil {
+		return false, err
 	}
 
 	return ro.compareRanges(first, second) >= 0, nil
@@ -173,136 +595,454 @@ func (ro operator[T, S]) GreaterThanOrEqualTo(first, second pgtype.Range[T]) (bo
 
 // Does the first range contain the second?
 // PostgreSQL equivalent: anyrange @> anyrange → boolean
+//
+// The bounds are compared directly, every range contains the empty range and an empty range
+// contains no other range.
 func (ro operator[T, S]) Contain(first, second pgtype.Range[T]) (bool, error) {
-	intersect, err := ro.Intersect(first, second)
-	if err != nil {
-		return false, err
-	}
-	return ro.Equal(intersect, second)
-}
-
-// Does the range contain the element?
-// PostgreSQL equivalent: anyrange @> anyelement → boolean
-func (ro operator[T, S]) ContainElement(first pgtype.Range[T], elem T) (bool, error) {
-	second := pgtype.Range[T]{Lower: elem, Upper: elem, Valid: true}
-	second.SetBoundTypes(pgtype.Inclusive, pgtype.Inclusive)
-	return ro.Contain(first, second)
-}
-
-// Do the ranges overlap, that is, have any elements in common?
-// PostgreSQL equivalent: anyrange && anyrange → boolean
-func (ro operator[T, S]) Overlap(first, second pgtype.Range[T]) (bool, error) {
 	if !first.Valid {
-		return false, fmt.Errorf("first range is not valid")
+		return false, fmt.Errorf("first %w", ErrInvalidRange)
 	}
 	if !second.Valid {
-		return false, fmt.Errorf("second range is not valid")
+		return false, fmt.Errorf("second %w", ErrInvalidRange)
 	}
 
-	firstEmpty, _ := ro.Empty(first)
-	secondEmpty, _ := ro.Empty(second)
-	if firstEmpty || secondEmpty {
+	firstEmpty, secondEmpty, err := ro.emptyBoth(first, second)
+	if err != nil {
+		return false, err
+	}
+	if secondEmpty {
+		return true, nil
+	}
+	if firstEmpty {
 		return false, nil
 	}
 
 	first = ro.Rewrite(first)
 	second = ro.Rewrite(second)
+	return ro.compareBounds(first, second, true, true) <= 0 && ro.compareBounds(first, second, false, false) >= 0, nil
+}
 
-	if ro.compareBounds(first, second, true, true) >= 0 && ro.compareBounds(first, second, true, false) <= 0 {
-		return true, nil
+// Does the first range properly contain the second, that is, contain it without being equal
+// to it? An empty range is properly contained by every range that is not empty.
+func (ro operator[T, S]) ProperContain(first, second pgtype.Range[T]) (bool, error) {
+	contain, err := ro.Contain(first, second)
+	if err != nil || !contain {
+		return false, err
 	}
-	if ro.compareBounds(second, first, true, true) >= 0 && ro.compareBounds(second, first, true, false) <= 0 {
-		return true, nil
+	equal, err := ro.Equal(first, second)
+	if err != nil {
+		return false, err
 	}
-
-	return false, nil
+	return !equal, nil
 }
 
-// Is the first range strictly left of the second?
-// PostgreSQL equivalent: anyrange << anyrange → boolean
-func (ro operator[T, S]) LeftOf(first, second pgtype.Range[T]) (bool, error) {
+// Does the range contain the element?
+// PostgreSQL equivalent: anyrange @> anyelement → boolean
+//
+// The element is compared with the bounds directly, so no values are created from it and
+// elements at the limits of the type don't overflow.
+func (ro operator[T, S]) ContainElement(first pgtype.Range[T], elem T) (bool, error) {
 	if !first.Valid {
-		return false, fmt.Errorf("first range is not valid")
+		return false, ErrInvalidRange
 	}
-	if !second.Valid {
-		return false, fmt.Errorf("second range is not valid")
+	first, err := ro.normalizeSpecial(first)
+	if err != nil {
+		return false, err
 	}
-
-	firstEmpty, _ := ro.Empty(first)
-	secondEmpty, _ := ro.Empty(second)
-	if firstEmpty || secondEmpty {
+	if ro.special != nil {
+		if nan, _ := ro.special(elem); nan {
+			return false, fmt.Errorf("element %w", ErrNaN)
+		}
+	}
+	if hasEmptyBound(first) {
 		return false, nil
 	}
 
-	first = ro.Rewrite(first)
-	second = ro.Rewrite(second)
-
-	return ro.compareBounds(first, second, false, true) < 0, nil
+	return ro.compareBoundValues(first.Lower, first.LowerType, true, elem, pgtype.Inclusive, true) <= 0 &&
+		ro.compareBoundValues(elem, pgtype.Inclusive, false, first.Upper, first.UpperType, false) <= 0, nil
 }
 
-// Is the first range strictly right of the second?
-// PostgreSQL equivalent: anyrange >> anyrange → boolean
-func (ro operator[T, S]) RightOf(first, second pgtype.Range[T]) (bool, error) {
-	return ro.LeftOf(second, first)
+// Merges the ranges into the fewest ranges that don't overlap and are not adjacent, ordered by
+// their lower bound. Empty ranges are dropped. The ranges are canonicalized once, after sorting
+// them a single pass merges them in place, so the running time is O(n log n) dominated by the
+// sort and the result is the only allocation.
+func (ro operator[T, S]) MergeOverlapping(rs []pgtype.Range[T]) ([]pgtype.Range[T], error) {
+	result := make([]pgtype.Range[T], 0, len(rs))
+	for i, r := range rs {
+		// Empty reports invalid ranges and NaN bounds, Rewrite passes them through
+		if _, err := ro.Empty(r); err != nil {
+			return nil, fmt.Errorf("range %d: %w", i, err)
+		}
+		r = ro.Rewrite(r)
+		if r.LowerType != pgtype.Empty {
+			result = append(result, r)
+		}
+	}
+	slices.SortFunc(result, func(a, b pgtype.Range[T]) int {
+		return ro.compareBoundValues(a.Lower, a.LowerType, true, b.Lower, b.LowerType, true)
+	})
+
+	n := 0
+	for _, r := range result {
+		if n > 0 {
+			last := &result[n-1]
+			// the lower bound of r is not below the lower bound of last, so the ranges are connected
+			// if r starts before last ends or right where it ends
+			overlap := ro.compareBoundValues(last.Upper, last.UpperType, false, r.Lower, r.LowerType, true) >= 0
+			adjacent := last.UpperType != pgtype.Unbounded && r.LowerType != pgtype.Unbounded &&
+				ro.cmp(last.Upper, r.Lower) == 0 &&
+				(last.UpperType == pgtype.Inclusive || r.LowerType == pgtype.Inclusive)
+			if overlap || adjacent {
+				if ro.compareBoundValues(r.Upper, r.UpperType, false, last.Upper, last.UpperType, false) > 0 {
+					last.Upper, last.UpperType = r.Upper, r.UpperType
+				}
+				continue
+			}
+		}
+		result[n] = r
+		n++
+	}
+	return result[:n], nil
 }
 
-// Does the first range not extend to the right of the second?
-// PostgreSQL equivalent: anyrange &< anyrange → boolean
-func (ro operator[T, S]) NotExtendRight(first, second pgtype.Range[T]) (bool, error) {
-	if !first.Valid {
-		return false, fmt.Errorf("first range is not valid")
+// Merges the ranges like MergeOverlapping and also merges ranges that are at most maxGap apart,
+// the gaps between them become part of the result. The distance between ranges is the Size of
+// their Gap, the gap is only closed when maxGap is positive, so a maxGap of zero gives the same
+// result as MergeOverlapping. A gap too wide for S, its size wraps around, is never closed.
+func (ro operator[T, S]) MergeWithTolerance(rs []pgtype.Range[T], maxGap S) ([]pgtype.Range[T], error) {
+	if !(maxGap >= 0) {
+		return nil, fmt.Errorf("maximum gap %v is negative", maxGap)
 	}
-	if !second.Valid {
-		return false, fmt.Errorf("second range is not valid")
+	result, err := ro.MergeOverlapping(rs)
+	if err != nil {
+		return nil, err
 	}
 
-	firstEmpty, _ := ro.Empty(first)
-	secondEmpty, _ := ro.Empty(second)
-	if firstEmpty || secondEmpty {
-		return false, nil
+	n := 0
+	for _, r := range result {
+		if n > 0 {
+			last := &result[n-1]
+			// the merged ranges are ordered and apart, so the gap between them is not empty
+			gap, err := ro.Gap(*last, r)
+			if err != nil {
+				return nil, err
+			}
+			size, err := ro.Size(gap)
+			if err != nil {
+				return nil, err
+			}
+			// a negative size wrapped around, the gap is wider than any maxGap
+			if maxGap > 0 && size >= 0 && size <= maxGap {
+				last.Upper, last.UpperType = r.Upper, r.UpperType
+				continue
+			}
+		}
+		result[n] = r
+		n++
 	}
-
-	first = ro.Rewrite(first)
-	second = ro.Rewrite(second)
-
-	return ro.compareBounds(first, second, false, false) <= 0, nil
+	return result[:n], nil
 }
 
-// Does the first range not extend to the left of the second?
-// PostgreSQL equivalent: anyrange &> anyrange → boolean
-func (ro operator[T, S]) NotExtendLeft(first, second pgtype.Range[T]) (bool, error) {
-	if !first.Valid {
-		return false, fmt.Errorf("first range is not valid")
-	}
-	if !second.Valid {
-		return false, fmt.Errorf("second range is not valid")
+// Computes the smallest range that contains all the ranges, gaps between the ranges are
+// included. Empty ranges are ignored, the result is empty if there are no other ranges.
+func (ro operator[T, S]) Cover(rs []pgtype.Range[T]) (pgtype.Range[T], error) {
+	result := makeEmptyRange[T]()
+	for i, r := range rs {
+		if _, err := ro.Empty(r); err != nil {
+			return pgtype.Range[T]{}, fmt.Errorf("range %d: %w", i, err)
+		}
+		r = ro.Rewrite(r)
+		// the empty bounds of an empty range or of the initial result are ignored
+		result.Lower, result.LowerType = ro.extremeBound(result.Lower, result.LowerType, r.Lower, r.LowerType, true, true)
+		result.Upper, result.UpperType = ro.extremeBound(result.Upper, result.UpperType, r.Upper, r.UpperType, false, true)
 	}
+	return result, nil
+}
 
-	firstEmpty, _ := ro.Empty(first)
-	secondEmpty, _ := ro.Empty(second)
-	if firstEmpty || secondEmpty {
-		return false, nil
+// Does the outer range contain all the inner ranges? Empty inner ranges are always contained.
+func (ro operator[T, S]) ContainsAllRanges(outer pgtype.Range[T], inners []pgtype.Range[T]) (bool, error) {
+	if !outer.Valid {
+		return false, ErrInvalidRange
 	}
-
-	first = ro.Rewrite(first)
-	second = ro.Rewrite(second)
-
-	return ro.compareBounds(first, second, true, true) >= 0, nil
+	for _, inner := range inners {
+		contains, err := ro.Contain(outer, inner)
+		if err != nil || !contains {
+			return false, err
+		}
+	}
+	return true, nil
 }
 
-// Are the ranges adjacent?
-// PostgreSQL equivalent: anyrange -|- anyrange → boolean
-func (ro operator[T, S]) Adjacent(first, second pgtype.Range[T]) (bool, error) {
-	if !first.Valid {
-		return false, fmt.Errorf("first range is not valid")
+// Does any range in a overlap any range in b? Both sets are merged and sorted first, after
+// that a single sweep over both sets is enough.
+func (ro operator[T, S]) AnyOverlap(a, b []pgtype.Range[T]) (bool, error) {
+	a, err := ro.MergeOverlapping(a)
+	if err != nil {
+		return false, fmt.Errorf("first %w", err)
 	}
-	if !second.Valid {
-		return false, fmt.Errorf("second range is not valid")
+	b, err = ro.MergeOverlapping(b)
+	if err != nil {
+		return false, fmt.Errorf("second %w", err)
 	}
 
-	firstEmpty, _ := ro.Empty(first)
-	secondEmpty, _ := ro.Empty(second)
-	if firstEmpty || secondEmpty {
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		overlap, err := ro.Overlap(a[i], b[j])
+		if err != nil || overlap {
+			return overlap, err
+		}
+		// the range that ends first can not overlap any of the remaining ranges of the other set
+		if ro.compareBounds(a[i], b[j], false, false) < 0 {
+			i++
+		} else {
+			j++
+		}
+	}
+	return false, nil
+}
+
+// Are all the ranges disjoint, that is, does no range overlap any other range? When two ranges
+// overlap their indices are returned, the smaller index first, otherwise both indices are -1.
+// The ranges are sorted by their lower bound first, after that a single sweep is enough.
+func (ro operator[T, S]) AllDisjoint(rs []pgtype.Range[T]) (bool, int, int, error) {
+	indices := make([]int, 0, len(rs))
+	canonical := make([]pgtype.Range[T], len(rs))
+	for i, r := range rs {
+		if e, err := ro.Empty(r); err != nil {
+			return false, -1, -1, fmt.Errorf("range %d: %w", i, err)
+		} else if e {
+			// an empty range doesn't overlap any range
+			continue
+		}
+		canonical[i] = ro.Rewrite(r)
+		indices = append(indices, i)
+	}
+	slices.SortStableFunc(indices, func(a, b int) int {
+		return ro.compareBoundValues(canonical[a].Lower, canonical[a].LowerType, true, canonical[b].Lower, canonical[b].LowerType, true)
+	})
+
+	// last is the index of the range that ends last of the ranges seen so far
+	last := -1
+	for _, i := range indices {
+		if last >= 0 {
+			if ro.compareBoundValues(canonical[last].Upper, canonical[last].UpperType, false, canonical[i].Lower, canonical[i].LowerType, true) >= 0 {
+				return false, min(last, i), max(last, i), nil
+			}
+		}
+		if last < 0 || ro.compareBoundValues(canonical[i].Upper, canonical[i].UpperType, false, canonical[last].Upper, canonical[last].UpperType, false) > 0 {
+			last = i
+		}
+	}
+	return true, -1, -1, nil
+}
+
+// Counts how many of the values fall in each of the buckets, the result has a count for every
+// bucket. The buckets must be sorted and disjoint, so a binary search finds the bucket of a
+// value. Values outside all the buckets are ignored and empty buckets count no values.
+func (ro operator[T, S]) Bucketize(buckets []pgtype.Range[T], values []T) ([]int, error) {
+	// indices of the non-empty buckets, in order
+	indices := make([]int, 0, len(buckets))
+	canonical := make([]pgtype.Range[T], len(buckets))
+	for i, b := range buckets {
+		if e, err := ro.Empty(b); err != nil {
+			return nil, fmt.Errorf("bucket %d: %w", i, err)
+		} else if e {
+			continue
+		}
+		canonical[i] = ro.Rewrite(b)
+		if len(indices) > 0 {
+			previous := indices[len(indices)-1]
+			if ro.compareBoundValues(canonical[previous].Upper, canonical[previous].UpperType, false, canonical[i].Lower, canonical[i].LowerType, true) >= 0 {
+				return nil, fmt.Errorf("buckets %d and %d are not sorted and disjoint", previous, i)
+			}
+		}
+		indices = append(indices, i)
+	}
+
+	counts := make([]int, len(buckets))
+	for i, v := range values {
+		if ro.special != nil {
+			if nan, _ := ro.special(v); nan {
+				return nil, fmt.Errorf("value %d %w", i, ErrNaN)
+			}
+		}
+		// the value is compared like the range [v,v]
+		j, found := slices.BinarySearchFunc(indices, v, func(b int, v T) int {
+			if ro.compareBoundValues(canonical[b].Upper, canonical[b].UpperType, false, v, pgtype.Inclusive, true) < 0 {
+				return -1
+			}
+			if ro.compareBoundValues(canonical[b].Lower, canonical[b].LowerType, true, v, pgtype.Inclusive, false) > 0 {
+				return 1
+			}
+			return 0
+		})
+		if found {
+			counts[indices[j]]++
+		}
+	}
+	return counts, nil
+}
+
+// Finds an overlapping pair of the ranges like AllDisjoint and returns the pair, in the order of
+// the slice, and their intersection. Found is false if all the ranges are disjoint.
+func (ro operator[T, S]) FirstOverlap(rs []pgtype.Range[T]) (a, b, overlap pgtype.Range[T], found bool, err error) {
+	disjoint, i, j, err := ro.AllDisjoint(rs)
+	if err != nil || disjoint {
+		return pgtype.Range[T]{}, pgtype.Range[T]{}, pgtype.Range[T]{}, false, err
+	}
+	overlap, err = ro.Intersect(rs[i], rs[j])
+	if err != nil {
+		return pgtype.Range[T]{}, pgtype.Range[T]{}, pgtype.Range[T]{}, false, err
+	}
+	return rs[i], rs[j], overlap, true, nil
+}
+
+// Is the first range contained by the second?
+// PostgreSQL equivalent: anyrange <@ anyrange → boolean
+func (ro operator[T, S]) ContainedBy(first, second pgtype.Range[T]) (bool, error) {
+	// check the validity here so the errors refer to the operands in the right order
+	if !first.Valid {
+		return false, fmt.Errorf("first %w", ErrInvalidRange)
+	}
+	if !second.Valid {
+		return false, fmt.Errorf("second %w", ErrInvalidRange)
+	}
+	return ro.Contain(second, first)
+}
+
+// Is the element contained by the range?
+// PostgreSQL equivalent: anyelement <@ anyrange → boolean
+func (ro operator[T, S]) ElementContainedBy(elem T, r pgtype.Range[T]) (bool, error) {
+	return ro.ContainElement(r, elem)
+}
+
+// Do the ranges overlap, that is, have any elements in common?
+// PostgreSQL equivalent: anyrange && anyrange → boolean
+func (ro operator[T, S]) Overlap(first, second pgtype.Range[T]) (bool, error) {
+	if !first.Valid {
+		return false, fmt.Errorf("first %w", ErrInvalidRange)
+	}
+	if !second.Valid {
+		return false, fmt.Errorf("second %w", ErrInvalidRange)
+	}
+
+	firstEmpty, secondEmpty, err := ro.emptyBoth(first, second)
+	if err != nil {
+		return false, err
+	}
+	if firstEmpty || secondEmpty {
+		return false, nil
+	}
+
+	l1l2, l1u2, u1l2, _ := ro.compareAllBounds(ro.Rewrite(first), ro.Rewrite(second))
+	return boundsOverlap(l1l2, l1u2, u1l2), nil
+}
+
+// Is the first range strictly left of the second?
+// PostgreSQL equivalent: anyrange << anyrange → boolean
+func (ro operator[T, S]) LeftOf(first, second pgtype.Range[T]) (bool, error) {
+	if !first.Valid {
+		return false, fmt.Errorf("first %w", ErrInvalidRange)
+	}
+	if !second.Valid {
+		return false, fmt.Errorf("second %w", ErrInvalidRange)
+	}
+
+	firstEmpty, secondEmpty, err := ro.emptyBoth(first, second)
+	if err != nil {
+		return false, err
+	}
+	if firstEmpty || secondEmpty {
+		return false, nil
+	}
+
+	first = ro.Rewrite(first)
+	second = ro.Rewrite(second)
+
+	return ro.compareBounds(first, second, false, true) < 0, nil
+}
+
+// Is the first range strictly right of the second?
+// PostgreSQL equivalent: anyrange >> anyrange → boolean
+func (ro operator[T, S]) RightOf(first, second pgtype.Range[T]) (bool, error) {
+	return ro.LeftOf(second, first)
+}
+
+// Does the first range not extend to the right of the second? Like PostgreSQL the result is
+// false if either range is empty.
+// PostgreSQL equivalent: anyrange &< anyrange → boolean
+func (ro operator[T, S]) NotExtendRight(first, second pgtype.Range[T]) (bool, error) {
+	if !first.Valid {
+		return false, fmt.Errorf("first %w", ErrInvalidRange)
+	}
+	if !second.Valid {
+		return false, fmt.Errorf("second %w", ErrInvalidRange)
+	}
+
+	firstEmpty, secondEmpty, err := ro.emptyBoth(first, second)
+	if err != nil {
+		return false, err
+	}
+	if firstEmpty || secondEmpty {
+		return false, nil
+	}
+
+	first = ro.Rewrite(first)
+	second = ro.Rewrite(second)
+
+	return ro.compareBounds(first, second, false, false) <= 0, nil
+}
+
+// Does the first range not extend to the left of the second? Like PostgreSQL the result is
+// false if either range is empty.
+// PostgreSQL equivalent: anyrange &> anyrange → boolean
+func (ro operator[T, S]) NotExtendLeft(first, second pgtype.Range[T]) (bool, error) {
+	if !first.Valid {
+		return false, fmt.Errorf("first %w", ErrInvalidRange)
+	}
+	if !second.Valid {
+		return false, fmt.Errorf("second %w", ErrInvalidRange)
+	}
+
+	firstEmpty, secondEmpty, err := ro.emptyBoth(first, second)
+	if err != nil {
+		return false, err
+	}
+	if firstEmpty || secondEmpty {
+		return false, nil
+	}
+
+	first = ro.Rewrite(first)
+	second = ro.Rewrite(second)
+
+	return ro.compareBounds(first, second, true, true) >= 0, nil
+}
+
+// Are the ranges ordered without overlap, that is, do they not overlap and does the first range
+// not extend to the right of the second? For ranges that are not empty this is the same as
+// LeftOf, empty ranges are never ordered.
+func (ro operator[T, S]) StrictlyOrdered(first, second pgtype.Range[T]) (bool, error) {
+	overlap, err := ro.Overlap(first, second)
+	if err != nil || overlap {
+		return false, err
+	}
+	return ro.NotExtendRight(first, second)
+}
+
+// Are the ranges adjacent?
+// PostgreSQL equivalent: anyrange -|- anyrange → boolean
+func (ro operator[T, S]) Adjacent(first, second pgtype.Range[T]) (bool, error) {
+	if !first.Valid {
+		return false, fmt.Errorf("first %w", ErrInvalidRange)
+	}
+	if !second.Valid {
+		return false, fmt.Errorf("second %w", ErrInvalidRange)
+	}
+
+	firstEmpty, secondEmpty, err := ro.emptyBoth(first, second)
+	if err != nil {
+		return false, err
+	}
+	if firstEmpty || secondEmpty {
 		return false, nil
 	}
 
@@ -314,193 +1054,1103 @@ func (ro operator[T, S]) Adjacent(first, second pgtype.Range[T]) (bool, error) {
 		ro.cmp(first.Upper, second.Lower) == 0 {
 		return true, nil
 	}
-	if ((first.LowerType == pgtype.Inclusive && second.UpperType == pgtype.Exclusive) ||
-		(first.LowerType == pgtype.Exclusive && second.UpperType == pgtype.Inclusive)) &&
-		ro.cmp(first.Lower, second.Upper) == 0 {
-		return true, nil
+	if ((first.LowerType == pgtype.Inclusive && second.UpperType == pgtype.Exclusive) ||
+		(first.LowerType == pgtype.Exclusive && second.UpperType == pgtype.Inclusive)) &&
+		ro.cmp(first.Lower, second.Upper) == 0 {
+		return true, nil
+	}
+	return false, nil
+}
+
+// Do the ranges touch, that is, do they overlap or are they adjacent? Their union is a single
+// range if they do. Empty ranges don't touch any range.
+func (ro operator[T, S]) Touches(first, second pgtype.Range[T]) (bool, error) {
+	overlap, err := ro.Overlap(first, second)
+	if err != nil || overlap {
+		return overlap, err
+	}
+	return ro.Adjacent(first, second)
+}
+
+// Are the ranges adjacent when bounds that differ at most epsilon are treated as equal? This
+// makes adjacency of continuous ranges robust against rounding errors, for example [1.0,2.0) and
+// [2.0000001,3.0) are adjacent within 1e-5. Discrete ranges are compared exactly by Adjacent.
+func (ro operator[T, S]) AdjacentWithin(first, second pgtype.Range[T], epsilon S) (bool, error) {
+	if !first.Valid {
+		return false, fmt.Errorf("first %w", ErrInvalidRange)
+	}
+	if !second.Valid {
+		return false, fmt.Errorf("second %w", ErrInvalidRange)
+	}
+	if ro.discrete {
+		return false, fmt.Errorf("the range is not continuous")
+	}
+	if !(epsilon >= 0) {
+		return false, fmt.Errorf("epsilon %v is negative", epsilon)
+	}
+
+	firstEmpty, secondEmpty, err := ro.emptyBoth(first, second)
+	if err != nil {
+		return false, err
+	}
+	if firstEmpty || secondEmpty {
+		return false, nil
+	}
+
+	first = ro.Rewrite(first)
+	second = ro.Rewrite(second)
+
+	within := func(a, b T) bool {
+		d := ro.diff(a, b)
+		return d >= -epsilon && d <= epsilon
+	}
+	if ((first.UpperType == pgtype.Inclusive && second.LowerType == pgtype.Exclusive) ||
+		(first.UpperType == pgtype.Exclusive && second.LowerType == pgtype.Inclusive)) &&
+		within(first.Upper, second.Lower) {
+		return true, nil
+	}
+	if ((first.LowerType == pgtype.Inclusive && second.UpperType == pgtype.Exclusive) ||
+		(first.LowerType == pgtype.Exclusive && second.UpperType == pgtype.Inclusive)) &&
+		within(first.Lower, second.Upper) {
+		return true, nil
+	}
+	return false, nil
+}
+
+func (ro operator[T, S]) Union(first, second pgtype.Range[T]) (pgtype.Range[T], error) {
+	return ro.union(first, second, true)
+}
+
+func (ro operator[T, S]) Merge(first, second pgtype.Range[T]) (pgtype.Range[T], error) {
+	return ro.union(first, second, false)
+}
+
+// MergeInfo merges the ranges like Merge and reports if the ranges are contiguous, they overlap
+// or are adjacent. When they are not contiguous the result also spans the gap between them.
+func (ro operator[T, S]) MergeInfo(first, second pgtype.Range[T]) (pgtype.Range[T], bool, error) {
+	result, err := ro.Merge(first, second)
+	if err != nil {
+		return pgtype.Range[T]{}, false, err
+	}
+	contiguous, err := ro.Touches(first, second)
+	if err != nil {
+		return pgtype.Range[T]{}, false, err
+	}
+	return result, contiguous, nil
+}
+
+func (ro operator[T, S]) union(first, second pgtype.Range[T], strict bool) (pgtype.Range[T], error) {
+	if !first.Valid {
+		return pgtype.Range[T]{}, fmt.Errorf("first %w", ErrInvalidRange)
+	}
+	if !second.Valid {
+		return pgtype.Range[T]{}, fmt.Errorf("second %w", ErrInvalidRange)
+	}
+
+	firstEmpty, secondEmpty, err := ro.emptyBoth(first, second)
+	if err != nil {
+		return pgtype.Range[T]{}, err
+	}
+	if firstEmpty && secondEmpty {
+		return makeEmptyRange[T](), nil
+	}
+
+	first = ro.Rewrite(first)
+	second = ro.Rewrite(second)
+
+	if firstEmpty {
+		return second, nil
+	}
+	if secondEmpty {
+		return first, nil
+	}
+	// a full range covers the other range
+	if ro.IsFull(first) {
+		return first, nil
+	}
+	if ro.IsFull(second) {
+		return second, nil
+	}
+
+	touches, err := ro.Touches(first, second)
+	if err != nil {
+		return pgtype.Range[T]{}, err
+	}
+	if !touches && strict {
+		return pgtype.Range[T]{}, fmt.Errorf("range union: %w", ErrNotContiguous)
+	}
+
+	result := pgtype.Range[T]{
+		Valid: true,
+	}
+	result.Lower, result.LowerType = ro.extremeBound(first.Lower, first.LowerType, second.Lower, second.LowerType, true, true)
+	result.Upper, result.UpperType = ro.extremeBound(first.Upper, first.UpperType, second.Upper, second.UpperType, false, true)
+
+	return ro.Rewrite(result), nil
+}
+
+// Computes the intersection of the ranges.
+// PostgreSQL equivalent: anyrange * anyrange → anyrange
+func (ro operator[T, S]) Intersect(first, second pgtype.Range[T]) (pgtype.Range[T], error) {
+	if !first.Valid {
+		return pgtype.Range[T]{}, fmt.Errorf("first %w", ErrInvalidRange)
+	}
+	if !second.Valid {
+		return pgtype.Range[T]{}, fmt.Errorf("second %w", ErrInvalidRange)
+	}
+
+	firstEmpty, secondEmpty, err := ro.emptyBoth(first, second)
+	if err != nil {
+		return pgtype.Range[T]{}, err
+	}
+	if firstEmpty || secondEmpty {
+		return makeEmptyRange[T](), nil
+	}
+	// a full range doesn't restrict the other range
+	if ro.IsFull(first) {
+		return ro.Rewrite(second), nil
+	}
+	if ro.IsFull(second) {
+		return ro.Rewrite(first), nil
+	}
+
+	first = ro.Rewrite(first)
+	second = ro.Rewrite(second)
+
+	l1l2, l1u2, u1l2, _ := ro.compareAllBounds(first, second)
+	if !boundsOverlap(l1l2, l1u2, u1l2) {
+		return makeEmptyRange[T](), nil
+	}
+
+	result := pgtype.Range[T]{
+		Valid: true,
+	}
+	result.Lower, result.LowerType = ro.extremeBound(first.Lower, first.LowerType, second.Lower, second.LowerType, true, false)
+	result.Upper, result.UpperType = ro.extremeBound(first.Upper, first.UpperType, second.Upper, second.UpperType, false, false)
+
+	return ro.Rewrite(result), nil
+}
+
+func (ro operator[T, S]) Difference(first, second pgtype.Range[T]) (pgtype.Range[T], error) {
+	if !first.Valid {
+		return pgtype.Range[T]{}, fmt.Errorf("first %w", ErrInvalidRange)
+	}
+	if !second.Valid {
+		return pgtype.Range[T]{}, fmt.Errorf("second %w", ErrInvalidRange)
+	}
+
+	firstEmpty, secondEmpty, err := ro.emptyBoth(first, second)
+	if err != nil {
+		return pgtype.Range[T]{}, err
+	}
+	if firstEmpty {
+		return makeEmptyRange[T](), nil
+	}
+	if secondEmpty {
+		return ro.Rewrite(first), nil
+	}
+
+	first = ro.Rewrite(first)
+	second = ro.Rewrite(second)
+
+	l1l2, l1u2, u1l2, u1u2 := ro.compareAllBounds(first, second)
+
+	if l1l2 < 0 && u1u2 > 0 {
+		// cut in the middle
+		return pgtype.Range[T]{}, fmt.Errorf("range difference: %w", ErrNotContiguous)
+	}
+
+	if l1u2 > 0 || u1l2 < 0 {
+		// no overlap
+		return ro.Rewrite(first), nil
+	}
+
+	if l1l2 >= 0 && u1u2 <= 0 {
+		// at least full overlap
+		return makeEmptyRange[T](), nil
+	}
+
+	if l1l2 <= 0 && u1l2 >= 0 && u1u2 <= 0 {
+		inverseUpperType := pgtype.Exclusive
+		if second.LowerType == pgtype.Exclusive {
+			inverseUpperType = pgtype.Inclusive
+		}
+		return pgtype.Range[T]{
+			Lower:     first.Lower,
+			LowerType: first.LowerType,
+			Upper:     second.Lower,
+			UpperType: inverseUpperType,
+			Valid:     true,
+		}, nil
+	}
+
+	if l1l2 >= 0 && u1u2 >= 0 && l1u2 <= 0 {
+		inverseUpperType := pgtype.Exclusive
+		if second.UpperType == pgtype.Exclusive {
+			inverseUpperType = pgtype.Inclusive
+		}
+		return pgtype.Range[T]{
+			Lower:     second.Upper,
+			LowerType: inverseUpperType,
+			Upper:     first.Upper,
+			UpperType: first.UpperType,
+			Valid:     true,
+		}, nil
+	}
+
+	return pgtype.Range[T]{}, fmt.Errorf("unexpected case in range difference")
+}
+
+// Computes the range strictly between the ranges. The result is empty if the
+// ranges overlap or are adjacent.
+func (ro operator[T, S]) Gap(first, second pgtype.Range[T]) (pgtype.Range[T], error) {
+	if !first.Valid {
+		return pgtype.Range[T]{}, fmt.Errorf("first %w", ErrInvalidRange)
+	}
+	if !second.Valid {
+		return pgtype.Range[T]{}, fmt.Errorf("second %w", ErrInvalidRange)
+	}
+
+	first = ro.Rewrite(first)
+	second = ro.Rewrite(second)
+
+	firstEmpty, secondEmpty, err := ro.emptyBoth(first, second)
+	if err != nil {
+		return pgtype.Range[T]{}, err
+	}
+	overlap, err := ro.Overlap(first, second)
+	if err != nil {
+		return pgtype.Range[T]{}, err
+	}
+	adjacent, err := ro.Adjacent(first, second)
+	if err != nil {
+		return pgtype.Range[T]{}, err
+	}
+	if firstEmpty || secondEmpty || overlap || adjacent {
+		return makeEmptyRange[T](), nil
+	}
+
+	// make sure the first range is the left one
+	if ro.compareBounds(first, second, true, true) > 0 {
+		first, second = second, first
+	}
+	if first.UpperType == pgtype.Unbounded || second.LowerType == pgtype.Unbounded {
+		return pgtype.Range[T]{}, fmt.Errorf("inner bound of range gap: %w", ErrUnboundedRange)
+	}
+
+	return ro.Rewrite(pgtype.Range[T]{
+		Lower:     first.Upper,
+		LowerType: invertBoundType(first.UpperType),
+		Upper:     second.Lower,
+		UpperType: invertBoundType(second.LowerType),
+		Valid:     true,
+	}), nil
+}
+
+// Moves both bounds of the range by delta, the bound types are preserved and unbounded
+// sides are left untouched. A bound that would move past the limits of the type is an error
+// wrapping ErrOutOfRange.
+func (ro operator[T, S]) Shift(r pgtype.Range[T], delta S) (pgtype.Range[T], error) {
+	if !r.Valid {
+		return pgtype.Range[T]{}, ErrInvalidRange
+	}
+	if ro.add == nil {
+		return pgtype.Range[T]{}, ro.errNoAdd("shift")
+	}
+	if e, err := ro.Empty(r); err != nil {
+		return pgtype.Range[T]{}, err
+	} else if e {
+		return makeEmptyRange[T](), nil
+	}
+
+	if r.LowerType != pgtype.Unbounded {
+		lower := ro.add(r.Lower, delta)
+		if ro.wrapped(r.Lower, lower, delta) {
+			return pgtype.Range[T]{}, fmt.Errorf("shifted lower bound exceeds the bounds of the type: %w", ErrOutOfRange)
+		}
+		r.Lower = lower
+	}
+	if r.UpperType != pgtype.Unbounded {
+		upper := ro.add(r.Upper, delta)
+		if ro.wrapped(r.Upper, upper, delta) {
+			return pgtype.Range[T]{}, fmt.Errorf("shifted upper bound exceeds the bounds of the type: %w", ErrOutOfRange)
+		}
+		r.Upper = upper
+	}
+	return r, nil
+}
+
+// wrapped reports if moving a value by delta resulted in a value on the other side of it, that
+// is, if adding delta wrapped around
+func (ro operator[T, S]) wrapped(old, moved T, delta S) bool {
+	var zero S
+	c := ro.cmp(moved, old)
+	return (delta > zero && c < 0) || (delta < zero && c > 0)
+}
+
+// Moves the lower bound down and the upper bound up by amount, a negative amount
+// contracts the range. An empty range is returned if the range collapses, a bound that would
+// move past the limits of the type is an error wrapping ErrOutOfRange.
+func (ro operator[T, S]) Expand(r pgtype.Range[T], amount S) (pgtype.Range[T], error) {
+	if !r.Valid {
+		return pgtype.Range[T]{}, ErrInvalidRange
+	}
+	if ro.add == nil {
+		return pgtype.Range[T]{}, ro.errNoAdd("expand")
+	}
+	if e, err := ro.Empty(r); err != nil {
+		return pgtype.Range[T]{}, err
+	} else if e {
+		return makeEmptyRange[T](), nil
+	}
+
+	if r.LowerType != pgtype.Unbounded {
+		lower := ro.add(r.Lower, -amount)
+		if ro.wrapped(r.Lower, lower, -amount) {
+			return pgtype.Range[T]{}, fmt.Errorf("expanded range exceeds the bounds of the type: %w", ErrOutOfRange)
+		}
+		r.Lower = lower
+	}
+	if r.UpperType != pgtype.Unbounded {
+		upper := ro.add(r.Upper, amount)
+		if ro.wrapped(r.Upper, upper, amount) {
+			return pgtype.Range[T]{}, fmt.Errorf("expanded range exceeds the bounds of the type: %w", ErrOutOfRange)
+		}
+		r.Upper = upper
+	}
+	if e, err := ro.Empty(r); err != nil {
+		return pgtype.Range[T]{}, err
+	} else if e {
+		return makeEmptyRange[T](), nil
+	}
+	return r, nil
+}
+
+// Moves the lower bound up and the upper bound down by amount, this is the inverse of
+// Expand.
+func (ro operator[T, S]) Contract(r pgtype.Range[T], amount S) (pgtype.Range[T], error) {
+	return ro.Expand(r, -amount)
+}
+
+// Scales the range by factor while keeping its midpoint in place, a factor above 1 grows the
+// range and a factor below 1 shrinks it. The bounds are rounded down for integer difference
+// types. An empty range is returned if the range collapses.
+func (ro operator[T, S]) Scale(r pgtype.Range[T], factor float64) (pgtype.Range[T], error) {
+	if !r.Valid {
+		return pgtype.Range[T]{}, ErrInvalidRange
+	}
+	if ro.add == nil {
+		return pgtype.Range[T]{}, ro.errNoAdd("scale")
+	}
+	if !(factor >= 0) {
+		return pgtype.Range[T]{}, fmt.Errorf("scale factor should not be negative")
+	}
+	if r.LowerType == pgtype.Unbounded || r.UpperType == pgtype.Unbounded {
+		return pgtype.Range[T]{}, ErrUnboundedRange
+	}
+	if e, err := ro.Empty(r); err != nil {
+		return pgtype.Range[T]{}, err
+	} else if e {
+		return makeEmptyRange[T](), nil
+	}
+
+	r = ro.Rewrite(r)
+	// the new bounds are computed as offsets from the lower bound, the midpoint is at half the
+	// difference and the half-width is scaled by factor
+	d := float64(ro.diff(r.Upper, r.Lower))
+	lowerOffset, upperOffset := d/2-d*factor/2, d/2+d*factor/2
+	if !ro.fractional() {
+		lowerOffset, upperOffset = math.Floor(lowerOffset), math.Floor(upperOffset)
+	}
+	result := r
+	result.Lower = ro.add(r.Lower, S(lowerOffset))
+	result.Upper = ro.add(r.Lower, S(upperOffset))
+	// a grown range that doesn't contain the original bounds wrapped around
+	if factor > 1 && (ro.cmp(result.Lower, r.Lower) > 0 || ro.cmp(result.Upper, r.Upper) < 0) {
+		return pgtype.Range[T]{}, fmt.Errorf("scaled range exceeds the bounds of the type")
+	}
+	if e, err := ro.Empty(result); err != nil {
+		return pgtype.Range[T]{}, err
+	} else if e {
+		return makeEmptyRange[T](), nil
+	}
+	return result, nil
+}
+
+// Returns the value if the range contains it, otherwise the value of the range that is
+// closest to it. For continuous ranges there is no closest value to an exclusive bound.
+func (ro operator[T, S]) Clamp(r pgtype.Range[T], v T) (T, error) {
+	if !r.Valid {
+		return ro.zero, ErrInvalidRange
+	}
+	if e, err := ro.Empty(r); err != nil {
+		return ro.zero, err
+	} else if e {
+		return ro.zero, fmt.Errorf("clamp: %w", ErrEmptyUndefined)
+	}
+
+	r = ro.Rewrite(r)
+
+	if r.LowerType != pgtype.Unbounded {
+		c := ro.cmp(v, r.Lower)
+		if c < 0 || (c == 0 && r.LowerType == pgtype.Exclusive) {
+			if r.LowerType == pgtype.Exclusive {
+				return ro.zero, fmt.Errorf("cannot clamp to an exclusive lower bound")
+			}
+			return r.Lower, nil
+		}
+	}
+	if r.UpperType != pgtype.Unbounded {
+		c := ro.cmp(v, r.Upper)
+		if c > 0 || (c == 0 && r.UpperType == pgtype.Exclusive) {
+			if r.UpperType == pgtype.Inclusive {
+				return r.Upper, nil
+			}
+			if ro.discrete {
+				// the canonical upper bound is exclusive, the step before it is the last element
+				if ro.add == nil {
+					return ro.zero, ro.errNoAdd("clamp")
+				}
+				one := S(1)
+				return ro.add(r.Upper, -one), nil
+			}
+			return ro.zero, fmt.Errorf("cannot clamp to an exclusive upper bound")
+		}
+	}
+
+	return v, nil
+}
+
+// Returns the value halfway between the bounds of the canonicalized range. For a range that is
+// wider than S can represent, the midpoint is computed from the distances of both bounds to the
+// zero value of T instead, an error wrapping ErrOutOfRange is returned if those don't fit either.
+func (ro operator[T, S]) Midpoint(r pgtype.Range[T]) (T, error) {
+	if !r.Valid {
+		return ro.zero, ErrInvalidRange
+	}
+	if ro.add == nil {
+		return ro.zero, ro.errNoAdd("midpoint")
+	}
+	if r.LowerType == pgtype.Unbounded || r.UpperType == pgtype.Unbounded {
+		return ro.zero, ErrUnboundedRange
+	}
+	if e, err := ro.Empty(r); err != nil {
+		return ro.zero, err
+	} else if e {
+		return ro.zero, fmt.Errorf("midpoint: %w", ErrEmptyUndefined)
+	}
+
+	r = ro.Rewrite(r)
+	var zero S
+	if d := ro.diff(r.Upper, r.Lower); d >= zero {
+		return ro.add(r.Lower, d/2), nil
+	}
+	// the difference wrapped around, half of the distance of each bound to zero does fit
+	mid := ro.add(ro.zero, ro.diff(r.Lower, ro.zero)/2+ro.diff(r.Upper, ro.zero)/2)
+	if ro.cmp(mid, r.Lower) < 0 || ro.cmp(mid, r.Upper) > 0 {
+		return ro.zero, fmt.Errorf("midpoint: %w", ErrOutOfRange)
+	}
+	return mid, nil
+}
+
+// Splits the range into n contiguous and adjacent subranges of (nearly) equal size, the
+// union of the subranges equals the range.
+func (ro operator[T, S]) Partition(r pgtype.Range[T], n int) ([]pgtype.Range[T], error) {
+	if !r.Valid {
+		return nil, ErrInvalidRange
+	}
+	if ro.add == nil {
+		return nil, ro.errNoAdd("partition")
+	}
+	if n < 1 {
+		return nil, fmt.Errorf("number of partitions should be at least 1")
+	}
+	if r.LowerType == pgtype.Unbounded || r.UpperType == pgtype.Unbounded {
+		return nil, ErrUnboundedRange
+	}
+	if e, err := ro.Empty(r); err != nil {
+		return nil, err
+	} else if e {
+		return nil, fmt.Errorf("partition: %w", ErrEmptyUndefined)
+	}
+
+	r = ro.Rewrite(r)
+	d := ro.diff(r.Upper, r.Lower)
+	if d < S(n) && !ro.fractional() {
+		return nil, fmt.Errorf("the range is too small for %d partitions", n)
+	}
+
+	// the split points are calculated as lower + d*i/n without overflowing d*i
+	quotient := d / S(n)
+	remainder := d - quotient*S(n)
+	result := make([]pgtype.Range[T], n)
+	lower, lowerType := r.Lower, r.LowerType
+	for i := 1; i <= n; i++ {
+		upper, upperType := r.Upper, r.UpperType
+		if i < n {
+			upper = ro.add(r.Lower, quotient*S(i)+remainder*S(i)/S(n))
+			upperType = pgtype.Exclusive
+		}
+		result[i-1] = pgtype.Range[T]{
+			Lower:     lower,
+			LowerType: lowerType,
+			Upper:     upper,
+			UpperType: upperType,
+			Valid:     true,
+		}
+		lower, lowerType = upper, pgtype.Inclusive
+	}
+	return result, nil
+}
+
+// Creates windows of the given size that start at the lower bound of the range and advance by
+// step, every window includes its lower bound and excludes its upper bound. Windows that extend
+// beyond the range are clipped to its upper bound. Windows overlap when step is smaller than
+// size and leave gaps when step is larger than size.
+func (ro operator[T, S]) Windows(r pgtype.Range[T], size, step S) ([]pgtype.Range[T], error) {
+	if !r.Valid {
+		return nil, ErrInvalidRange
+	}
+	if ro.add == nil {
+		return nil, ro.errNoAdd("windows")
+	}
+	if !(size > 0) {
+		return nil, fmt.Errorf("window size %v is not positive", size)
+	}
+	if !(step > 0) {
+		return nil, fmt.Errorf("window step %v is not positive", step)
+	}
+	if r.LowerType == pgtype.Unbounded || r.UpperType == pgtype.Unbounded {
+		return nil, ErrUnboundedRange
+	}
+	if e, err := ro.Empty(r); err != nil {
+		return nil, err
+	} else if e {
+		return nil, fmt.Errorf("windows: %w", ErrEmptyUndefined)
+	}
+
+	r = ro.Rewrite(r)
+	var result []pgtype.Range[T]
+	lower, lowerType := r.Lower, r.LowerType
+	for {
+		window := pgtype.Range[T]{Lower: lower, LowerType: lowerType, Upper: ro.add(lower, size), UpperType: pgtype.Exclusive, Valid: true}
+		// an upper bound that wrapped around is beyond the range as well
+		if ro.cmp(window.Upper, lower) <= 0 ||
+			ro.compareBoundValues(window.Upper, window.UpperType, false, r.Upper, r.UpperType, false) > 0 {
+			window.Upper, window.UpperType = r.Upper, r.UpperType
+		}
+		if e, err := ro.Empty(window); err != nil {
+			return nil, err
+		} else if e {
+			// the window starts beyond the range
+			break
+		}
+		result = append(result, window)
+
+		next := ro.add(lower, step)
+		if ro.cmp(next, lower) <= 0 {
+			break
+		}
+		lower, lowerType = next, pgtype.Inclusive
+	}
+	return result, nil
+}
+
+// Splits the range at the value into the part before and the part from the value, the
+// value is excluded from the first part and included in the second part. The range is
+// returned as is when the value is not strictly inside it.
+func (ro operator[T, S]) SplitAt(r pgtype.Range[T], at T) ([]pgtype.Range[T], error) {
+	if e, err := ro.Empty(r); err != nil {
+		return nil, err
+	} else if e {
+		return nil, fmt.Errorf("split at: %w", ErrEmptyUndefined)
+	}
+
+	contain, err := ro.ContainElement(r, at)
+	if err != nil {
+		return nil, err
+	}
+	if !contain {
+		return []pgtype.Range[T]{r}, nil
 	}
-	return false, nil
+	// a value on an inclusive bound is not strictly inside the range
+	if (r.LowerType != pgtype.Unbounded && ro.cmp(at, r.Lower) == 0) || (r.UpperType != pgtype.Unbounded && ro.cmp(at, r.Upper) == 0) {
+		return []pgtype.Range[T]{r}, nil
+	}
+
+	before := r
+	before.Upper, before.UpperType = at, pgtype.Exclusive
+	// the value is the first element of the range
+	if e, err := ro.Empty(before); err != nil {
+		return nil, err
+	} else if e {
+		return []pgtype.Range[T]{r}, nil
+	}
+	after := r
+	after.Lower, after.LowerType = at, pgtype.Inclusive
+	return []pgtype.Range[T]{before, after}, nil
 }
 
-func (ro operator[T, S]) Union(first, second pgtype.Range[T]) (pgtype.Range[T], error) {
-	return ro.union(first, second, true)
+// Returns an iterator over all the elements of a discrete range, from the canonical
+// lower bound up to but excluding the canonical upper bound. An upper bound at the maximum
+// value of the type stays inclusive and is the last element.
+func (ro operator[T, S]) Elements(r pgtype.Range[T]) (iter.Seq[T], error) {
+	if ro.IsZero() {
+		return nil, ErrUninitialized
+	}
+	if !r.Valid {
+		return nil, ErrInvalidRange
+	}
+	if !ro.discrete {
+		return nil, fmt.Errorf("the range is not discrete")
+	}
+	if r.LowerType == pgtype.Unbounded || r.UpperType == pgtype.Unbounded {
+		return nil, ErrUnboundedRange
+	}
+	if e, err := ro.Empty(r); err != nil {
+		return nil, err
+	} else if e {
+		return func(yield func(T) bool) {}, nil
+	}
+
+	r = ro.Rewrite(r)
+	return func(yield func(T) bool) {
+		for v := r.Lower; ; v = ro.addOne(v) {
+			c := ro.cmp(v, r.Upper)
+			if c > 0 || (c == 0 && r.UpperType == pgtype.Exclusive) {
+				return
+			}
+			// stop at an inclusive upper bound, the next value would wrap around
+			if !yield(v) || c == 0 {
+				return
+			}
+		}
+	}, nil
 }
 
-func (ro operator[T, S]) Merge(first, second pgtype.Range[T]) (pgtype.Range[T], error) {
-	return ro.union(first, second, false)
+// Converts a discrete range to the closed form [a,b] where both bounds are inclusive, the
+// inverse of ToHalfOpen. Unbounded and empty ranges have no closed form.
+func (ro operator[T, S]) ToClosed(r pgtype.Range[T]) (pgtype.Range[T], error) {
+	r, err := ro.ToHalfOpen(r)
+	if err != nil {
+		return pgtype.Range[T]{}, err
+	}
+	if ro.add == nil {
+		return pgtype.Range[T]{}, ro.errNoAdd("to closed")
+	}
+	one := S(1)
+	r.Upper, r.UpperType = ro.add(r.Upper, -one), pgtype.Inclusive
+	return r, nil
 }
 
-func (ro operator[T, S]) union(first, second pgtype.Range[T], strict bool) (pgtype.Range[T], error) {
-	if !first.Valid {
-		return pgtype.Range[T]{}, fmt.Errorf("first range is not valid")
+// Converts a discrete range to the half-open form [a,b) where the lower bound is inclusive and the
+// upper bound exclusive, this is the canonical form. Unbounded and empty ranges have no half-open
+// form and neither does a range that includes the maximum value of the type.
+func (ro operator[T, S]) ToHalfOpen(r pgtype.Range[T]) (pgtype.Range[T], error) {
+	if ro.IsZero() {
+		return pgtype.Range[T]{}, ErrUninitialized
 	}
-	if !second.Valid {
-		return pgtype.Range[T]{}, fmt.Errorf("second range is not valid")
+	if !r.Valid {
+		return pgtype.Range[T]{}, ErrInvalidRange
+	}
+	if !ro.discrete {
+		return pgtype.Range[T]{}, fmt.Errorf("the range is not discrete")
+	}
+	if r.LowerType == pgtype.Unbounded || r.UpperType == pgtype.Unbounded {
+		return pgtype.Range[T]{}, ErrUnboundedRange
+	}
+	if e, err := ro.Empty(r); err != nil {
+		return pgtype.Range[T]{}, err
+	} else if e {
+		return pgtype.Range[T]{}, fmt.Errorf("half-open: %w", ErrEmptyUndefined)
 	}
 
-	first = ro.Rewrite(first)
-	second = ro.Rewrite(second)
+	r = ro.Rewrite(r)
+	if r.UpperType != pgtype.Exclusive {
+		return pgtype.Range[T]{}, fmt.Errorf("the upper bound %v has no next value", r.Upper)
+	}
+	return r, nil
+}
 
-	firstEmpty, _ := ro.Empty(first)
-	secondEmpty, _ := ro.Empty(second)
-	if firstEmpty && secondEmpty {
-		return makeEmptyRange[T](), nil
+// Calls fn for every value of the range starting at the canonical lower bound and advancing by
+// stride, the iteration stops at the first error returned by fn and that error is returned. The
+// iteration also stops when advancing doesn't give a greater value, at the maximum value of the
+// type or when the stride is too small to change a large value.
+func (ro operator[T, S]) ForEachStep(r pgtype.Range[T], stride S, fn func(T) error) error {
+	if !(stride > 0) {
+		return fmt.Errorf("stride %v is not positive", stride)
 	}
-	if firstEmpty {
-		return second, nil
+	if ro.add == nil {
+		return ro.errNoAdd("for each step")
 	}
-	if secondEmpty {
-		return first, nil
+	if e, err := ro.Empty(r); err != nil {
+		return err
+	} else if e {
+		return fmt.Errorf("for each step: %w", ErrEmptyUndefined)
+	}
+	r = ro.Rewrite(r)
+	if r.LowerType == pgtype.Unbounded || r.UpperType == pgtype.Unbounded {
+		return fmt.Errorf("for each step: %w", ErrUnboundedRange)
 	}
 
-	overlap, _ := ro.Overlap(first, second)
-	adjacent, _ := ro.Adjacent(first, second)
-	if !overlap && !adjacent && strict {
-		return pgtype.Range[T]{}, fmt.Errorf("result of range union would not be contiguous")
+	v := r.Lower
+	if r.LowerType == pgtype.Exclusive {
+		next := ro.add(v, stride)
+		if ro.cmp(next, v) <= 0 {
+			return nil
+		}
+		v = next
+	}
+	for ro.compareBoundValues(v, pgtype.Inclusive, true, r.Upper, r.UpperType, false) <= 0 {
+		if err := fn(v); err != nil {
+			return err
+		}
+		// the next value wrapped around at the maximum of the type or the stride is too small to
+		// change the value
+		next := ro.add(v, stride)
+		if ro.cmp(next, v) <= 0 {
+			break
+		}
+		v = next
 	}
+	return nil
+}
 
-	result := pgtype.Range[T]{
-		Valid: true,
+// Computes the distance between the canonical lower bound of the range and the value, for
+// discrete ranges this is the index of the value in the range.
+func (ro operator[T, S]) Offset(r pgtype.Range[T], v T) (S, error) {
+	var zero S
+	contains, err := ro.ContainElement(r, v)
+	if err != nil {
+		return zero, err
 	}
-	if ro.compareBounds(first, second, true, true) < 0 {
-		result.Lower = first.Lower
-		result.LowerType = first.LowerType
-	} else {
-		result.Lower = second.Lower
-		result.LowerType = second.LowerType
+	if !contains {
+		return zero, fmt.Errorf("offset of %v: %w", v, ErrOutOfRange)
 	}
-	if ro.compareBounds(first, second, false, false) > 0 {
-		result.Upper = first.Upper
-		result.UpperType = first.UpperType
-	} else {
-		result.Upper = second.Upper
-		result.UpperType = second.UpperType
+	if r.LowerType == pgtype.Unbounded {
+		return zero, fmt.Errorf("offset lower bound: %w", ErrUnboundedRange)
 	}
+	return ro.diff(v, ro.Rewrite(r).Lower), nil
+}
 
-	return ro.Rewrite(result), nil
+// Computes the distance between the nearest bounds of the ranges, the distance is zero if
+// the ranges overlap or are adjacent.
+func (ro operator[T, S]) Distance(first, second pgtype.Range[T]) (S, error) {
+	var zero S
+	firstEmpty, secondEmpty, err := ro.emptyBoth(first, second)
+	if err != nil {
+		return zero, err
+	}
+	if firstEmpty || secondEmpty {
+		return zero, fmt.Errorf("distance: %w", ErrEmptyUndefined)
+	}
+
+	gap, err := ro.Gap(first, second)
+	if err != nil {
+		return zero, err
+	}
+	if e, err := ro.Empty(gap); err != nil {
+		return zero, err
+	} else if e {
+		return zero, nil
+	}
+	return ro.diff(gap.Upper, gap.Lower), nil
 }
 
-// Computes the intersection of the ranges.
-// PostgreSQL equivalent: anyrange * anyrange → anyrange
-func (ro operator[T, S]) Intersect(first, second pgtype.Range[T]) (pgtype.Range[T], error) {
-	if !first.Valid {
-		return pgtype.Range[T]{}, fmt.Errorf("first range is not valid")
+// Computes the size of the intersection of the ranges, the size is zero if the ranges
+// don't overlap.
+func (ro operator[T, S]) OverlapSize(first, second pgtype.Range[T]) (S, error) {
+	var zero S
+	intersect, err := ro.Intersect(first, second)
+	if err != nil {
+		return zero, err
 	}
-	if !second.Valid {
-		return pgtype.Range[T]{}, fmt.Errorf("second range is not valid")
+	if e, err := ro.Empty(intersect); err != nil {
+		return zero, err
+	} else if e {
+		return zero, nil
 	}
+	return ro.Size(intersect)
+}
 
-	first = ro.Rewrite(first)
-	second = ro.Rewrite(second)
+// Computes the Jaccard similarity of the ranges, that is the size of the intersection
+// divided by the size of the union. Identical ranges have a similarity of 1, disjoint
+// ranges a similarity of 0.
+func (ro operator[T, S]) Jaccard(first, second pgtype.Range[T]) (float64, error) {
+	intersect, err := ro.Intersect(first, second)
+	if err != nil {
+		return 0, err
+	}
 
-	firstEmpty, _ := ro.Empty(first)
-	secondEmpty, _ := ro.Empty(second)
-	overlap, _ := ro.Overlap(first, second)
-	if firstEmpty || secondEmpty || !overlap {
-		return makeEmptyRange[T](), nil
+	var sizes [3]S
+	for i, r := range []pgtype.Range[T]{first, second, intersect} {
+		if e, err := ro.Empty(r); err != nil {
+			return 0, err
+		} else if e {
+			continue
+		}
+		if sizes[i], err = ro.Size(r); err != nil {
+			return 0, err
+		}
 	}
 
-	result := pgtype.Range[T]{
-		Valid: true,
+	union := float64(sizes[0]) + float64(sizes[1]) - float64(sizes[2])
+	if union == 0 {
+		return 0, fmt.Errorf("jaccard: %w", ErrEmptyUndefined)
 	}
-	if ro.compareBounds(first, second, true, true) >= 0 {
-		result.Lower = first.Lower
-		result.LowerType = first.LowerType
-	} else {
-		result.Lower = second.Lower
-		result.LowerType = second.LowerType
+	return float64(sizes[2]) / union, nil
+}
+
+// Computes the fraction of target that is covered by the ranges, from 0 for no coverage to 1
+// for full coverage. The ranges are merged first so overlapping ranges are not counted twice.
+// The coverage of an unbounded or empty target is undefined and an error.
+func (ro operator[T, S]) Coverage(target pgtype.Range[T], rs []pgtype.Range[T]) (float64, error) {
+	if !target.Valid {
+		return 0, fmt.Errorf("target %w", ErrInvalidRange)
 	}
-	if ro.compareBounds(first, second, false, false) <= 0 {
-		result.Upper = first.Upper
-		result.UpperType = first.UpperType
-	} else {
-		result.Upper = second.Upper
-		result.UpperType = second.UpperType
+	if e, err := ro.Empty(target); err != nil {
+		return 0, err
+	} else if e {
+		return 0, fmt.Errorf("coverage: %w", ErrEmptyUndefined)
+	}
+	total, err := ro.Size(target)
+	if err != nil {
+		return 0, fmt.Errorf("coverage: %w", err)
+	}
+	merged, err := ro.MergeOverlapping(rs)
+	if err != nil {
+		return 0, err
 	}
 
-	return ro.Rewrite(result), nil
+	var covered float64
+	for _, r := range merged {
+		s, err := ro.OverlapSize(target, r)
+		if err != nil {
+			return 0, err
+		}
+		covered += float64(s)
+	}
+	return covered / float64(total), nil
 }
 
-func (ro operator[T, S]) Difference(first, second pgtype.Range[T]) (pgtype.Range[T], error) {
-	if !first.Valid {
-		return pgtype.Range[T]{}, fmt.Errorf("first range is not valid")
+// Merges the ranges and clips them to bound. Returns the merged ranges that cover parts of
+// bound and the gaps within bound that are not covered, both ordered from low to high. A gap
+// that would extend to an unbounded side of bound can not be enumerated and is an error.
+func (ro operator[T, S]) Coalesce(bound pgtype.Range[T], rs []pgtype.Range[T]) ([]pgtype.Range[T], []pgtype.Range[T], error) {
+	if !bound.Valid {
+		return nil, nil, ErrInvalidRange
 	}
-	if !second.Valid {
-		return pgtype.Range[T]{}, fmt.Errorf("second range is not valid")
+	merged, err := ro.MergeOverlapping(rs)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	firstEmpty, _ := ro.Empty(first)
-	secondEmpty, _ := ro.Empty(second)
-	if firstEmpty {
-		return makeEmptyRange[T](), nil
-	}
-	if secondEmpty {
-		return ro.Rewrite(first), nil
+	var covered []pgtype.Range[T]
+	for _, r := range merged {
+		c, err := ro.Intersect(bound, r)
+		if err != nil {
+			return nil, nil, err
+		}
+		if e, err := ro.Empty(c); err != nil {
+			return nil, nil, err
+		} else if !e {
+			covered = append(covered, c)
+		}
 	}
 
-	first = ro.Rewrite(first)
-	second = ro.Rewrite(second)
+	var gaps []pgtype.Range[T]
+	addGap := func(gap pgtype.Range[T]) error {
+		gap = ro.Rewrite(gap)
+		if e, err := ro.Empty(gap); err != nil || e {
+			return err
+		}
+		if gap.LowerType == pgtype.Unbounded || gap.UpperType == pgtype.Unbounded {
+			return fmt.Errorf("coalesce gap: %w", ErrUnboundedRange)
+		}
+		gaps = append(gaps, gap)
+		return nil
+	}
 
-	l1l2 := ro.compareBounds(first, second, true, true)
-	l1u2 := ro.compareBounds(first, second, true, false)
-	u1l2 := ro.compareBounds(first, second, false, true)
-	u1u2 := ro.compareBounds(first, second, false, false)
+	if e, err := ro.Empty(bound); err != nil || e {
+		return covered, nil, err
+	}
+	// next is the part of bound that is not yet covered and not yet reported as a gap
+	next := ro.Rewrite(bound)
+	for _, c := range covered {
+		if c.LowerType != pgtype.Unbounded {
+			gap := next
+			gap.Upper, gap.UpperType = c.Lower, invertBoundType(c.LowerType)
+			if err := addGap(gap); err != nil {
+				return nil, nil, err
+			}
+		}
+		if c.UpperType == pgtype.Unbounded {
+			return covered, gaps, nil
+		}
+		next.Lower, next.LowerType = c.Upper, invertBoundType(c.UpperType)
+	}
+	if err := addGap(next); err != nil {
+		return nil, nil, err
+	}
+	return covered, gaps, nil
+}
 
-	if l1l2 < 0 && u1u2 > 0 {
-		// cut in the middle
-		return pgtype.Range[T]{}, fmt.Errorf("result of range difference would not be contiguous")
+// Computes the parts of universe that are not covered by the range, that is zero, one or two
+// ranges ordered from low to high. The range is clipped to universe, but it can not be unbounded
+// on a side where universe is bounded.
+func (ro operator[T, S]) Complement(universe, r pgtype.Range[T]) ([]pgtype.Range[T], error) {
+	if !universe.Valid {
+		return nil, fmt.Errorf("first %w", ErrInvalidRange)
+	}
+	if !r.Valid {
+		return nil, fmt.Errorf("second %w", ErrInvalidRange)
+	}
+	if (r.LowerType == pgtype.Unbounded && universe.LowerType != pgtype.Unbounded) ||
+		(r.UpperType == pgtype.Unbounded && universe.UpperType != pgtype.Unbounded) {
+		return nil, fmt.Errorf("range complement: %w", ErrUnboundedRange)
 	}
 
-	if l1u2 > 0 || u1l2 < 0 {
-		// no overlap
-		return ro.Rewrite(first), nil
+	universe = ro.Rewrite(universe)
+	if e, err := ro.Empty(universe); err != nil || e {
+		return nil, err
+	}
+	r, err := ro.Intersect(universe, r)
+	if err != nil {
+		return nil, err
+	}
+	if e, err := ro.Empty(r); err != nil {
+		return nil, err
+	} else if e {
+		return []pgtype.Range[T]{universe}, nil
 	}
 
-	if l1l2 >= 0 && u1u2 <= 0 {
-		// at least full overlap
-		return makeEmptyRange[T](), nil
+	// there is no piece on a side where the range extends to the end of universe
+	var pieces []pgtype.Range[T]
+	if r.LowerType != pgtype.Unbounded {
+		pieces = append(pieces, pgtype.Range[T]{Lower: universe.Lower, LowerType: universe.LowerType, Upper: r.Lower, UpperType: invertBoundType(r.LowerType), Valid: true})
+	}
+	if r.UpperType != pgtype.Unbounded {
+		pieces = append(pieces, pgtype.Range[T]{Lower: r.Upper, LowerType: invertBoundType(r.UpperType), Upper: universe.Upper, UpperType: universe.UpperType, Valid: true})
 	}
 
-	if l1l2 <= 0 && u1l2 >= 0 && u1u2 <= 0 {
-		inverseUpperType := pgtype.Exclusive
-		if second.LowerType == pgtype.Exclusive {
-			inverseUpperType = pgtype.Inclusive
+	var result []pgtype.Range[T]
+	for _, piece := range pieces {
+		piece = ro.Rewrite(piece)
+		if e, err := ro.Empty(piece); err != nil {
+			return nil, err
+		} else if !e {
+			result = append(result, piece)
 		}
-		return pgtype.Range[T]{
-			Lower:     first.Lower,
-			LowerType: first.LowerType,
-			Upper:     second.Lower,
-			UpperType: inverseUpperType,
-			Valid:     true,
-		}, nil
 	}
+	return result, nil
+}
 
-	if l1l2 >= 0 && u1u2 >= 0 && l1u2 <= 0 {
-		inverseUpperType := pgtype.Exclusive
-		if second.UpperType == pgtype.Exclusive {
-			inverseUpperType = pgtype.Inclusive
+// Computes an FNV-1a hash of the canonicalized range, so ranges that are equal have the same
+// hash. The bound values are hashed using their MarshalBinary method if they have one, their
+// fixed-size binary representation if there is one and their default format otherwise. Values
+// that compare equal but have a different representation, like time.Time values in different
+// locations, are first mapped to the same key by the operators of this package. Custom operators
+// can set such a key with WithHashKey.
+func (ro operator[T, S]) Hash(r pgtype.Range[T]) (uint64, error) {
+	if _, err := ro.Empty(r); err != nil {
+		return 0, err
+	}
+	r = ro.Rewrite(r)
+
+	h := fnv.New64a()
+	for _, bound := range []struct {
+		value     T
+		boundType pgtype.BoundType
+	}{
+		{value: r.Lower, boundType: r.LowerType},
+		{value: r.Upper, boundType: r.UpperType},
+	} {
+		h.Write([]byte{byte(bound.boundType)})
+		if bound.boundType == pgtype.Unbounded || bound.boundType == pgtype.Empty {
+			continue
+		}
+		var key any = bound.value
+		if ro.hashKey != nil {
+			key = ro.hashKey(bound.value)
+		}
+		if err := writeHashValue(h, key); err != nil {
+			return 0, fmt.Errorf("range hash: %w", err)
 		}
-		return pgtype.Range[T]{
-			Lower:     second.Upper,
-			LowerType: inverseUpperType,
-			Upper:     first.Upper,
-			UpperType: first.UpperType,
-			Valid:     true,
-		}, nil
 	}
+	return h.Sum64(), nil
+}
 
-	return pgtype.Range[T]{}, fmt.Errorf("unexpected case in range difference")
+// Creates the range [v,v] that contains only v.
+func (ro operator[T, S]) Singleton(v T) pgtype.Range[T] {
+	return pgtype.Range[T]{
+		Lower:     v,
+		LowerType: pgtype.Inclusive,
+		Upper:     v,
+		UpperType: pgtype.Inclusive,
+		Valid:     true,
+	}
+}
+
+// Creates a range from two elements with the given bound types, the range is checked with
+// Validate, so an error is returned when the lower bound is greater than the upper bound. The
+// value of an unbounded side is ignored.
+func (ro operator[T, S]) Between(lower, upper T, lowerType, upperType pgtype.BoundType) (pgtype.Range[T], error) {
+	r := pgtype.Range[T]{Lower: lower, LowerType: lowerType, Upper: upper, UpperType: upperType, Valid: true}
+	if lowerType == pgtype.Unbounded {
+		r.Lower = ro.zero
+	}
+	if upperType == pgtype.Unbounded {
+		r.Upper = ro.zero
+	}
+	if err := ro.Validate(r); err != nil {
+		return pgtype.Range[T]{}, err
+	}
+	return r, nil
+}
+
+// Checks that the range is valid, that the lower bound is not greater than the upper bound
+// and that an empty bound type is used on both sides or on neither side.
+func (ro operator[T, S]) Validate(r pgtype.Range[T]) error {
+	if !r.Valid {
+		return ErrInvalidRange
+	}
+	r, err := ro.normalizeSpecial(r)
+	if err != nil {
+		return err
+	}
+	if (r.LowerType == pgtype.Empty) != (r.UpperType == pgtype.Empty) {
+		return fmt.Errorf("empty bound type on one side only: %w", ErrMalformedRange)
+	}
+	if r.LowerType == pgtype.Empty {
+		return nil
+	}
+	if r.LowerType != pgtype.Unbounded && r.UpperType != pgtype.Unbounded && ro.cmp(r.Lower, r.Upper) > 0 {
+		return fmt.Errorf("lower bound %v is greater than upper bound %v: %w", r.Lower, r.Upper, ErrMalformedRange)
+	}
+	return nil
 }
 
 func (ro operator[T, S]) Size(r pgtype.Range[T]) (S, error) {
+	if ro.IsZero() {
+		var size S
+		return size, ErrUninitialized
+	}
 	if !r.Valid {
-		return ro.diff(ro.zero, ro.zero), fmt.Errorf("the range is not valid")
+		return ro.diff(ro.zero, ro.zero), ErrInvalidRange
+	}
+	r, err := ro.normalizeSpecial(r)
+	if err != nil {
+		return ro.diff(ro.zero, ro.zero), err
 	}
 
 	if r.LowerType == pgtype.Unbounded || r.UpperType == pgtype.Unbounded {
-		return ro.diff(ro.zero, ro.zero), fmt.Errorf("the range is unbounded")
+		return ro.diff(ro.zero, ro.zero), ErrUnboundedRange
 	}
 	diff := ro.diff(r.Upper, r.Lower)
+	// a fractional size of a continuous range is a length, the bound types don't change it
+	if ro.fractional() && !ro.discrete {
+		return diff, nil
+	}
 	if r.LowerType == pgtype.Inclusive && r.UpperType == pgtype.Inclusive {
 		return diff + 1, nil
 	}
@@ -510,17 +2160,72 @@ func (ro operator[T, S]) Size(r pgtype.Range[T]) (S, error) {
 	return diff, nil
 }
 
+// SizeBig is like Size but the result is not limited to S, so the size of very wide ranges
+// doesn't wrap around. This relies on diff wrapping around on overflow like the built-in
+// integer types do, diff functions that saturate instead (like time.Time.Sub) can not be
+// corrected. SizeBig is not defined for fractional difference types.
+func (ro operator[T, S]) SizeBig(r pgtype.Range[T]) (*big.Int, error) {
+	if ro.IsZero() {
+		return nil, ErrUninitialized
+	}
+	if !r.Valid {
+		return nil, ErrInvalidRange
+	}
+	if ro.fractional() {
+		return nil, fmt.Errorf("size of type %T is not an integer", *new(S))
+	}
+	if r.LowerType == pgtype.Unbounded || r.UpperType == pgtype.Unbounded {
+		return nil, ErrUnboundedRange
+	}
+
+	var zero S
+	diff := ro.diff(r.Upper, r.Lower)
+	result := new(big.Int)
+	if zero-1 < zero {
+		result.SetInt64(int64(diff))
+		// the upper bound is greater than the lower bound, so a negative difference has wrapped around
+		if diff < 0 && ro.cmp(r.Upper, r.Lower) > 0 {
+			result.Add(result, new(big.Int).Lsh(big.NewInt(1), uint(unsafe.Sizeof(diff))*8))
+		}
+	} else {
+		result.SetUint64(uint64(diff))
+	}
+
+	if r.LowerType == pgtype.Inclusive && r.UpperType == pgtype.Inclusive {
+		result.Add(result, big.NewInt(1))
+	}
+	if r.LowerType == pgtype.Exclusive && r.UpperType == pgtype.Exclusive {
+		result.Sub(result, big.NewInt(1))
+	}
+	return result, nil
+}
+
 // Rewrite converts all bounded ranges to the form [ , )
 func (ro operator[T, S]) Rewrite(r pgtype.Range[T]) pgtype.Range[T] {
+	if ro.IsZero() {
+		panic(ErrUninitialized)
+	}
+	// a range with a NaN bound is returned as is, Empty reports the error to the callers
+	if normalized, err := ro.normalizeSpecial(r); err == nil {
+		r = normalized
+	}
+	r = normalizeEmpty(r)
+	// a bound at the maximum value of the type has no next value, PostgreSQL reports an out of
+	// range error for it, the bound is kept as is instead of wrapping around
 	if r.LowerType == pgtype.Exclusive && ro.discrete {
-		r.Lower = ro.addOne(r.Lower)
-		r.LowerType = pgtype.Inclusive
+		if next := ro.addOne(r.Lower); ro.cmp(next, r.Lower) > 0 {
+			r.Lower = next
+			r.LowerType = pgtype.Inclusive
+		}
 	}
 	if r.UpperType == pgtype.Inclusive && ro.discrete {
-		r.Upper = ro.addOne(r.Upper)
-		r.UpperType = pgtype.Exclusive
+		if next := ro.addOne(r.Upper); ro.cmp(next, r.Upper) > 0 {
+			r.Upper = next
+			r.UpperType = pgtype.Exclusive
+		}
 	}
 
+	// an invalid range is returned as is, callers check the validity themselves
 	if e, _ := ro.Empty(r); e {
 		return makeEmptyRange[T]()
 	}
@@ -528,10 +2233,64 @@ func (ro operator[T, S]) Rewrite(r pgtype.Range[T]) pgtype.Range[T] {
 	return r
 }
 
+// normalizeSpecial turns an infinite lower or upper bound into an unbounded bound, a bound that is
+// not a number is an error
+func (ro operator[T, S]) normalizeSpecial(r pgtype.Range[T]) (pgtype.Range[T], error) {
+	// every operation that checks its operands passes here first
+	if ro.IsZero() {
+		return r, ErrUninitialized
+	}
+	if ro.special == nil || !r.Valid {
+		return r, nil
+	}
+	if r.LowerType == pgtype.Inclusive || r.LowerType == pgtype.Exclusive {
+		nan, inf := ro.special(r.Lower)
+		if nan {
+			return r, fmt.Errorf("lower %w", ErrNaN)
+		}
+		if inf < 0 {
+			r.Lower = ro.zero
+			r.LowerType = pgtype.Unbounded
+		}
+	}
+	if r.UpperType == pgtype.Inclusive || r.UpperType == pgtype.Exclusive {
+		nan, inf := ro.special(r.Upper)
+		if nan {
+			return r, fmt.Errorf("upper %w", ErrNaN)
+		}
+		if inf > 0 {
+			r.Upper = ro.zero
+			r.UpperType = pgtype.Unbounded
+		}
+	}
+	return r, nil
+}
+
+// fractional reports if the difference type can represent fractions, that is, if it is a
+// floating point type
+func (ro operator[T, S]) fractional() bool {
+	one := S(1)
+	return one/2 != 0
+}
+
+// emptyBoth reports for both ranges if they are empty
+func (ro operator[T, S]) emptyBoth(first, second pgtype.Range[T]) (bool, bool, error) {
+	firstEmpty, err := ro.Empty(first)
+	if err != nil {
+		return false, false, err
+	}
+	secondEmpty, err := ro.Empty(second)
+	if err != nil {
+		return false, false, err
+	}
+	return firstEmpty, secondEmpty, nil
+}
+
 func (ro operator[T, S]) compareRanges(first, second pgtype.Range[T]) int {
 	first = ro.Rewrite(first)
 	second = ro.Rewrite(second)
 
+	// callers make sure both ranges are valid, so Empty can not fail
 	result := 0
 	firstEmpty, _ := ro.Empty(first)
 	secondEmpty, _ := ro.Empty(second)
@@ -551,20 +2310,67 @@ func (ro operator[T, S]) compareRanges(first, second pgtype.Range[T]) int {
 	return result
 }
 
+// CompareAllBounds compares every bound of the first range with every bound of the second range
+// after canonicalizing them, l1u2 for example compares the lower bound of the first range with
+// the upper bound of the second range. Each result is negative, zero or positive like cmp, an
+// unbounded lower bound is below and an unbounded upper bound above every value. The ranges
+// should be valid and not empty, otherwise the results are meaningless.
+func (ro operator[T, S]) CompareAllBounds(first, second pgtype.Range[T]) (l1l2, l1u2, u1l2, u1u2 int) {
+	return ro.compareAllBounds(ro.Rewrite(first), ro.Rewrite(second))
+}
+
+// compareAllBounds is CompareAllBounds for canonicalized ranges
+func (ro operator[T, S]) compareAllBounds(first, second pgtype.Range[T]) (l1l2, l1u2, u1l2, u1u2 int) {
+	return ro.compareBounds(first, second, true, true),
+		ro.compareBounds(first, second, true, false),
+		ro.compareBounds(first, second, false, true),
+		ro.compareBounds(first, second, false, false)
+}
+
+// boundsOverlap reports if non-empty ranges overlap given the results of compareAllBounds, either
+// lower bound lies within the other range
+func boundsOverlap(l1l2, l1u2, u1l2 int) bool {
+	return (l1l2 >= 0 && l1u2 <= 0) || (l1l2 <= 0 && u1l2 >= 0)
+}
+
+// extremeBound chooses one of two lower bounds, or of two upper bounds when lower is false. When
+// outer is true the bound of the convex hull is chosen, that is the lowest lower bound or the
+// highest upper bound, otherwise the bound of the intersection. An unbounded bound is the most
+// extreme bound and when the values are equal an inclusive bound is more extreme than an
+// exclusive bound. An empty bound is ignored for the hull and chosen for the intersection.
+func (ro operator[T, S]) extremeBound(aValue T, aType pgtype.BoundType, bValue T, bType pgtype.BoundType, lower, outer bool) (T, pgtype.BoundType) {
+	if aType == pgtype.Empty || bType == pgtype.Empty {
+		if (aType == pgtype.Empty) == outer {
+			return bValue, bType
+		}
+		return aValue, aType
+	}
+	c := ro.compareBoundValues(aValue, aType, lower, bValue, bType, lower)
+	// the hull needs the smaller lower bound, the intersection the greater one
+	if (lower == outer && c <= 0) || (lower != outer && c >= 0) {
+		return aValue, aType
+	}
+	return bValue, bType
+}
+
 // the boolean parameters determine if the lower or upper bound is used to for comparison
 func (ro operator[T, S]) compareBounds(first, second pgtype.Range[T], firstLower, secondLower bool) int {
-	// make sure the boundaries that need to be compared are in the lower part of the ranges
-	// this makes the rest of the code easier to understand
-	if !firstLower {
-		first.Lower = first.Upper
-		first.LowerType = first.UpperType
+	firstValue, firstType := first.Upper, first.UpperType
+	if firstLower {
+		firstValue, firstType = first.Lower, first.LowerType
 	}
-	if !secondLower {
-		second.Lower = second.Upper
-		second.LowerType = second.UpperType
+	secondValue, secondType := second.Upper, second.UpperType
+	if secondLower {
+		secondValue, secondType = second.Lower, second.LowerType
 	}
+	return ro.compareBoundValues(firstValue, firstType, firstLower, secondValue, secondType, secondLower)
+}
 
-	if first.LowerType == pgtype.Unbounded && second.LowerType == pgtype.Unbounded {
+// compareBoundValues compares two bounds given by their value and type, the boolean parameters
+// tell if the bound is a lower or an upper bound. Hot loops can use it directly to avoid copying
+// whole ranges.
+func (ro operator[T, S]) compareBoundValues(firstValue T, firstType pgtype.BoundType, firstLower bool, secondValue T, secondType pgtype.BoundType, secondLower bool) int {
+	if firstType == pgtype.Unbounded && secondType == pgtype.Unbounded {
 		if firstLower == secondLower {
 			return 0
 		}
@@ -572,21 +2378,21 @@ func (ro operator[T, S]) compareBounds(first, second pgtype.Range[T], firstLower
 			return -1
 		}
 		return 1
-	} else if first.LowerType == pgtype.Unbounded {
+	} else if firstType == pgtype.Unbounded {
 		if firstLower {
 			return -1
 		}
 		return 1
-	} else if second.LowerType == pgtype.Unbounded {
+	} else if secondType == pgtype.Unbounded {
 		if secondLower {
 			return 1
 		}
 		return -1
 	}
 
-	result := ro.cmp(first.Lower, second.Lower)
+	result := ro.cmp(firstValue, secondValue)
 	if result == 0 {
-		if first.LowerType != pgtype.Inclusive && second.LowerType != pgtype.Inclusive {
+		if firstType != pgtype.Inclusive && secondType != pgtype.Inclusive {
 			if firstLower == secondLower {
 				return 0
 			}
@@ -594,12 +2400,12 @@ func (ro operator[T, S]) compareBounds(first, second pgtype.Range[T], firstLower
 				return 1
 			}
 			return -1
-		} else if first.LowerType != pgtype.Inclusive {
+		} else if firstType != pgtype.Inclusive {
 			if firstLower {
 				return 1
 			}
 			return -1
-		} else if second.LowerType != pgtype.Inclusive {
+		} else if secondType != pgtype.Inclusive {
 			if secondLower {
 				return -1
 			}
@@ -612,6 +2418,22 @@ func (ro operator[T, S]) compareBounds(first, second pgtype.Range[T], firstLower
 	return result
 }
 
+// hasEmptyBound reports if the range has an empty bound type on either side. A range with an
+// empty bound type on one side only is inconsistent, it is treated as the empty range like pgx
+// does when it encodes a range with an empty lower bound type.
+func hasEmptyBound[T any](r pgtype.Range[T]) bool {
+	return r.LowerType == pgtype.Empty || r.UpperType == pgtype.Empty
+}
+
+// normalizeEmpty returns the empty range for a valid range with an empty bound type on either
+// side, other ranges are returned as is
+func normalizeEmpty[T any](r pgtype.Range[T]) pgtype.Range[T] {
+	if r.Valid && hasEmptyBound(r) {
+		return makeEmptyRange[T]()
+	}
+	return r
+}
+
 func makeEmptyRange[T any]() pgtype.Range[T] {
 	return pgtype.Range[T]{
 		LowerType: pgtype.Empty,
@@ -619,3 +2441,31 @@ func makeEmptyRange[T any]() pgtype.Range[T] {
 		Valid:     true,
 	}
 }
+
+// writeHashValue writes a representation of the value to the hash
+func writeHashValue(h hash.Hash64, v any) error {
+	if m, ok := v.(encoding.BinaryMarshaler); ok {
+		b, err := m.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		_, err = h.Write(b)
+		return err
+	}
+	if binary.Size(v) > 0 {
+		return binary.Write(h, binary.LittleEndian, v)
+	}
+	_, err := fmt.Fprint(h, v)
+	return err
+}
+
+// invertBoundType turns an inclusive bound into an exclusive bound and vice versa
+func invertBoundType(t pgtype.BoundType) pgtype.BoundType {
+	switch t {
+	case pgtype.Inclusive:
+		return pgtype.Exclusive
+	case pgtype.Exclusive:
+		return pgtype.Inclusive
+	}
+	return t
+}
@@ -3,23 +3,29 @@ package pro
 import (
 	"cmp"
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"math"
+	"math/rand/v2"
 	"reflect"
+	"slices"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/munnik/pgx_range_operator/protest"
 	"github.com/ory/dockertest/v3"
 )
 
 var conn *pgxpool.Pool
-var iro = New(
+var iro = NewWithAdd(
 	cmp.Compare[int64],
 	func(a, b int64) int64 { return a - b },
-	func(a int64) int64 { return a + 1 },
+	func(a, d int64) int64 { return a + d },
 	true,
 )
 var tro = NewTime()
@@ -82,14 +88,14 @@ func FuzzLessThan(f *testing.F) {
 			secondIntRange := pgtype.Range[int64]{Lower: lowerSecond, Upper: upperSecond, Valid: validSecond}
 			secondIntRange.SetBoundTypes(createBoundType(lowerTypeSecond), createBoundType(upperTypeSecond))
 
-			binaryOperatorTest1(t, "<", "int8range", firstIntRange, secondIntRange, iro.LessThan)
+			protest.CheckBoolOperator(t, conn, "<", "int8range", firstIntRange, secondIntRange, iro.LessThan)
 
 			firstTimeRange := pgtype.Range[time.Time]{Lower: time.Unix(lowerFirst, 0), Upper: time.Unix(upperFirst, 0), Valid: validFirst}
 			firstTimeRange.SetBoundTypes(createBoundType(lowerTypeFirst), createBoundType(upperTypeFirst))
 			secondTimeRange := pgtype.Range[time.Time]{Lower: time.Unix(lowerSecond, 0), Upper: time.Unix(upperSecond, 0), Valid: validSecond}
 			secondTimeRange.SetBoundTypes(createBoundType(lowerTypeSecond), createBoundType(upperTypeSecond))
 
-			binaryOperatorTest1(t, "<", "tstzrange", firstTimeRange, secondTimeRange, tro.LessThan)
+			protest.CheckBoolOperator(t, conn, "<", "tstzrange", firstTimeRange, secondTimeRange, tro.LessThan)
 		},
 	)
 }
@@ -107,14 +113,14 @@ func FuzzLessThanOrEqualTo(f *testing.F) {
 			second := pgtype.Range[int64]{Lower: lowerSecond, Upper: upperSecond, Valid: validSecond}
 			second.SetBoundTypes(createBoundType(lowerTypeSecond), createBoundType(upperTypeSecond))
 
-			binaryOperatorTest1(t, "<=", "int8range", first, second, iro.LessThanOrEqualTo)
+			protest.CheckBoolOperator(t, conn, "<=", "int8range", first, second, iro.LessThanOrEqualTo)
 
 			firstTimeRange := pgtype.Range[time.Time]{Lower: time.Unix(lowerFirst, 0), Upper: time.Unix(upperFirst, 0), Valid: validFirst}
 			firstTimeRange.SetBoundTypes(createBoundType(lowerTypeFirst), createBoundType(upperTypeFirst))
 			secondTimeRange := pgtype.Range[time.Time]{Lower: time.Unix(lowerSecond, 0), Upper: time.Unix(upperSecond, 0), Valid: validSecond}
 			secondTimeRange.SetBoundTypes(createBoundType(lowerTypeSecond), createBoundType(upperTypeSecond))
 
-			binaryOperatorTest1(t, "<=", "tstzrange", firstTimeRange, secondTimeRange, tro.LessThanOrEqualTo)
+			protest.CheckBoolOperator(t, conn, "<=", "tstzrange", firstTimeRange, secondTimeRange, tro.LessThanOrEqualTo)
 		},
 	)
 }
@@ -132,14 +138,14 @@ func FuzzGreaterThan(f *testing.F) {
 			second := pgtype.Range[int64]{Lower: lowerSecond, Upper: upperSecond, Valid: validSecond}
 			second.SetBoundTypes(createBoundType(lowerTypeSecond), createBoundType(upperTypeSecond))
 
-			binaryOperatorTest1(t, ">", "int8range", first, second, iro.GreaterThan)
+			protest.CheckBoolOperator(t, conn, ">", "int8range", first, second, iro.GreaterThan)
 
 			firstTimeRange := pgtype.Range[time.Time]{Lower: time.Unix(lowerFirst, 0), Upper: time.Unix(upperFirst, 0), Valid: validFirst}
 			firstTimeRange.SetBoundTypes(createBoundType(lowerTypeFirst), createBoundType(upperTypeFirst))
 			secondTimeRange := pgtype.Range[time.Time]{Lower: time.Unix(lowerSecond, 0), Upper: time.Unix(upperSecond, 0), Valid: validSecond}
 			secondTimeRange.SetBoundTypes(createBoundType(lowerTypeSecond), createBoundType(upperTypeSecond))
 
-			binaryOperatorTest1(t, ">", "tstzrange", firstTimeRange, secondTimeRange, tro.GreaterThan)
+			protest.CheckBoolOperator(t, conn, ">", "tstzrange", firstTimeRange, secondTimeRange, tro.GreaterThan)
 		},
 	)
 }
@@ -157,14 +163,14 @@ func FuzzGreaterThanOrEqualTo(f *testing.F) {
 			second := pgtype.Range[int64]{Lower: lowerSecond, Upper: upperSecond, Valid: validSecond}
 			second.SetBoundTypes(createBoundType(lowerTypeSecond), createBoundType(upperTypeSecond))
 
-			binaryOperatorTest1(t, ">=", "int8range", first, second, iro.GreaterThanOrEqualTo)
+			protest.CheckBoolOperator(t, conn, ">=", "int8range", first, second, iro.GreaterThanOrEqualTo)
 
 			firstTimeRange := pgtype.Range[time.Time]{Lower: time.Unix(lowerFirst, 0), Upper: time.Unix(upperFirst, 0), Valid: validFirst}
 			firstTimeRange.SetBoundTypes(createBoundType(lowerTypeFirst), createBoundType(upperTypeFirst))
 			secondTimeRange := pgtype.Range[time.Time]{Lower: time.Unix(lowerSecond, 0), Upper: time.Unix(upperSecond, 0), Valid: validSecond}
 			secondTimeRange.SetBoundTypes(createBoundType(lowerTypeSecond), createBoundType(upperTypeSecond))
 
-			binaryOperatorTest1(t, ">=", "tstzrange", firstTimeRange, secondTimeRange, tro.GreaterThanOrEqualTo)
+			protest.CheckBoolOperator(t, conn, ">=", "tstzrange", firstTimeRange, secondTimeRange, tro.GreaterThanOrEqualTo)
 		},
 	)
 }
@@ -182,14 +188,14 @@ func FuzzEqual(f *testing.F) {
 			second := pgtype.Range[int64]{Lower: lowerSecond, Upper: upperSecond, Valid: validSecond}
 			second.SetBoundTypes(createBoundType(lowerTypeSecond), createBoundType(upperTypeSecond))
 
-			binaryOperatorTest1(t, "=", "int8range", first, second, iro.Equal)
+			protest.CheckBoolOperator(t, conn, "=", "int8range", first, second, iro.Equal)
 
 			firstTimeRange := pgtype.Range[time.Time]{Lower: time.Unix(lowerFirst, 0), Upper: time.Unix(upperFirst, 0), Valid: validFirst}
 			firstTimeRange.SetBoundTypes(createBoundType(lowerTypeFirst), createBoundType(upperTypeFirst))
 			secondTimeRange := pgtype.Range[time.Time]{Lower: time.Unix(lowerSecond, 0), Upper: time.Unix(upperSecond, 0), Valid: validSecond}
 			secondTimeRange.SetBoundTypes(createBoundType(lowerTypeSecond), createBoundType(upperTypeSecond))
 
-			binaryOperatorTest1(t, "=", "tstzrange", firstTimeRange, secondTimeRange, tro.Equal)
+			protest.CheckBoolOperator(t, conn, "=", "tstzrange", firstTimeRange, secondTimeRange, tro.Equal)
 		},
 	)
 }
@@ -207,14 +213,14 @@ func FuzzContain(f *testing.F) {
 			second := pgtype.Range[int64]{Lower: lowerSecond, Upper: upperSecond, Valid: validSecond}
 			second.SetBoundTypes(createBoundType(lowerTypeSecond), createBoundType(upperTypeSecond))
 
-			binaryOperatorTest1(t, "@>", "int8range", first, second, iro.Contain)
+			protest.CheckBoolOperator(t, conn, "@>", "int8range", first, second, iro.Contain)
 
 			firstTimeRange := pgtype.Range[time.Time]{Lower: time.Unix(lowerFirst, 0), Upper: time.Unix(upperFirst, 0), Valid: validFirst}
 			firstTimeRange.SetBoundTypes(createBoundType(lowerTypeFirst), createBoundType(upperTypeFirst))
 			secondTimeRange := pgtype.Range[time.Time]{Lower: time.Unix(lowerSecond, 0), Upper: time.Unix(upperSecond, 0), Valid: validSecond}
 			secondTimeRange.SetBoundTypes(createBoundType(lowerTypeSecond), createBoundType(upperTypeSecond))
 
-			binaryOperatorTest1(t, "@>", "tstzrange", firstTimeRange, secondTimeRange, tro.Contain)
+			protest.CheckBoolOperator(t, conn, "@>", "tstzrange", firstTimeRange, secondTimeRange, tro.Contain)
 		},
 	)
 }
@@ -229,13 +235,59 @@ func FuzzContainElement(f *testing.F) {
 			first := pgtype.Range[int64]{Lower: lowerFirst, Upper: upperFirst, Valid: validFirst}
 			first.SetBoundTypes(createBoundType(lowerTypeFirst), createBoundType(upperTypeFirst))
 
-			binaryOperatorTest2(t, "@>", "int8range", "bigint", first, second, iro.ContainElement)
+			protest.CheckElementOperator(t, conn, "@>", "int8range", "bigint", first, second, iro.ContainElement)
 
 			firstTimeRange := pgtype.Range[time.Time]{Lower: time.Unix(lowerFirst, 0), Upper: time.Unix(upperFirst, 0), Valid: validFirst}
 			firstTimeRange.SetBoundTypes(createBoundType(lowerTypeFirst), createBoundType(upperTypeFirst))
 			secondTime := time.Unix(second, 0)
 
-			binaryOperatorTest2(t, "@>", "tstzrange", "timestamp with time zone", firstTimeRange, secondTime, tro.ContainElement)
+			protest.CheckElementOperator(t, conn, "@>", "tstzrange", "timestamp with time zone", firstTimeRange, secondTime, tro.ContainElement)
+		},
+	)
+}
+
+func FuzzContainedBy(f *testing.F) {
+	f.Fuzz(
+		func(t *testing.T, lowerFirst, lowerTypeFirst, upperFirst, upperTypeFirst int64, validFirst bool, lowerSecond, lowerTypeSecond, upperSecond, upperTypeSecond int64, validSecond bool) {
+			t.Parallel()
+
+			lowerFirst, upperFirst = sort(lowerFirst, upperFirst)
+			lowerSecond, upperSecond = sort(lowerSecond, upperSecond)
+
+			first := pgtype.Range[int64]{Lower: lowerFirst, Upper: upperFirst, Valid: validFirst}
+			first.SetBoundTypes(createBoundType(lowerTypeFirst), createBoundType(upperTypeFirst))
+			second := pgtype.Range[int64]{Lower: lowerSecond, Upper: upperSecond, Valid: validSecond}
+			second.SetBoundTypes(createBoundType(lowerTypeSecond), createBoundType(upperTypeSecond))
+
+			protest.CheckBoolOperator(t, conn, "<@", "int8range", first, second, iro.ContainedBy)
+
+			firstTimeRange := pgtype.Range[time.Time]{Lower: time.Unix(lowerFirst, 0), Upper: time.Unix(upperFirst, 0), Valid: validFirst}
+			firstTimeRange.SetBoundTypes(createBoundType(lowerTypeFirst), createBoundType(upperTypeFirst))
+			secondTimeRange := pgtype.Range[time.Time]{Lower: time.Unix(lowerSecond, 0), Upper: time.Unix(upperSecond, 0), Valid: validSecond}
+			secondTimeRange.SetBoundTypes(createBoundType(lowerTypeSecond), createBoundType(upperTypeSecond))
+
+			protest.CheckBoolOperator(t, conn, "<@", "tstzrange", firstTimeRange, secondTimeRange, tro.ContainedBy)
+		},
+	)
+}
+
+func FuzzElementContainedBy(f *testing.F) {
+	f.Fuzz(
+		func(t *testing.T, first int64, lowerSecond, lowerTypeSecond, upperSecond, upperTypeSecond int64, validSecond bool) {
+			t.Parallel()
+
+			lowerSecond, upperSecond = sort(lowerSecond, upperSecond)
+
+			second := pgtype.Range[int64]{Lower: lowerSecond, Upper: upperSecond, Valid: validSecond}
+			second.SetBoundTypes(createBoundType(lowerTypeSecond), createBoundType(upperTypeSecond))
+
+			protest.CheckElementRangeOperator(t, conn, "<@", "bigint", "int8range", first, second, iro.ElementContainedBy)
+
+			firstTime := time.Unix(first, 0)
+			secondTimeRange := pgtype.Range[time.Time]{Lower: time.Unix(lowerSecond, 0), Upper: time.Unix(upperSecond, 0), Valid: validSecond}
+			secondTimeRange.SetBoundTypes(createBoundType(lowerTypeSecond), createBoundType(upperTypeSecond))
+
+			protest.CheckElementRangeOperator(t, conn, "<@", "timestamp with time zone", "tstzrange", firstTime, secondTimeRange, tro.ElementContainedBy)
 		},
 	)
 }
@@ -253,14 +305,14 @@ func FuzzOverlap(f *testing.F) {
 			second := pgtype.Range[int64]{Lower: lowerSecond, Upper: upperSecond, Valid: validSecond}
 			second.SetBoundTypes(createBoundType(lowerTypeSecond), createBoundType(upperTypeSecond))
 
-			binaryOperatorTest1(t, "&&", "int8range", first, second, iro.Overlap)
+			protest.CheckBoolOperator(t, conn, "&&", "int8range", first, second, iro.Overlap)
 
 			firstTimeRange := pgtype.Range[time.Time]{Lower: time.Unix(lowerFirst, 0), Upper: time.Unix(upperFirst, 0), Valid: validFirst}
 			firstTimeRange.SetBoundTypes(createBoundType(lowerTypeFirst), createBoundType(upperTypeFirst))
 			secondTimeRange := pgtype.Range[time.Time]{Lower: time.Unix(lowerSecond, 0), Upper: time.Unix(upperSecond, 0), Valid: validSecond}
 			secondTimeRange.SetBoundTypes(createBoundType(lowerTypeSecond), createBoundType(upperTypeSecond))
 
-			binaryOperatorTest1(t, "&&", "tstzrange", firstTimeRange, secondTimeRange, tro.Overlap)
+			protest.CheckBoolOperator(t, conn, "&&", "tstzrange", firstTimeRange, secondTimeRange, tro.Overlap)
 		},
 	)
 }
@@ -278,14 +330,14 @@ func FuzzLeftOf(f *testing.F) {
 			second := pgtype.Range[int64]{Lower: lowerSecond, Upper: upperSecond, Valid: validSecond}
 			second.SetBoundTypes(createBoundType(lowerTypeSecond), createBoundType(upperTypeSecond))
 
-			binaryOperatorTest1(t, "<<", "int8range", first, second, iro.LeftOf)
+			protest.CheckBoolOperator(t, conn, "<<", "int8range", first, second, iro.LeftOf)
 
 			firstTimeRange := pgtype.Range[time.Time]{Lower: time.Unix(lowerFirst, 0), Upper: time.Unix(upperFirst, 0), Valid: validFirst}
 			firstTimeRange.SetBoundTypes(createBoundType(lowerTypeFirst), createBoundType(upperTypeFirst))
 			secondTimeRange := pgtype.Range[time.Time]{Lower: time.Unix(lowerSecond, 0), Upper: time.Unix(upperSecond, 0), Valid: validSecond}
 			secondTimeRange.SetBoundTypes(createBoundType(lowerTypeSecond), createBoundType(upperTypeSecond))
 
-			binaryOperatorTest1(t, "<<", "tstzrange", firstTimeRange, secondTimeRange, tro.LeftOf)
+			protest.CheckBoolOperator(t, conn, "<<", "tstzrange", firstTimeRange, secondTimeRange, tro.LeftOf)
 		},
 	)
 }
@@ -303,14 +355,14 @@ func FuzzRightOf(f *testing.F) {
 			second := pgtype.Range[int64]{Lower: lowerSecond, Upper: upperSecond, Valid: validSecond}
 			second.SetBoundTypes(createBoundType(lowerTypeSecond), createBoundType(upperTypeSecond))
 
-			binaryOperatorTest1(t, ">>", "int8range", first, second, iro.RightOf)
+			protest.CheckBoolOperator(t, conn, ">>", "int8range", first, second, iro.RightOf)
 
 			firstTimeRange := pgtype.Range[time.Time]{Lower: time.Unix(lowerFirst, 0), Upper: time.Unix(upperFirst, 0), Valid: validFirst}
 			firstTimeRange.SetBoundTypes(createBoundType(lowerTypeFirst), createBoundType(upperTypeFirst))
 			secondTimeRange := pgtype.Range[time.Time]{Lower: time.Unix(lowerSecond, 0), Upper: time.Unix(upperSecond, 0), Valid: validSecond}
 			secondTimeRange.SetBoundTypes(createBoundType(lowerTypeSecond), createBoundType(upperTypeSecond))
 
-			binaryOperatorTest1(t, ">>", "tstzrange", firstTimeRange, secondTimeRange, tro.RightOf)
+			protest.CheckBoolOperator(t, conn, ">>", "tstzrange", firstTimeRange, secondTimeRange, tro.RightOf)
 		},
 	)
 }
@@ -328,14 +380,14 @@ func FuzzAdjacent(f *testing.F) {
 			second := pgtype.Range[int64]{Lower: lowerSecond, Upper: upperSecond, Valid: validSecond}
 			second.SetBoundTypes(createBoundType(lowerTypeSecond), createBoundType(upperTypeSecond))
 
-			binaryOperatorTest1(t, "-|-", "int8range", first, second, iro.Adjacent)
+			protest.CheckBoolOperator(t, conn, "-|-", "int8range", first, second, iro.Adjacent)
 
 			firstTimeRange := pgtype.Range[time.Time]{Lower: time.Unix(lowerFirst, 0), Upper: time.Unix(upperFirst, 0), Valid: validFirst}
 			firstTimeRange.SetBoundTypes(createBoundType(lowerTypeFirst), createBoundType(upperTypeFirst))
 			secondTimeRange := pgtype.Range[time.Time]{Lower: time.Unix(lowerSecond, 0), Upper: time.Unix(upperSecond, 0), Valid: validSecond}
 			secondTimeRange.SetBoundTypes(createBoundType(lowerTypeSecond), createBoundType(upperTypeSecond))
 
-			binaryOperatorTest1(t, "-|-", "tstzrange", firstTimeRange, secondTimeRange, tro.Adjacent)
+			protest.CheckBoolOperator(t, conn, "-|-", "tstzrange", firstTimeRange, secondTimeRange, tro.Adjacent)
 		},
 	)
 }
@@ -353,14 +405,14 @@ func FuzzIntersect(f *testing.F) {
 			second := pgtype.Range[int64]{Lower: lowerSecond, Upper: upperSecond, Valid: validSecond}
 			second.SetBoundTypes(createBoundType(lowerTypeSecond), createBoundType(upperTypeSecond))
 
-			binaryOperatorTest3(t, "*", "int8range", first, second, iro.Intersect)
+			protest.CheckRangeOperator(t, conn, "*", "int8range", first, second, iro.Intersect)
 
 			firstTimeRange := pgtype.Range[time.Time]{Lower: time.Unix(lowerFirst, 0), Upper: time.Unix(upperFirst, 0), Valid: validFirst}
 			firstTimeRange.SetBoundTypes(createBoundType(lowerTypeFirst), createBoundType(upperTypeFirst))
 			secondTimeRange := pgtype.Range[time.Time]{Lower: time.Unix(lowerSecond, 0), Upper: time.Unix(upperSecond, 0), Valid: validSecond}
 			secondTimeRange.SetBoundTypes(createBoundType(lowerTypeSecond), createBoundType(upperTypeSecond))
 
-			binaryOperatorTest3(t, "*", "tstzrange", firstTimeRange, secondTimeRange, tro.Intersect)
+			protest.CheckRangeOperator(t, conn, "*", "tstzrange", firstTimeRange, secondTimeRange, tro.Intersect)
 		},
 	)
 }
@@ -378,14 +430,14 @@ func FuzzNotExtendRight(f *testing.F) {
 			second := pgtype.Range[int64]{Lower: lowerSecond, Upper: upperSecond, Valid: validSecond}
 			second.SetBoundTypes(createBoundType(lowerTypeSecond), createBoundType(upperTypeSecond))
 
-			binaryOperatorTest1(t, "&<", "int8range", first, second, iro.NotExtendRight)
+			protest.CheckBoolOperator(t, conn, "&<", "int8range", first, second, iro.NotExtendRight)
 
 			firstTimeRange := pgtype.Range[time.Time]{Lower: time.Unix(lowerFirst, 0), Upper: time.Unix(upperFirst, 0), Valid: validFirst}
 			firstTimeRange.SetBoundTypes(createBoundType(lowerTypeFirst), createBoundType(upperTypeFirst))
 			secondTimeRange := pgtype.Range[time.Time]{Lower: time.Unix(lowerSecond, 0), Upper: time.Unix(upperSecond, 0), Valid: validSecond}
 			secondTimeRange.SetBoundTypes(createBoundType(lowerTypeSecond), createBoundType(upperTypeSecond))
 
-			binaryOperatorTest1(t, "&<", "tstzrange", firstTimeRange, secondTimeRange, tro.NotExtendRight)
+			protest.CheckBoolOperator(t, conn, "&<", "tstzrange", firstTimeRange, secondTimeRange, tro.NotExtendRight)
 		},
 	)
 }
@@ -403,14 +455,14 @@ func FuzzNotExtendLeft(f *testing.F) {
 			second := pgtype.Range[int64]{Lower: lowerSecond, Upper: upperSecond, Valid: validSecond}
 			second.SetBoundTypes(createBoundType(lowerTypeSecond), createBoundType(upperTypeSecond))
 
-			binaryOperatorTest1(t, "&>", "int8range", first, second, iro.NotExtendLeft)
+			protest.CheckBoolOperator(t, conn, "&>", "int8range", first, second, iro.NotExtendLeft)
 
 			firstTimeRange := pgtype.Range[time.Time]{Lower: time.Unix(lowerFirst, 0), Upper: time.Unix(upperFirst, 0), Valid: validFirst}
 			firstTimeRange.SetBoundTypes(createBoundType(lowerTypeFirst), createBoundType(upperTypeFirst))
 			secondTimeRange := pgtype.Range[time.Time]{Lower: time.Unix(lowerSecond, 0), Upper: time.Unix(upperSecond, 0), Valid: validSecond}
 			secondTimeRange.SetBoundTypes(createBoundType(lowerTypeSecond), createBoundType(upperTypeSecond))
 
-			binaryOperatorTest1(t, "&>", "tstzrange", firstTimeRange, secondTimeRange, tro.NotExtendLeft)
+			protest.CheckBoolOperator(t, conn, "&>", "tstzrange", firstTimeRange, secondTimeRange, tro.NotExtendLeft)
 		},
 	)
 }
@@ -428,14 +480,14 @@ func FuzzUnion(f *testing.F) {
 			second := pgtype.Range[int64]{Lower: lowerSecond, Upper: upperSecond, Valid: validSecond}
 			second.SetBoundTypes(createBoundType(lowerTypeSecond), createBoundType(upperTypeSecond))
 
-			binaryOperatorTest3(t, "+", "int8range", first, second, iro.Union)
+			protest.CheckRangeOperator(t, conn, "+", "int8range", first, second, iro.Union)
 
 			firstTimeRange := pgtype.Range[time.Time]{Lower: time.Unix(lowerFirst, 0), Upper: time.Unix(upperFirst, 0), Valid: validFirst}
 			firstTimeRange.SetBoundTypes(createBoundType(lowerTypeFirst), createBoundType(upperTypeFirst))
 			secondTimeRange := pgtype.Range[time.Time]{Lower: time.Unix(lowerSecond, 0), Upper: time.Unix(upperSecond, 0), Valid: validSecond}
 			secondTimeRange.SetBoundTypes(createBoundType(lowerTypeSecond), createBoundType(upperTypeSecond))
 
-			binaryOperatorTest3(t, "+", "tstzrange", firstTimeRange, secondTimeRange, tro.Union)
+			protest.CheckRangeOperator(t, conn, "+", "tstzrange", firstTimeRange, secondTimeRange, tro.Union)
 		},
 	)
 }
@@ -453,14 +505,14 @@ func FuzzMerge(f *testing.F) {
 			second := pgtype.Range[int64]{Lower: lowerSecond, Upper: upperSecond, Valid: validSecond}
 			second.SetBoundTypes(createBoundType(lowerTypeSecond), createBoundType(upperTypeSecond))
 
-			binaryFunctionTest(t, "range_merge", "int8range", first, second, iro.Merge)
+			protest.CheckRangeFunction(t, conn, "range_merge", "int8range", first, second, iro.Merge)
 
 			firstTimeRange := pgtype.Range[time.Time]{Lower: time.Unix(lowerFirst, 0), Upper: time.Unix(upperFirst, 0), Valid: validFirst}
 			firstTimeRange.SetBoundTypes(createBoundType(lowerTypeFirst), createBoundType(upperTypeFirst))
 			secondTimeRange := pgtype.Range[time.Time]{Lower: time.Unix(lowerSecond, 0), Upper: time.Unix(upperSecond, 0), Valid: validSecond}
 			secondTimeRange.SetBoundTypes(createBoundType(lowerTypeSecond), createBoundType(upperTypeSecond))
 
-			binaryFunctionTest(t, "range_merge", "tstzrange", firstTimeRange, secondTimeRange, tro.Merge)
+			protest.CheckRangeFunction(t, conn, "range_merge", "tstzrange", firstTimeRange, secondTimeRange, tro.Merge)
 		},
 	)
 }
@@ -478,18 +530,281 @@ func FuzzDifference(f *testing.F) {
 			second := pgtype.Range[int64]{Lower: lowerSecond, Upper: upperSecond, Valid: validSecond}
 			second.SetBoundTypes(createBoundType(lowerTypeSecond), createBoundType(upperTypeSecond))
 
-			binaryOperatorTest3(t, "-", "int8range", first, second, iro.Difference)
+			protest.CheckRangeOperator(t, conn, "-", "int8range", first, second, iro.Difference)
 
 			firstTimeRange := pgtype.Range[time.Time]{Lower: time.Unix(lowerFirst, 0), Upper: time.Unix(upperFirst, 0), Valid: validFirst}
 			firstTimeRange.SetBoundTypes(createBoundType(lowerTypeFirst), createBoundType(upperTypeFirst))
 			secondTimeRange := pgtype.Range[time.Time]{Lower: time.Unix(lowerSecond, 0), Upper: time.Unix(upperSecond, 0), Valid: validSecond}
 			secondTimeRange.SetBoundTypes(createBoundType(lowerTypeSecond), createBoundType(upperTypeSecond))
 
-			binaryOperatorTest3(t, "-", "tstzrange", firstTimeRange, secondTimeRange, tro.Difference)
+			protest.CheckRangeOperator(t, conn, "-", "tstzrange", firstTimeRange, secondTimeRange, tro.Difference)
+		},
+	)
+}
+
+func FuzzLowerUpperInc(f *testing.F) {
+	f.Fuzz(
+		func(t *testing.T, lower, lowerType, upper, upperType int64) {
+			t.Parallel()
+
+			lower, upper = sort(lower, upper)
+
+			r := pgtype.Range[int64]{Lower: lower, Upper: upper, Valid: true}
+			r.SetBoundTypes(createBoundType(lowerType), createBoundType(upperType))
+			timeRange := pgtype.Range[time.Time]{Lower: time.Unix(lower, 0), Upper: time.Unix(upper, 0), Valid: true}
+			timeRange.SetBoundTypes(createBoundType(lowerType), createBoundType(upperType))
+
+			for _, tt := range []struct {
+				query  string
+				args   pgx.NamedArgs
+				result bool
+			}{
+				{query: `SELECT lower_inc(@r::int8range)`, args: pgx.NamedArgs{"r": r}, result: iro.LowerInc(r)},
+				{query: `SELECT upper_inc(@r::int8range)`, args: pgx.NamedArgs{"r": r}, result: iro.UpperInc(r)},
+				{query: `SELECT lower_inc(@r::tstzrange)`, args: pgx.NamedArgs{"r": timeRange}, result: tro.LowerInc(timeRange)},
+				{query: `SELECT upper_inc(@r::tstzrange)`, args: pgx.NamedArgs{"r": timeRange}, result: tro.UpperInc(timeRange)},
+			} {
+				expected, err := protest.RetrieveExpected[bool](conn, tt.query, tt.args)
+				if err != nil {
+					t.Errorf("%s with `%v`: expected no error, got `%v`", tt.query, tt.args["r"], err)
+					continue
+				}
+				if expected != tt.result {
+					t.Errorf("%s with `%v`: expected result `%v`, got `%v`", tt.query, tt.args["r"], expected, tt.result)
+				}
+			}
 		},
 	)
 }
 
+func TestNotExtendEmptyAndUnbounded(t *testing.T) {
+	ranges := []pgtype.Range[int64]{
+		{LowerType: pgtype.Empty, UpperType: pgtype.Empty, Valid: true},
+		{Lower: 3, LowerType: pgtype.Inclusive, Upper: 3, UpperType: pgtype.Exclusive, Valid: true},
+		{LowerType: pgtype.Unbounded, UpperType: pgtype.Unbounded, Valid: true},
+		{LowerType: pgtype.Unbounded, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+		{Lower: 5, LowerType: pgtype.Inclusive, UpperType: pgtype.Unbounded, Valid: true},
+		{Lower: 1, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+	}
+	for _, first := range ranges {
+		for _, second := range ranges {
+			protest.CheckBoolOperator(t, conn, "&<", "int8range", first, second, iro.NotExtendRight)
+			protest.CheckBoolOperator(t, conn, "&>", "int8range", first, second, iro.NotExtendLeft)
+		}
+	}
+}
+
+func TestEmptyOperands(t *testing.T) {
+	empty := pgtype.Range[int64]{LowerType: pgtype.Empty, UpperType: pgtype.Empty, Valid: true}
+	normals := []pgtype.Range[int64]{
+		{Lower: 1, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+		{Lower: 1, LowerType: pgtype.Exclusive, UpperType: pgtype.Unbounded, Valid: true},
+		{LowerType: pgtype.Unbounded, UpperType: pgtype.Unbounded, Valid: true},
+		// not empty by its bound types, but without any elements
+		{Lower: 3, LowerType: pgtype.Inclusive, Upper: 3, UpperType: pgtype.Exclusive, Valid: true},
+	}
+	var pairs [][2]pgtype.Range[int64]
+	for _, normal := range normals {
+		pairs = append(pairs, [2]pgtype.Range[int64]{empty, normal}, [2]pgtype.Range[int64]{normal, empty})
+	}
+	pairs = append(pairs, [2]pgtype.Range[int64]{empty, empty})
+
+	boolOperators := map[string]func(pgtype.Range[int64], pgtype.Range[int64]) (bool, error){
+		"=":   iro.Equal,
+		"<":   iro.LessThan,
+		"<=":  iro.LessThanOrEqualTo,
+		">":   iro.GreaterThan,
+		">=":  iro.GreaterThanOrEqualTo,
+		"@>":  iro.Contain,
+		"<@":  iro.ContainedBy,
+		"&&":  iro.Overlap,
+		"<<":  iro.LeftOf,
+		">>":  iro.RightOf,
+		"&<":  iro.NotExtendRight,
+		"&>":  iro.NotExtendLeft,
+		"-|-": iro.Adjacent,
+	}
+	rangeOperators := map[string]func(pgtype.Range[int64], pgtype.Range[int64]) (pgtype.Range[int64], error){
+		"+": iro.Union,
+		"*": iro.Intersect,
+		"-": iro.Difference,
+	}
+
+	for _, pair := range pairs {
+		for sqlOperator, fn := range boolOperators {
+			protest.CheckBoolOperator(t, conn, sqlOperator, "int8range", pair[0], pair[1], fn)
+		}
+		for sqlOperator, fn := range rangeOperators {
+			protest.CheckRangeOperator(t, conn, sqlOperator, "int8range", pair[0], pair[1], fn)
+		}
+		protest.CheckRangeFunction(t, conn, "range_merge", "int8range", pair[0], pair[1], iro.Merge)
+
+		first := pgtype.Range[time.Time]{Lower: time.Unix(pair[0].Lower, 0), LowerType: pair[0].LowerType, Upper: time.Unix(pair[0].Upper, 0), UpperType: pair[0].UpperType, Valid: true}
+		second := pgtype.Range[time.Time]{Lower: time.Unix(pair[1].Lower, 0), LowerType: pair[1].LowerType, Upper: time.Unix(pair[1].Upper, 0), UpperType: pair[1].UpperType, Valid: true}
+		protest.CheckBoolOperator(t, conn, "&&", "tstzrange", first, second, tro.Overlap)
+		protest.CheckBoolOperator(t, conn, "@>", "tstzrange", first, second, tro.Contain)
+		protest.CheckBoolOperator(t, conn, "-|-", "tstzrange", first, second, tro.Adjacent)
+		protest.CheckBoolOperator(t, conn, "<", "tstzrange", first, second, tro.LessThan)
+	}
+}
+
+func TestRewriteDatabase(t *testing.T) {
+	random := rand.New(rand.NewPCG(3, 4))
+	values := []int64{math.MinInt64, math.MinInt64 + 1, -1, 0, 1, math.MaxInt64 - 1, math.MaxInt64}
+	value := func() int64 {
+		if random.IntN(2) == 0 {
+			return values[random.IntN(len(values))]
+		}
+		return random.Int64()
+	}
+
+	for range 500 {
+		lower, upper := sort(value(), value())
+		r := pgtype.Range[int64]{Lower: lower, Upper: upper, Valid: true}
+		r.SetBoundTypes(createBoundType(random.Int64()), createBoundType(random.Int64()))
+		if r.LowerType == pgtype.Unbounded {
+			r.Lower = 0
+		}
+		if r.UpperType == pgtype.Unbounded {
+			r.Upper = 0
+		}
+
+		result := iro.Rewrite(r)
+		expected, err := protest.RetrieveExpected[pgtype.Range[int64]](conn, "SELECT @r::int8range", pgx.NamedArgs{"r": r})
+		if err != nil {
+			// PostgreSQL can't canonicalize a bound at the maximum value, the bound is kept as is
+			atMax := (result.LowerType == pgtype.Exclusive && result.Lower == math.MaxInt64) ||
+				(result.UpperType == pgtype.Inclusive && result.Upper == math.MaxInt64)
+			if !atMax || iro.Validate(result) != nil {
+				t.Errorf("rewrite `%v`: expected a bound kept at the maximum value, got `%v` (PostgreSQL error `%v`)", r, result, err)
+			}
+			continue
+		}
+		if !reflect.DeepEqual(expected, result) {
+			t.Errorf("rewrite `%v`: expected result `%v`, got `%v`", r, expected, result)
+		}
+	}
+}
+
+func TestRewriteMaximum(t *testing.T) {
+	tests := []struct {
+		r        pgtype.Range[int64]
+		expected pgtype.Range[int64]
+	}{
+		{
+			r:        pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: math.MaxInt64, UpperType: pgtype.Inclusive, Valid: true},
+			expected: pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: math.MaxInt64, UpperType: pgtype.Inclusive, Valid: true},
+		},
+		{
+			r:        pgtype.Range[int64]{Lower: math.MaxInt64 - 1, LowerType: pgtype.Exclusive, Upper: math.MaxInt64, UpperType: pgtype.Inclusive, Valid: true},
+			expected: pgtype.Range[int64]{Lower: math.MaxInt64, LowerType: pgtype.Inclusive, Upper: math.MaxInt64, UpperType: pgtype.Inclusive, Valid: true},
+		},
+		{
+			r:        pgtype.Range[int64]{Lower: math.MaxInt64, LowerType: pgtype.Exclusive, UpperType: pgtype.Unbounded, Valid: true},
+			expected: pgtype.Range[int64]{Lower: math.MaxInt64, LowerType: pgtype.Exclusive, UpperType: pgtype.Unbounded, Valid: true},
+		},
+		{
+			r:        pgtype.Range[int64]{Lower: math.MinInt64, LowerType: pgtype.Exclusive, Upper: math.MaxInt64 - 1, UpperType: pgtype.Inclusive, Valid: true},
+			expected: pgtype.Range[int64]{Lower: math.MinInt64 + 1, LowerType: pgtype.Inclusive, Upper: math.MaxInt64, UpperType: pgtype.Exclusive, Valid: true},
+		},
+	}
+
+	for _, tt := range tests {
+		if result := iro.Rewrite(tt.r); !reflect.DeepEqual(tt.expected, result) {
+			t.Errorf("rewrite `%v`: expected result `%v`, got `%v`", tt.r, tt.expected, result)
+		}
+	}
+
+	// the element at the maximum value is still contained
+	r := pgtype.Range[int32]{Lower: math.MaxInt32 - 1, LowerType: pgtype.Inclusive, Upper: math.MaxInt32, UpperType: pgtype.Inclusive, Valid: true}
+	if size, err := NewInt32().Size(r); err != nil || size != 2 {
+		t.Errorf("size `%v`: expected result `2`, got `%v` (error `%v`)", r, size, err)
+	}
+	if contain, err := NewInt32().Contain(r, pgtype.Range[int32]{Lower: math.MaxInt32, LowerType: pgtype.Inclusive, Upper: math.MaxInt32, UpperType: pgtype.Inclusive, Valid: true}); err != nil || !contain {
+		t.Errorf("`%v` @> `[%d,%d]`: expected result `true`, got `%v` (error `%v`)", r, math.MaxInt32, math.MaxInt32, contain, err)
+	}
+}
+
+func TestDurationDatabase(t *testing.T) {
+	dro := NewDuration()
+	values := []time.Duration{0, time.Minute, 5 * time.Minute, 30 * time.Minute}
+	boundTypes := []pgtype.BoundType{pgtype.Inclusive, pgtype.Exclusive, pgtype.Unbounded}
+	var rs []pgtype.Range[time.Duration]
+	for i, lower := range values {
+		for _, upper := range values[i:] {
+			for _, lowerType := range boundTypes {
+				for _, upperType := range boundTypes {
+					rs = append(rs, pgtype.Range[time.Duration]{Lower: lower, LowerType: lowerType, Upper: upper, UpperType: upperType, Valid: true})
+				}
+			}
+		}
+	}
+
+	// a duration range matches an int8range of nanoseconds
+	for _, first := range rs {
+		for _, second := range rs {
+			protest.CheckBoolOperator(t, conn, "&&", "int8range", first, second, dro.Overlap)
+			protest.CheckBoolOperator(t, conn, "@>", "int8range", first, second, dro.Contain)
+		}
+		for _, elem := range values {
+			protest.CheckElementOperator(t, conn, "@>", "int8range", "bigint", first, elem, dro.ContainElement)
+		}
+	}
+}
+
+func TestUnionAdjacentDatabase(t *testing.T) {
+	fro := NewFloat64()
+	boundTypes := []pgtype.BoundType{pgtype.Inclusive, pgtype.Exclusive}
+	for _, lowerType := range boundTypes {
+		for _, firstUpperType := range boundTypes {
+			for _, secondLowerType := range boundTypes {
+				for _, upperType := range boundTypes {
+					first := pgtype.Range[float64]{Lower: 1, LowerType: lowerType, Upper: 5, UpperType: firstUpperType, Valid: true}
+					second := pgtype.Range[float64]{Lower: 5, LowerType: secondLowerType, Upper: 10, UpperType: upperType, Valid: true}
+					protest.CheckRangeOperator(t, conn, "+", "numrange", first, second, fro.Union)
+					protest.CheckRangeOperator(t, conn, "+", "numrange", second, first, fro.Union)
+
+					firstInt := pgtype.Range[int64]{Lower: 1, LowerType: lowerType, Upper: 5, UpperType: firstUpperType, Valid: true}
+					secondInt := pgtype.Range[int64]{Lower: 5, LowerType: secondLowerType, Upper: 10, UpperType: upperType, Valid: true}
+					protest.CheckRangeOperator(t, conn, "+", "int8range", firstInt, secondInt, iro.Union)
+					protest.CheckRangeOperator(t, conn, "+", "int8range", secondInt, firstInt, iro.Union)
+				}
+			}
+		}
+	}
+}
+
+func TestEqualEmptyDatabase(t *testing.T) {
+	// degenerate ranges are equal to the literal empty range when PostgreSQL considers them empty
+	ranges := []pgtype.Range[int64]{
+		makeEmptyRange[int64](),
+		{Lower: 5, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+		{Lower: 4, LowerType: pgtype.Exclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+		{Lower: 5, LowerType: pgtype.Exclusive, Upper: 5, UpperType: pgtype.Inclusive, Valid: true},
+		{Lower: 5, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Inclusive, Valid: true},
+	}
+	for _, first := range ranges {
+		for _, second := range ranges {
+			protest.CheckBoolOperator(t, conn, "=", "int8range", first, second, iro.Equal)
+		}
+	}
+
+	// (4,5) is not empty for a continuous range
+	fro := NewFloat64()
+	floatRanges := []pgtype.Range[float64]{
+		makeEmptyRange[float64](),
+		{Lower: 5, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+		{Lower: 4, LowerType: pgtype.Exclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+		{Lower: 5, LowerType: pgtype.Exclusive, Upper: 5, UpperType: pgtype.Inclusive, Valid: true},
+		{Lower: 5, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Inclusive, Valid: true},
+	}
+	for _, first := range floatRanges {
+		for _, second := range floatRanges {
+			protest.CheckBoolOperator(t, conn, "=", "numrange", first, second, fro.Equal)
+		}
+	}
+}
+
 func TestSize(t *testing.T) {
 	tests := []struct {
 		r           pgtype.Range[int64]
@@ -555,101 +870,3795 @@ func TestSize(t *testing.T) {
 	}
 }
 
-func binaryOperatorTest1[T any](t *testing.T, sqlOperator, sqlRangeType string, first, second pgtype.Range[T], fn func(pgtype.Range[T], pgtype.Range[T]) (bool, error)) {
-	expected, expectedErr := retrieveExpected[bool](
-		fmt.Sprintf(`SELECT @first::%s %s @second::%s`, sqlRangeType, sqlOperator, sqlRangeType),
-		pgx.NamedArgs{"first": first, "second": second},
-	)
-	result, err := fn(first, second)
-	if err == nil && expectedErr != nil {
-		t.Errorf("`%v` %s `%v`: expected error `%v`, got none", first, sqlOperator, second, expectedErr)
+func TestGap(t *testing.T) {
+	tests := []struct {
+		first       pgtype.Range[int64]
+		second      pgtype.Range[int64]
+		expected    pgtype.Range[int64]
+		expectedErr bool
+	}{
+		{
+			first:    pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+			second:   pgtype.Range[int64]{Lower: 8, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+			expected: pgtype.Range[int64]{Lower: 5, LowerType: pgtype.Inclusive, Upper: 8, UpperType: pgtype.Exclusive, Valid: true},
+		},
+		{
+			first:    pgtype.Range[int64]{Lower: 8, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+			second:   pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+			expected: pgtype.Range[int64]{Lower: 5, LowerType: pgtype.Inclusive, Upper: 8, UpperType: pgtype.Exclusive, Valid: true},
+		},
+		{
+			first:    pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Unbounded, Upper: 5, UpperType: pgtype.Inclusive, Valid: true},
+			second:   pgtype.Range[int64]{Lower: 8, LowerType: pgtype.Exclusive, Upper: 0, UpperType: pgtype.Unbounded, Valid: true},
+			expected: pgtype.Range[int64]{Lower: 6, LowerType: pgtype.Inclusive, Upper: 9, UpperType: pgtype.Exclusive, Valid: true},
+		},
+		{
+			first:    pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+			second:   pgtype.Range[int64]{Lower: 5, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+			expected: pgtype.Range[int64]{LowerType: pgtype.Empty, UpperType: pgtype.Empty, Valid: true},
+		},
+		{
+			first:    pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 7, UpperType: pgtype.Exclusive, Valid: true},
+			second:   pgtype.Range[int64]{Lower: 5, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+			expected: pgtype.Range[int64]{LowerType: pgtype.Empty, UpperType: pgtype.Empty, Valid: true},
+		},
+		{
+			first:       pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: false},
+			second:      pgtype.Range[int64]{Lower: 8, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+			expectedErr: true,
+		},
 	}
-	if err != nil && expectedErr == nil {
-		t.Errorf("`%v` %s `%v`: expected no error, got `%v`", first, sqlOperator, second, err)
+
+	for _, tt := range tests {
+		result, err := iro.Gap(tt.first, tt.second)
+		if err == nil && tt.expectedErr {
+			t.Errorf("gap `%v` `%v`: expected error, got none", tt.first, tt.second)
+		}
+		if err != nil && !tt.expectedErr {
+			t.Errorf("gap `%v` `%v`: expected no error, got `%v`", tt.first, tt.second, err)
+		}
+		if err != nil || tt.expectedErr {
+			continue
+		}
+		if !reflect.DeepEqual(tt.expected, result) {
+			t.Errorf("gap `%v` `%v`: expected result `%v`, got `%v`", tt.first, tt.second, tt.expected, result)
+		}
 	}
-	if err != nil && expectedErr != nil {
-		return
+
+	first := pgtype.Range[time.Time]{Lower: time.Unix(100, 0), LowerType: pgtype.Inclusive, Upper: time.Unix(200, 0), UpperType: pgtype.Exclusive, Valid: true}
+	second := pgtype.Range[time.Time]{Lower: time.Unix(300, 0), LowerType: pgtype.Exclusive, Upper: time.Unix(400, 0), UpperType: pgtype.Exclusive, Valid: true}
+	expected := pgtype.Range[time.Time]{Lower: time.Unix(200, 0), LowerType: pgtype.Inclusive, Upper: time.Unix(300, 0), UpperType: pgtype.Inclusive, Valid: true}
+	result, err := tro.Gap(first, second)
+	if err != nil {
+		t.Errorf("gap `%v` `%v`: expected no error, got `%v`", first, second, err)
 	}
-	if expected != result {
-		t.Errorf("`%v` %s `%v`: expected result `%v`, got `%v`", first, sqlOperator, second, expected, result)
+	if !reflect.DeepEqual(expected, result) {
+		t.Errorf("gap `%v` `%v`: expected result `%v`, got `%v`", first, second, expected, result)
 	}
 }
 
-func binaryOperatorTest2[T any](t *testing.T, sqlOperator, sqlRangeType, sqlElementType string, first pgtype.Range[T], second T, fn func(pgtype.Range[T], T) (bool, error)) {
-	expected, expectedErr := retrieveExpected[bool](
-		fmt.Sprintf(`SELECT @first::%s %s @second::%s`, sqlRangeType, sqlOperator, sqlElementType),
-		pgx.NamedArgs{"first": first, "second": second},
-	)
-	result, err := fn(first, second)
-	if err == nil && expectedErr != nil {
-		t.Errorf("`%v` %s `%v`: expected error `%v`, got none", first, sqlOperator, second, expectedErr)
+func TestShift(t *testing.T) {
+	tests := []struct {
+		r           pgtype.Range[int64]
+		delta       int64
+		expected    pgtype.Range[int64]
+		expectedErr bool
+	}{
+		{
+			r:        pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+			delta:    3,
+			expected: pgtype.Range[int64]{Lower: 4, LowerType: pgtype.Inclusive, Upper: 8, UpperType: pgtype.Exclusive, Valid: true},
+		},
+		{
+			r:        pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Exclusive, Upper: 5, UpperType: pgtype.Inclusive, Valid: true},
+			delta:    -3,
+			expected: pgtype.Range[int64]{Lower: -2, LowerType: pgtype.Exclusive, Upper: 2, UpperType: pgtype.Inclusive, Valid: true},
+		},
+		{
+			r:        pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Unbounded, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+			delta:    10,
+			expected: pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Unbounded, Upper: 15, UpperType: pgtype.Exclusive, Valid: true},
+		},
+		{
+			r:        pgtype.Range[int64]{Lower: 5, LowerType: pgtype.Inclusive, Upper: 0, UpperType: pgtype.Unbounded, Valid: true},
+			delta:    -10,
+			expected: pgtype.Range[int64]{Lower: -5, LowerType: pgtype.Inclusive, Upper: 0, UpperType: pgtype.Unbounded, Valid: true},
+		},
+		{
+			r:        pgtype.Range[int64]{Lower: 5, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+			delta:    1,
+			expected: pgtype.Range[int64]{LowerType: pgtype.Empty, UpperType: pgtype.Empty, Valid: true},
+		},
+		{
+			r:           pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: false},
+			delta:       1,
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		result, err := iro.Shift(tt.r, tt.delta)
+		if err == nil && tt.expectedErr {
+			t.Errorf("shift `%v` by `%v`: expected error, got none", tt.r, tt.delta)
+		}
+		if err != nil && !tt.expectedErr {
+			t.Errorf("shift `%v` by `%v`: expected no error, got `%v`", tt.r, tt.delta, err)
+		}
+		if err != nil || tt.expectedErr {
+			continue
+		}
+		if !reflect.DeepEqual(tt.expected, result) {
+			t.Errorf("shift `%v` by `%v`: expected result `%v`, got `%v`", tt.r, tt.delta, tt.expected, result)
+		}
 	}
-	if err != nil && expectedErr == nil {
-		t.Errorf("`%v` %s `%v`: expected no error, got `%v`", first, sqlOperator, second, err)
+
+	// a bound that moves past the limits of the type is an error instead of wrapping around
+	for _, tt := range []struct {
+		r     pgtype.Range[int64]
+		delta int64
+	}{
+		{r: pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: math.MaxInt64, UpperType: pgtype.Exclusive, Valid: true}, delta: 10},
+		{r: pgtype.Range[int64]{Lower: math.MinInt64 + 1, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true}, delta: -10},
+		{r: pgtype.Range[int64]{LowerType: pgtype.Unbounded, Upper: math.MaxInt64 - 5, UpperType: pgtype.Exclusive, Valid: true}, delta: 10},
+	} {
+		if result, err := iro.Shift(tt.r, tt.delta); !errors.Is(err, ErrOutOfRange) {
+			t.Errorf("shift `%v` by `%v`: expected error `%v`, got `%v` (result `%v`)", tt.r, tt.delta, ErrOutOfRange, err, result)
+		}
 	}
-	if err != nil && expectedErr != nil {
-		return
+
+	timeTests := []struct {
+		r        pgtype.Range[time.Time]
+		delta    time.Duration
+		expected pgtype.Range[time.Time]
+	}{
+		{
+			r:        pgtype.Range[time.Time]{Lower: time.Unix(100, 0), LowerType: pgtype.Inclusive, Upper: time.Unix(200, 0), UpperType: pgtype.Exclusive, Valid: true},
+			delta:    time.Minute,
+			expected: pgtype.Range[time.Time]{Lower: time.Unix(160, 0), LowerType: pgtype.Inclusive, Upper: time.Unix(260, 0), UpperType: pgtype.Exclusive, Valid: true},
+		},
+		{
+			r:        pgtype.Range[time.Time]{Lower: time.Unix(100, 0), LowerType: pgtype.Exclusive, UpperType: pgtype.Unbounded, Valid: true},
+			delta:    -time.Minute,
+			expected: pgtype.Range[time.Time]{Lower: time.Unix(40, 0), LowerType: pgtype.Exclusive, UpperType: pgtype.Unbounded, Valid: true},
+		},
 	}
-	if expected != result {
-		t.Errorf("`%v` %s `%v`: expected result `%v`, got `%v`", first, sqlOperator, second, expected, result)
+
+	for _, tt := range timeTests {
+		result, err := tro.Shift(tt.r, tt.delta)
+		if err != nil {
+			t.Errorf("shift `%v` by `%v`: expected no error, got `%v`", tt.r, tt.delta, err)
+			continue
+		}
+		if !reflect.DeepEqual(tt.expected, result) {
+			t.Errorf("shift `%v` by `%v`: expected result `%v`, got `%v`", tt.r, tt.delta, tt.expected, result)
+		}
 	}
 }
 
-func binaryOperatorTest3[T any](t *testing.T, sqlOperator, sqlRangeType string, first, second pgtype.Range[T], fn func(pgtype.Range[T], pgtype.Range[T]) (pgtype.Range[T], error)) {
-	expected, expectedErr := retrieveExpected[pgtype.Range[T]](
-		fmt.Sprintf(`SELECT @first::%s %s @second::%s`, sqlRangeType, sqlOperator, sqlRangeType),
-		pgx.NamedArgs{"first": first, "second": second},
-	)
-	result, err := fn(first, second)
-	if err == nil && expectedErr != nil {
-		t.Errorf("`%v` %s `%v`: expected error `%v`, got none", first, sqlOperator, second, expectedErr)
-	}
-	if err != nil && expectedErr == nil {
-		t.Errorf("`%v` %s `%v`: expected no error, got `%v`", first, sqlOperator, second, err)
+func TestExpand(t *testing.T) {
+	tests := []struct {
+		r           pgtype.Range[int64]
+		amount      int64
+		expected    pgtype.Range[int64]
+		expectedErr bool
+	}{
+		{
+			r:        pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+			amount:   2,
+			expected: pgtype.Range[int64]{Lower: -1, LowerType: pgtype.Inclusive, Upper: 7, UpperType: pgtype.Exclusive, Valid: true},
+		},
+		{
+			r:        pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 9, UpperType: pgtype.Exclusive, Valid: true},
+			amount:   -2,
+			expected: pgtype.Range[int64]{Lower: 3, LowerType: pgtype.Inclusive, Upper: 7, UpperType: pgtype.Exclusive, Valid: true},
+		},
+		{
+			r:        pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+			amount:   -2,
+			expected: pgtype.Range[int64]{LowerType: pgtype.Empty, UpperType: pgtype.Empty, Valid: true},
+		},
+		{
+			r:        pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+			amount:   -10,
+			expected: pgtype.Range[int64]{LowerType: pgtype.Empty, UpperType: pgtype.Empty, Valid: true},
+		},
+		{
+			r:        pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Unbounded, Upper: 5, UpperType: pgtype.Inclusive, Valid: true},
+			amount:   -10,
+			expected: pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Unbounded, Upper: -5, UpperType: pgtype.Inclusive, Valid: true},
+		},
+		{
+			r:           pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: false},
+			amount:      1,
+			expectedErr: true,
+		},
 	}
-	if err != nil && expectedErr != nil {
-		return
+
+	for _, tt := range tests {
+		result, err := iro.Expand(tt.r, tt.amount)
+		if err == nil && tt.expectedErr {
+			t.Errorf("expand `%v` by `%v`: expected error, got none", tt.r, tt.amount)
+		}
+		if err != nil && !tt.expectedErr {
+			t.Errorf("expand `%v` by `%v`: expected no error, got `%v`", tt.r, tt.amount, err)
+		}
+		if err != nil || tt.expectedErr {
+			continue
+		}
+		if !reflect.DeepEqual(tt.expected, result) {
+			t.Errorf("expand `%v` by `%v`: expected result `%v`, got `%v`", tt.r, tt.amount, tt.expected, result)
+		}
 	}
-	if !reflect.DeepEqual(expected, result) {
-		t.Errorf("`%v` %s `%v`: expected result `%v`, got `%v`", first, sqlOperator, second, expected, result)
+
+	// a bound that moves past the limits of the type is an error instead of wrapping around
+	for _, tt := range []struct {
+		r      pgtype.Range[int64]
+		amount int64
+	}{
+		{r: pgtype.Range[int64]{Lower: math.MinInt64 + 1, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true}, amount: 10},
+		{r: pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: math.MaxInt64 - 1, UpperType: pgtype.Exclusive, Valid: true}, amount: 10},
+		{r: pgtype.Range[int64]{Lower: math.MinInt64 + 1, LowerType: pgtype.Inclusive, Upper: math.MaxInt64 - 1, UpperType: pgtype.Exclusive, Valid: true}, amount: 2},
+	} {
+		if result, err := iro.Expand(tt.r, tt.amount); !errors.Is(err, ErrOutOfRange) {
+			t.Errorf("expand `%v` by `%v`: expected error `%v`, got `%v` (result `%v`)", tt.r, tt.amount, ErrOutOfRange, err, result)
+		}
 	}
-}
 
-func binaryFunctionTest[T any](t *testing.T, sqlFunction, sqlRangeType string, first, second pgtype.Range[T], fn func(pgtype.Range[T], pgtype.Range[T]) (pgtype.Range[T], error)) {
-	expected, expectedErr := retrieveExpected[pgtype.Range[T]](
-		fmt.Sprintf(`SELECT %s(@first::%s, @second::%s)`, sqlFunction, sqlRangeType, sqlRangeType),
-		pgx.NamedArgs{"first": first, "second": second},
-	)
-	result, err := fn(first, second)
-	if err == nil && expectedErr != nil {
-		t.Errorf("%s(`%v`, `%v`): expected error `%v`, got none", sqlFunction, first, second, expectedErr)
+	meeting := pgtype.Range[time.Time]{Lower: time.Unix(3600, 0), LowerType: pgtype.Inclusive, Upper: time.Unix(7200, 0), UpperType: pgtype.Exclusive, Valid: true}
+	expected := pgtype.Range[time.Time]{Lower: time.Unix(2700, 0), LowerType: pgtype.Inclusive, Upper: time.Unix(8100, 0), UpperType: pgtype.Exclusive, Valid: true}
+	result, err := tro.Expand(meeting, 15*time.Minute)
+	if err != nil {
+		t.Errorf("expand `%v` by `%v`: expected no error, got `%v`", meeting, 15*time.Minute, err)
 	}
-	if err != nil && expectedErr == nil {
-		t.Errorf("%s(`%v`, `%v`): expected no error, got `%v`", sqlFunction, first, second, err)
+	if !reflect.DeepEqual(expected, result) {
+		t.Errorf("expand `%v` by `%v`: expected result `%v`, got `%v`", meeting, 15*time.Minute, expected, result)
 	}
-	if err != nil && expectedErr != nil {
-		return
+
+	result, err = tro.Contract(expected, 15*time.Minute)
+	if err != nil {
+		t.Errorf("contract `%v` by `%v`: expected no error, got `%v`", expected, 15*time.Minute, err)
 	}
-	if !reflect.DeepEqual(expected, result) {
-		t.Errorf("%s(`%v`, `%v`): expected result `%v`, got `%v`", sqlFunction, first, second, expected, result)
+	if !reflect.DeepEqual(meeting, result) {
+		t.Errorf("contract `%v` by `%v`: expected result `%v`, got `%v`", expected, 15*time.Minute, meeting, result)
 	}
 }
 
-func retrieveExpected[T any](query string, args pgx.NamedArgs) (T, error) {
-	rows, err := conn.Query(
-		context.Background(),
-		query,
-		args,
-	)
-	if err != nil {
-		return *new(T), fmt.Errorf("excuting query failed: %v", err)
+func TestClamp(t *testing.T) {
+	tests := []struct {
+		r           pgtype.Range[int64]
+		v           int64
+		expected    int64
+		expectedErr bool
+	}{
+		{
+			r:        pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+			v:        3,
+			expected: 3,
+		},
+		{
+			r:        pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+			v:        -3,
+			expected: 1,
+		},
+		{
+			r:        pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+			v:        5,
+			expected: 4,
+		},
+		{
+			r:        pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Exclusive, Upper: 5, UpperType: pgtype.Inclusive, Valid: true},
+			v:        1,
+			expected: 2,
+		},
+		{
+			r:        pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Exclusive, Upper: 5, UpperType: pgtype.Inclusive, Valid: true},
+			v:        10,
+			expected: 5,
+		},
+		{
+			r:        pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Unbounded, Upper: 5, UpperType: pgtype.Inclusive, Valid: true},
+			v:        -100,
+			expected: -100,
+		},
+		{
+			r:           pgtype.Range[int64]{Lower: 5, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+			v:           5,
+			expectedErr: true,
+		},
+		{
+			r:           pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: false},
+			v:           3,
+			expectedErr: true,
+		},
 	}
-	defer rows.Close()
-	expected, err := pgx.CollectExactlyOneRow(rows, pgx.RowTo[T])
-	if err != nil {
-		return *new(T), fmt.Errorf("collecting the row failed: %v", err)
+
+	for _, tt := range tests {
+		result, err := iro.Clamp(tt.r, tt.v)
+		if err == nil && tt.expectedErr {
+			t.Errorf("clamp `%v` to `%v`: expected error, got none", tt.v, tt.r)
+		}
+		if err != nil && !tt.expectedErr {
+			t.Errorf("clamp `%v` to `%v`: expected no error, got `%v`", tt.v, tt.r, err)
+		}
+		if err != nil || tt.expectedErr {
+			continue
+		}
+		if tt.expected != result {
+			t.Errorf("clamp `%v` to `%v`: expected result `%v`, got `%v`", tt.v, tt.r, tt.expected, result)
+		}
+	}
+
+	timeTests := []struct {
+		r           pgtype.Range[time.Time]
+		v           time.Time
+		expected    time.Time
+		expectedErr bool
+	}{
+		{
+			r:        pgtype.Range[time.Time]{Lower: time.Unix(100, 0), LowerType: pgtype.Inclusive, Upper: time.Unix(200, 0), UpperType: pgtype.Inclusive, Valid: true},
+			v:        time.Unix(50, 0),
+			expected: time.Unix(100, 0),
+		},
+		{
+			r:        pgtype.Range[time.Time]{Lower: time.Unix(100, 0), LowerType: pgtype.Inclusive, Upper: time.Unix(200, 0), UpperType: pgtype.Inclusive, Valid: true},
+			v:        time.Unix(250, 0),
+			expected: time.Unix(200, 0),
+		},
+		{
+			r:        pgtype.Range[time.Time]{Lower: time.Unix(100, 0), LowerType: pgtype.Exclusive, Upper: time.Unix(200, 0), UpperType: pgtype.Exclusive, Valid: true},
+			v:        time.Unix(150, 0),
+			expected: time.Unix(150, 0),
+		},
+		{
+			r:           pgtype.Range[time.Time]{Lower: time.Unix(100, 0), LowerType: pgtype.Exclusive, Upper: time.Unix(200, 0), UpperType: pgtype.Exclusive, Valid: true},
+			v:           time.Unix(50, 0),
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range timeTests {
+		result, err := tro.Clamp(tt.r, tt.v)
+		if err == nil && tt.expectedErr {
+			t.Errorf("clamp `%v` to `%v`: expected error, got none", tt.v, tt.r)
+		}
+		if err != nil && !tt.expectedErr {
+			t.Errorf("clamp `%v` to `%v`: expected no error, got `%v`", tt.v, tt.r, err)
+		}
+		if err != nil || tt.expectedErr {
+			continue
+		}
+		if !tt.expected.Equal(result) {
+			t.Errorf("clamp `%v` to `%v`: expected result `%v`, got `%v`", tt.v, tt.r, tt.expected, result)
+		}
+	}
+}
+
+func TestMidpoint(t *testing.T) {
+	tests := []struct {
+		r           pgtype.Range[int64]
+		expected    int64
+		expectedErr bool
+	}{
+		{
+			r:        pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+			expected: 5,
+		},
+		{
+			r:        pgtype.Range[int64]{Lower: -1, LowerType: pgtype.Exclusive, Upper: 9, UpperType: pgtype.Inclusive, Valid: true},
+			expected: 5,
+		},
+		{
+			r:        pgtype.Range[int64]{Lower: 3, LowerType: pgtype.Inclusive, Upper: 3, UpperType: pgtype.Inclusive, Valid: true},
+			expected: 3,
+		},
+		{
+			r:        pgtype.Range[int64]{Lower: math.MinInt64 + 2, LowerType: pgtype.Inclusive, Upper: math.MaxInt64 - 2, UpperType: pgtype.Exclusive, Valid: true},
+			expected: -1,
+		},
+		{
+			r:        pgtype.Range[int64]{Lower: math.MinInt64, LowerType: pgtype.Inclusive, Upper: math.MaxInt64, UpperType: pgtype.Inclusive, Valid: true},
+			expected: -1,
+		},
+		{
+			r:        pgtype.Range[int64]{Lower: 10, LowerType: pgtype.Inclusive, Upper: math.MaxInt64, UpperType: pgtype.Exclusive, Valid: true},
+			expected: 10 + (math.MaxInt64-10)/2,
+		},
+		{
+			r:           pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Unbounded, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+			expectedErr: true,
+		},
+		{
+			r:           pgtype.Range[int64]{Lower: 3, LowerType: pgtype.Inclusive, Upper: 3, UpperType: pgtype.Exclusive, Valid: true},
+			expectedErr: true,
+		},
+		{
+			r:           pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: false},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		result, err := iro.Midpoint(tt.r)
+		if err == nil && tt.expectedErr {
+			t.Errorf("midpoint `%v`: expected error, got none", tt.r)
+		}
+		if err != nil && !tt.expectedErr {
+			t.Errorf("midpoint `%v`: expected no error, got `%v`", tt.r, err)
+		}
+		if err != nil || tt.expectedErr {
+			continue
+		}
+		if tt.expected != result {
+			t.Errorf("midpoint `%v`: expected result `%v`, got `%v`", tt.r, tt.expected, result)
+		}
+	}
+
+	r := pgtype.Range[time.Time]{Lower: time.Unix(3600, 0), LowerType: pgtype.Inclusive, Upper: time.Unix(7200, 0), UpperType: pgtype.Exclusive, Valid: true}
+	result, err := tro.Midpoint(r)
+	if err != nil {
+		t.Errorf("midpoint `%v`: expected no error, got `%v`", r, err)
+	}
+	if !time.Unix(5400, 0).Equal(result) {
+		t.Errorf("midpoint `%v`: expected result `%v`, got `%v`", r, time.Unix(5400, 0), result)
+	}
+}
+
+func TestPartition(t *testing.T) {
+	tests := []struct {
+		r           pgtype.Range[int64]
+		n           int
+		expectedErr bool
+	}{
+		{r: pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true}, n: 1},
+		{r: pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true}, n: 3},
+		{r: pgtype.Range[int64]{Lower: -7, LowerType: pgtype.Exclusive, Upper: 13, UpperType: pgtype.Inclusive, Valid: true}, n: 7},
+		{r: pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Inclusive, Upper: 4, UpperType: pgtype.Inclusive, Valid: true}, n: 5},
+		{r: pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Inclusive, Upper: 4, UpperType: pgtype.Inclusive, Valid: true}, n: 6, expectedErr: true},
+		{r: pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true}, n: 0, expectedErr: true},
+		{r: pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Inclusive, Upper: 0, UpperType: pgtype.Exclusive, Valid: true}, n: 1, expectedErr: true},
+		{r: pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Unbounded, Upper: 10, UpperType: pgtype.Exclusive, Valid: true}, n: 2, expectedErr: true},
+	}
+
+	for _, tt := range tests {
+		result, err := iro.Partition(tt.r, tt.n)
+		if err == nil && tt.expectedErr {
+			t.Errorf("partition `%v` in `%d`: expected error, got none", tt.r, tt.n)
+		}
+		if err != nil && !tt.expectedErr {
+			t.Errorf("partition `%v` in `%d`: expected no error, got `%v`", tt.r, tt.n, err)
+		}
+		if err != nil || tt.expectedErr {
+			continue
+		}
+		if len(result) != tt.n {
+			t.Errorf("partition `%v` in `%d`: expected `%d` subranges, got `%d`", tt.r, tt.n, tt.n, len(result))
+			continue
+		}
+
+		union := makeEmptyRange[int64]()
+		var count int64
+		for _, p := range result {
+			if union, err = iro.Union(union, p); err != nil {
+				t.Errorf("partition `%v` in `%d`: subranges are not contiguous: `%v`", tt.r, tt.n, result)
+				break
+			}
+			size, _ := iro.Size(p)
+			count += size
+		}
+		if equal, _ := iro.Equal(tt.r, union); !equal {
+			t.Errorf("partition `%v` in `%d`: expected union of subranges to be the range, got `%v`", tt.r, tt.n, union)
+		}
+		if expected, _ := iro.Size(tt.r); expected != count {
+			t.Errorf("partition `%v` in `%d`: expected `%d` elements, got `%d`", tt.r, tt.n, expected, count)
+		}
+	}
+
+	r := pgtype.Range[time.Time]{Lower: time.Unix(0, 0), LowerType: pgtype.Exclusive, Upper: time.Unix(3600, 0), UpperType: pgtype.Inclusive, Valid: true}
+	result, err := tro.Partition(r, 4)
+	if err != nil {
+		t.Errorf("partition `%v` in `%d`: expected no error, got `%v`", r, 4, err)
+	}
+	expected := []pgtype.Range[time.Time]{
+		{Lower: time.Unix(0, 0), LowerType: pgtype.Exclusive, Upper: time.Unix(900, 0), UpperType: pgtype.Exclusive, Valid: true},
+		{Lower: time.Unix(900, 0), LowerType: pgtype.Inclusive, Upper: time.Unix(1800, 0), UpperType: pgtype.Exclusive, Valid: true},
+		{Lower: time.Unix(1800, 0), LowerType: pgtype.Inclusive, Upper: time.Unix(2700, 0), UpperType: pgtype.Exclusive, Valid: true},
+		{Lower: time.Unix(2700, 0), LowerType: pgtype.Inclusive, Upper: time.Unix(3600, 0), UpperType: pgtype.Inclusive, Valid: true},
+	}
+	if !reflect.DeepEqual(expected, result) {
+		t.Errorf("partition `%v` in `%d`: expected result `%v`, got `%v`", r, 4, expected, result)
+	}
+}
+
+func TestSplitAt(t *testing.T) {
+	tests := []struct {
+		r           pgtype.Range[int64]
+		at          int64
+		expected    []pgtype.Range[int64]
+		expectedErr bool
+	}{
+		{
+			r:  pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+			at: 4,
+			expected: []pgtype.Range[int64]{
+				{Lower: 0, LowerType: pgtype.Inclusive, Upper: 4, UpperType: pgtype.Exclusive, Valid: true},
+				{Lower: 4, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+			},
+		},
+		{
+			r:        pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+			at:       0,
+			expected: []pgtype.Range[int64]{{Lower: 0, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true}},
+		},
+		{
+			r:        pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+			at:       10,
+			expected: []pgtype.Range[int64]{{Lower: 0, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true}},
+		},
+		{
+			r:        pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+			at:       -5,
+			expected: []pgtype.Range[int64]{{Lower: 0, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true}},
+		},
+		{
+			// 1 is the first element of (0,10]
+			r:        pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Exclusive, Upper: 10, UpperType: pgtype.Inclusive, Valid: true},
+			at:       1,
+			expected: []pgtype.Range[int64]{{Lower: 0, LowerType: pgtype.Exclusive, Upper: 10, UpperType: pgtype.Inclusive, Valid: true}},
+		},
+		{
+			r:        pgtype.Range[int64]{LowerType: pgtype.Unbounded, Upper: 10, UpperType: pgtype.Inclusive, Valid: true},
+			at:       10,
+			expected: []pgtype.Range[int64]{{LowerType: pgtype.Unbounded, Upper: 10, UpperType: pgtype.Inclusive, Valid: true}},
+		},
+		{
+			r:        pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Inclusive, Valid: true},
+			at:       10,
+			expected: []pgtype.Range[int64]{{Lower: 0, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Inclusive, Valid: true}},
+		},
+		{
+			r:  pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Inclusive, Valid: true},
+			at: 9,
+			expected: []pgtype.Range[int64]{
+				{Lower: 0, LowerType: pgtype.Inclusive, Upper: 9, UpperType: pgtype.Exclusive, Valid: true},
+				{Lower: 9, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Inclusive, Valid: true},
+			},
+		},
+		{
+			r:           pgtype.Range[int64]{LowerType: pgtype.Empty, UpperType: pgtype.Empty, Valid: true},
+			at:          4,
+			expectedErr: true,
+		},
+		{
+			r:           pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive},
+			at:          4,
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		result, err := iro.SplitAt(tt.r, tt.at)
+		if err == nil && tt.expectedErr {
+			t.Errorf("split `%v` at `%v`: expected error, got none", tt.r, tt.at)
+		}
+		if err != nil && !tt.expectedErr {
+			t.Errorf("split `%v` at `%v`: expected no error, got `%v`", tt.r, tt.at, err)
+		}
+		if err != nil || tt.expectedErr {
+			continue
+		}
+		if !reflect.DeepEqual(tt.expected, result) {
+			t.Errorf("split `%v` at `%v`: expected result `%v`, got `%v`", tt.r, tt.at, tt.expected, result)
+		}
+	}
+
+	r := pgtype.Range[time.Time]{Lower: time.Unix(0, 0), LowerType: pgtype.Inclusive, Upper: time.Unix(3600, 0), UpperType: pgtype.Exclusive, Valid: true}
+	result, err := tro.SplitAt(r, time.Unix(600, 0))
+	if err != nil {
+		t.Errorf("split `%v` at `%v`: expected no error, got `%v`", r, time.Unix(600, 0), err)
+	}
+	expected := []pgtype.Range[time.Time]{
+		{Lower: time.Unix(0, 0), LowerType: pgtype.Inclusive, Upper: time.Unix(600, 0), UpperType: pgtype.Exclusive, Valid: true},
+		{Lower: time.Unix(600, 0), LowerType: pgtype.Inclusive, Upper: time.Unix(3600, 0), UpperType: pgtype.Exclusive, Valid: true},
+	}
+	if !reflect.DeepEqual(expected, result) {
+		t.Errorf("split `%v` at `%v`: expected result `%v`, got `%v`", r, time.Unix(600, 0), expected, result)
+	}
+
+	// an inclusive upper bound is not strictly inside the range
+	r.UpperType = pgtype.Inclusive
+	if result, err := tro.SplitAt(r, r.Upper); err != nil || !reflect.DeepEqual([]pgtype.Range[time.Time]{r}, result) {
+		t.Errorf("split `%v` at `%v`: expected result `%v`, got `%v` (error `%v`)", r, r.Upper, []pgtype.Range[time.Time]{r}, result, err)
+	}
+}
+
+func TestElements(t *testing.T) {
+	tests := []struct {
+		r           pgtype.Range[int64]
+		expected    []int64
+		expectedErr bool
+	}{
+		{
+			r:        pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+			expected: []int64{1, 2, 3, 4},
+		},
+		{
+			r:        pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Exclusive, Upper: 5, UpperType: pgtype.Inclusive, Valid: true},
+			expected: []int64{2, 3, 4, 5},
+		},
+		{
+			r:        pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Exclusive, Upper: 2, UpperType: pgtype.Exclusive, Valid: true},
+			expected: nil,
+		},
+		{
+			r:        pgtype.Range[int64]{Lower: math.MaxInt64 - 1, LowerType: pgtype.Inclusive, Upper: math.MaxInt64, UpperType: pgtype.Inclusive, Valid: true},
+			expected: []int64{math.MaxInt64 - 1, math.MaxInt64},
+		},
+		{
+			r:        pgtype.Range[int64]{Lower: math.MaxInt64 - 1, LowerType: pgtype.Exclusive, Upper: math.MaxInt64, UpperType: pgtype.Inclusive, Valid: true},
+			expected: []int64{math.MaxInt64},
+		},
+		{
+			r:           pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 0, UpperType: pgtype.Unbounded, Valid: true},
+			expectedErr: true,
+		},
+		{
+			r:           pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: false},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		result, err := iro.Elements(tt.r)
+		if err == nil && tt.expectedErr {
+			t.Errorf("elements `%v`: expected error, got none", tt.r)
+		}
+		if err != nil && !tt.expectedErr {
+			t.Errorf("elements `%v`: expected no error, got `%v`", tt.r, err)
+		}
+		if err != nil || tt.expectedErr {
+			continue
+		}
+		if elements := slices.Collect(result); !reflect.DeepEqual(tt.expected, elements) {
+			t.Errorf("elements `%v`: expected result `%v`, got `%v`", tt.r, tt.expected, elements)
+		}
+	}
+
+	r := pgtype.Range[time.Time]{Lower: time.Unix(0, 0), LowerType: pgtype.Inclusive, Upper: time.Unix(1, 0), UpperType: pgtype.Exclusive, Valid: true}
+	if _, err := tro.Elements(r); err == nil {
+		t.Errorf("elements `%v`: expected error, got none", r)
+	}
+
+	large := pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Inclusive, Upper: 1_000_000, UpperType: pgtype.Exclusive, Valid: true}
+	allocs := testing.AllocsPerRun(10, func() {
+		elements, _ := iro.Elements(large)
+		for range elements {
+		}
+	})
+	if allocs > 10 {
+		t.Errorf("elements `%v`: expected a constant number of allocations, got `%v`", large, allocs)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	valid := pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true}
+	invalid := pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: false}
+	unbounded := pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, UpperType: pgtype.Unbounded, Valid: true}
+	empty := pgtype.Range[int64]{LowerType: pgtype.Empty, UpperType: pgtype.Empty, Valid: true}
+	far := pgtype.Range[int64]{Lower: 10, LowerType: pgtype.Inclusive, Upper: 15, UpperType: pgtype.Exclusive, Valid: true}
+	inner := pgtype.Range[int64]{Lower: 2, LowerType: pgtype.Inclusive, Upper: 3, UpperType: pgtype.Exclusive, Valid: true}
+
+	tests := []struct {
+		name     string
+		fn       func() error
+		expected error
+	}{
+		{"empty", func() error { _, err := iro.Empty(invalid); return err }, ErrInvalidRange},
+		{"equal", func() error { _, err := iro.Equal(invalid, valid); return err }, ErrInvalidRange},
+		{"overlap", func() error { _, err := iro.Overlap(valid, invalid); return err }, ErrInvalidRange},
+		{"union invalid", func() error { _, err := iro.Union(invalid, valid); return err }, ErrInvalidRange},
+		{"union", func() error { _, err := iro.Union(valid, far); return err }, ErrNotContiguous},
+		{"difference invalid", func() error { _, err := iro.Difference(valid, invalid); return err }, ErrInvalidRange},
+		{"difference", func() error { _, err := iro.Difference(valid, inner); return err }, ErrNotContiguous},
+		{"size invalid", func() error { _, err := iro.Size(invalid); return err }, ErrInvalidRange},
+		{"size", func() error { _, err := iro.Size(unbounded); return err }, ErrUnboundedRange},
+		{"midpoint unbounded", func() error { _, err := iro.Midpoint(unbounded); return err }, ErrUnboundedRange},
+		{"midpoint empty", func() error { _, err := iro.Midpoint(empty); return err }, ErrEmptyUndefined},
+		{"clamp", func() error { _, err := iro.Clamp(empty, 1); return err }, ErrEmptyUndefined},
+		{"partition", func() error { _, err := iro.Partition(empty, 1); return err }, ErrEmptyUndefined},
+		{"split at", func() error { _, err := iro.SplitAt(empty, 1); return err }, ErrEmptyUndefined},
+	}
+
+	for _, tt := range tests {
+		if err := tt.fn(); !errors.Is(err, tt.expected) {
+			t.Errorf("%s: expected error `%v`, got `%v`", tt.name, tt.expected, err)
+		}
+	}
+}
+
+func TestEmpty(t *testing.T) {
+	tests := []struct {
+		r           pgtype.Range[int64]
+		expected    bool
+		expectedErr error
+	}{
+		{
+			r:        pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Unbounded, Upper: 0, UpperType: pgtype.Unbounded, Valid: true},
+			expected: false,
+		},
+		{
+			r:        pgtype.Range[int64]{Lower: 5, LowerType: pgtype.Inclusive, Upper: 0, UpperType: pgtype.Unbounded, Valid: true},
+			expected: false,
+		},
+		{
+			r:        pgtype.Range[int64]{Lower: 5, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+			expected: true,
+		},
+		{
+			r:        pgtype.Range[int64]{LowerType: pgtype.Empty, UpperType: pgtype.Empty, Valid: true},
+			expected: true,
+		},
+		{
+			r:           pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Unbounded, Upper: 5, UpperType: pgtype.Exclusive, Valid: false},
+			expectedErr: ErrInvalidRange,
+		},
+	}
+
+	for _, tt := range tests {
+		// calling Empty repeatedly should always give the same result
+		for range 2 {
+			result, err := iro.Empty(tt.r)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("empty `%v`: expected error `%v`, got `%v`", tt.r, tt.expectedErr, err)
+			}
+			if tt.expected != result {
+				t.Errorf("empty `%v`: expected result `%v`, got `%v`", tt.r, tt.expected, result)
+			}
+		}
+	}
+}
+
+func TestInt4range(t *testing.T) {
+	ro := NewInt32()
+	tests := []struct {
+		first  pgtype.Range[int32]
+		second pgtype.Range[int32]
+	}{
+		{
+			first:  pgtype.Range[int32]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+			second: pgtype.Range[int32]{Lower: 4, LowerType: pgtype.Exclusive, Upper: 10, UpperType: pgtype.Inclusive, Valid: true},
+		},
+		{
+			first:  pgtype.Range[int32]{Lower: math.MaxInt32 - 10, LowerType: pgtype.Inclusive, Upper: math.MaxInt32 - 5, UpperType: pgtype.Inclusive, Valid: true},
+			second: pgtype.Range[int32]{Lower: math.MaxInt32 - 5, LowerType: pgtype.Exclusive, Upper: math.MaxInt32, UpperType: pgtype.Exclusive, Valid: true},
+		},
+		{
+			first:  pgtype.Range[int32]{Lower: math.MaxInt32 - 10, LowerType: pgtype.Exclusive, Upper: math.MaxInt32 - 1, UpperType: pgtype.Inclusive, Valid: true},
+			second: pgtype.Range[int32]{Lower: math.MaxInt32 - 1, LowerType: pgtype.Inclusive, UpperType: pgtype.Unbounded, Valid: true},
+		},
+		{
+			first:  pgtype.Range[int32]{Lower: math.MinInt32, LowerType: pgtype.Inclusive, Upper: math.MinInt32 + 5, UpperType: pgtype.Exclusive, Valid: true},
+			second: pgtype.Range[int32]{LowerType: pgtype.Unbounded, Upper: math.MinInt32 + 5, UpperType: pgtype.Exclusive, Valid: true},
+		},
+	}
+
+	for _, tt := range tests {
+		protest.CheckBoolOperator(t, conn, "-|-", "int4range", tt.first, tt.second, ro.Adjacent)
+		protest.CheckBoolOperator(t, conn, "&&", "int4range", tt.first, tt.second, ro.Overlap)
+
+		for _, r := range []pgtype.Range[int32]{tt.first, tt.second} {
+			if r.LowerType == pgtype.Unbounded || r.UpperType == pgtype.Unbounded {
+				continue
+			}
+			expected, expectedErr := protest.RetrieveExpected[int32](
+				conn,
+				`SELECT upper(@r::int4range) - lower(@r::int4range)`,
+				pgx.NamedArgs{"r": r},
+			)
+			result, err := ro.Size(r)
+			if err != nil || expectedErr != nil {
+				t.Errorf("size `%v`: expected no errors, got `%v` and `%v`", r, err, expectedErr)
+				continue
+			}
+			if expected != result {
+				t.Errorf("size `%v`: expected result `%v`, got `%v`", r, expected, result)
+			}
+		}
+	}
+}
+
+func TestNarrowIntegers(t *testing.T) {
+	i16 := NewInt16()
+	r16 := pgtype.Range[int16]{Lower: math.MaxInt16 - 10, LowerType: pgtype.Exclusive, Upper: math.MaxInt16 - 1, UpperType: pgtype.Inclusive, Valid: true}
+	if size, err := i16.Size(r16); err != nil || size != 9 {
+		t.Errorf("size `%v`: expected result `%v`, got `%v` (error `%v`)", r16, 9, size, err)
+	}
+
+	u32 := NewUint32()
+	first := pgtype.Range[uint32]{Lower: 0, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true}
+	second := pgtype.Range[uint32]{Lower: 4, LowerType: pgtype.Exclusive, Upper: math.MaxUint32 - 1, UpperType: pgtype.Inclusive, Valid: true}
+	if adjacent, err := u32.Adjacent(first, second); err != nil || !adjacent {
+		t.Errorf("`%v` -|- `%v`: expected result `%v`, got `%v` (error `%v`)", first, second, true, adjacent, err)
+	}
+	if size, err := u32.Size(second); err != nil || size != math.MaxUint32-5 {
+		t.Errorf("size `%v`: expected result `%v`, got `%v` (error `%v`)", second, math.MaxUint32-5, size, err)
+	}
+	empty := pgtype.Range[uint32]{Lower: 5, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true}
+	if e, err := u32.Empty(empty); err != nil || !e {
+		t.Errorf("empty `%v`: expected result `%v`, got `%v` (error `%v`)", empty, true, e, err)
+	}
+}
+
+func TestSizeBig(t *testing.T) {
+	tests := []struct {
+		r           pgtype.Range[int64]
+		expected    string
+		expectedErr bool
+	}{
+		{
+			r:           pgtype.Range[int64]{Lower: 3, LowerType: pgtype.Inclusive, Upper: 6, UpperType: pgtype.Inclusive, Valid: false},
+			expectedErr: true,
+		},
+		{
+			r:           pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Unbounded, Upper: 6, UpperType: pgtype.Inclusive, Valid: true},
+			expectedErr: true,
+		},
+		{
+			r:        pgtype.Range[int64]{Lower: 3, LowerType: pgtype.Inclusive, Upper: 6, UpperType: pgtype.Inclusive, Valid: true},
+			expected: "4",
+		},
+		{
+			r:        pgtype.Range[int64]{Lower: 3, LowerType: pgtype.Exclusive, Upper: 6, UpperType: pgtype.Exclusive, Valid: true},
+			expected: "2",
+		},
+		{
+			r:        pgtype.Range[int64]{Lower: math.MinInt64, LowerType: pgtype.Inclusive, Upper: math.MaxInt64, UpperType: pgtype.Exclusive, Valid: true},
+			expected: "18446744073709551615",
+		},
+		{
+			r:        pgtype.Range[int64]{Lower: math.MinInt64, LowerType: pgtype.Inclusive, Upper: math.MaxInt64, UpperType: pgtype.Inclusive, Valid: true},
+			expected: "18446744073709551616",
+		},
+		{
+			r:        pgtype.Range[int64]{Lower: -1, LowerType: pgtype.Inclusive, Upper: math.MaxInt64, UpperType: pgtype.Exclusive, Valid: true},
+			expected: "9223372036854775808",
+		},
+	}
+
+	for _, tt := range tests {
+		result, err := iro.SizeBig(tt.r)
+		if err == nil && tt.expectedErr {
+			t.Errorf("size `%v`: expected error, got none", tt.r)
+		}
+		if err != nil && !tt.expectedErr {
+			t.Errorf("size `%v`: expected no error, got `%v`", tt.r, err)
+		}
+		if err != nil || tt.expectedErr {
+			continue
+		}
+		if tt.expected != result.String() {
+			t.Errorf("size `%v`: expected result `%v`, got `%v`", tt.r, tt.expected, result)
+		}
+	}
+}
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		first       pgtype.Range[int64]
+		second      pgtype.Range[int64]
+		expected    int64
+		expectedErr bool
+	}{
+		{
+			first:    pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+			second:   pgtype.Range[int64]{Lower: 8, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+			expected: 3,
+		},
+		{
+			first:    pgtype.Range[int64]{Lower: 20, LowerType: pgtype.Exclusive, UpperType: pgtype.Unbounded, Valid: true},
+			second:   pgtype.Range[int64]{LowerType: pgtype.Unbounded, Upper: 10, UpperType: pgtype.Inclusive, Valid: true},
+			expected: 10,
+		},
+		{
+			first:    pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+			second:   pgtype.Range[int64]{Lower: 5, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+			expected: 0,
+		},
+		{
+			first:    pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 7, UpperType: pgtype.Exclusive, Valid: true},
+			second:   pgtype.Range[int64]{Lower: 5, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+			expected: 0,
+		},
+		{
+			first:       pgtype.Range[int64]{LowerType: pgtype.Empty, UpperType: pgtype.Empty, Valid: true},
+			second:      pgtype.Range[int64]{Lower: 5, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		result, err := iro.Distance(tt.first, tt.second)
+		if err == nil && tt.expectedErr {
+			t.Errorf("distance `%v` `%v`: expected error, got none", tt.first, tt.second)
+		}
+		if err != nil && !tt.expectedErr {
+			t.Errorf("distance `%v` `%v`: expected no error, got `%v`", tt.first, tt.second, err)
+		}
+		if err != nil || tt.expectedErr {
+			continue
+		}
+		if tt.expected != result {
+			t.Errorf("distance `%v` `%v`: expected result `%v`, got `%v`", tt.first, tt.second, tt.expected, result)
+		}
+	}
+
+	first := pgtype.Range[time.Time]{Lower: time.Unix(0, 0), LowerType: pgtype.Inclusive, Upper: time.Unix(3600, 0), UpperType: pgtype.Exclusive, Valid: true}
+	second := pgtype.Range[time.Time]{Lower: time.Unix(5400, 0), LowerType: pgtype.Inclusive, Upper: time.Unix(7200, 0), UpperType: pgtype.Exclusive, Valid: true}
+	result, err := tro.Distance(first, second)
+	if err != nil {
+		t.Errorf("distance `%v` `%v`: expected no error, got `%v`", first, second, err)
+	}
+	if result != 30*time.Minute {
+		t.Errorf("distance `%v` `%v`: expected result `%v`, got `%v`", first, second, 30*time.Minute, result)
+	}
+}
+
+func TestOverlapSize(t *testing.T) {
+	tests := []struct {
+		first       pgtype.Range[int64]
+		second      pgtype.Range[int64]
+		expected    int64
+		expectedErr bool
+	}{
+		{
+			first:    pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 7, UpperType: pgtype.Exclusive, Valid: true},
+			second:   pgtype.Range[int64]{Lower: 5, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+			expected: 2,
+		},
+		{
+			first:    pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 7, UpperType: pgtype.Inclusive, Valid: true},
+			second:   pgtype.Range[int64]{Lower: 5, LowerType: pgtype.Exclusive, UpperType: pgtype.Unbounded, Valid: true},
+			expected: 2,
+		},
+		{
+			first:    pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+			second:   pgtype.Range[int64]{Lower: 8, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+			expected: 0,
+		},
+		{
+			first:       pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, UpperType: pgtype.Unbounded, Valid: true},
+			second:      pgtype.Range[int64]{Lower: 5, LowerType: pgtype.Inclusive, UpperType: pgtype.Unbounded, Valid: true},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		result, err := iro.OverlapSize(tt.first, tt.second)
+		if err == nil && tt.expectedErr {
+			t.Errorf("overlap size `%v` `%v`: expected error, got none", tt.first, tt.second)
+		}
+		if err != nil && !tt.expectedErr {
+			t.Errorf("overlap size `%v` `%v`: expected no error, got `%v`", tt.first, tt.second, err)
+		}
+		if err != nil || tt.expectedErr {
+			continue
+		}
+		if tt.expected != result {
+			t.Errorf("overlap size `%v` `%v`: expected result `%v`, got `%v`", tt.first, tt.second, tt.expected, result)
+		}
+	}
+
+	first := pgtype.Range[time.Time]{Lower: time.Unix(0, 0), LowerType: pgtype.Inclusive, Upper: time.Unix(3600, 0), UpperType: pgtype.Exclusive, Valid: true}
+	second := pgtype.Range[time.Time]{Lower: time.Unix(2700, 0), LowerType: pgtype.Inclusive, Upper: time.Unix(7200, 0), UpperType: pgtype.Exclusive, Valid: true}
+	result, err := tro.OverlapSize(first, second)
+	if err != nil {
+		t.Errorf("overlap size `%v` `%v`: expected no error, got `%v`", first, second, err)
+	}
+	if result != 15*time.Minute {
+		t.Errorf("overlap size `%v` `%v`: expected result `%v`, got `%v`", first, second, 15*time.Minute, result)
+	}
+}
+
+func TestJaccard(t *testing.T) {
+	tests := []struct {
+		first       pgtype.Range[int64]
+		second      pgtype.Range[int64]
+		expected    float64
+		expectedErr bool
+	}{
+		{
+			first:    pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+			second:   pgtype.Range[int64]{Lower: 5, LowerType: pgtype.Inclusive, Upper: 15, UpperType: pgtype.Exclusive, Valid: true},
+			expected: 1.0 / 3.0,
+		},
+		{
+			first:    pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+			second:   pgtype.Range[int64]{Lower: -1, LowerType: pgtype.Exclusive, Upper: 9, UpperType: pgtype.Inclusive, Valid: true},
+			expected: 1.0,
+		},
+		{
+			first:    pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+			second:   pgtype.Range[int64]{Lower: 20, LowerType: pgtype.Inclusive, Upper: 25, UpperType: pgtype.Exclusive, Valid: true},
+			expected: 0.0,
+		},
+		{
+			first:    pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+			second:   pgtype.Range[int64]{LowerType: pgtype.Empty, UpperType: pgtype.Empty, Valid: true},
+			expected: 0.0,
+		},
+		{
+			first:       pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Inclusive, UpperType: pgtype.Unbounded, Valid: true},
+			second:      pgtype.Range[int64]{Lower: 5, LowerType: pgtype.Inclusive, Upper: 15, UpperType: pgtype.Exclusive, Valid: true},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		result, err := iro.Jaccard(tt.first, tt.second)
+		if err == nil && tt.expectedErr {
+			t.Errorf("jaccard `%v` `%v`: expected error, got none", tt.first, tt.second)
+		}
+		if err != nil && !tt.expectedErr {
+			t.Errorf("jaccard `%v` `%v`: expected no error, got `%v`", tt.first, tt.second, err)
+		}
+		if err != nil || tt.expectedErr {
+			continue
+		}
+		if tt.expected != result {
+			t.Errorf("jaccard `%v` `%v`: expected result `%v`, got `%v`", tt.first, tt.second, tt.expected, result)
+		}
+	}
+
+	first := pgtype.Range[time.Time]{Lower: time.Unix(0, 0), LowerType: pgtype.Inclusive, Upper: time.Unix(3600, 0), UpperType: pgtype.Exclusive, Valid: true}
+	second := pgtype.Range[time.Time]{Lower: time.Unix(1800, 0), LowerType: pgtype.Inclusive, Upper: time.Unix(3600, 0), UpperType: pgtype.Exclusive, Valid: true}
+	if result, err := tro.Jaccard(first, second); err != nil || result != 0.5 {
+		t.Errorf("jaccard `%v` `%v`: expected result `%v`, got `%v` (error `%v`)", first, second, 0.5, result, err)
+	}
+}
+
+func TestTimeWithStep(t *testing.T) {
+	sro := NewTimeWithStep(time.Second)
+	start := time.Unix(0, 0)
+
+	first := pgtype.Range[time.Time]{Lower: start, LowerType: pgtype.Inclusive, Upper: start.Add(time.Second), UpperType: pgtype.Exclusive, Valid: true}
+	second := pgtype.Range[time.Time]{Lower: start.Add(time.Second), LowerType: pgtype.Inclusive, Upper: start.Add(2 * time.Second), UpperType: pgtype.Exclusive, Valid: true}
+	if result, err := sro.Adjacent(first, second); err != nil || !result {
+		t.Errorf("`%v` adjacent `%v`: expected result `true`, got `%v` (error `%v`)", first, second, result, err)
+	}
+
+	// with a step of one second [t, t+1s] and [t+2s, t+3s) have no gap between them
+	first.UpperType = pgtype.Inclusive
+	second = pgtype.Range[time.Time]{Lower: start.Add(2 * time.Second), LowerType: pgtype.Inclusive, Upper: start.Add(3 * time.Second), UpperType: pgtype.Exclusive, Valid: true}
+	if result, err := sro.Adjacent(first, second); err != nil || !result {
+		t.Errorf("`%v` adjacent `%v`: expected result `true`, got `%v` (error `%v`)", first, second, result, err)
+	}
+	if result, err := tro.Adjacent(first, second); err != nil || result {
+		t.Errorf("`%v` adjacent `%v`: expected result `false`, got `%v` (error `%v`)", first, second, result, err)
+	}
+
+	r := pgtype.Range[time.Time]{Lower: start, LowerType: pgtype.Inclusive, Upper: start.Add(time.Minute), UpperType: pgtype.Exclusive, Valid: true}
+	if result, err := sro.Size(r); err != nil || result != 60 {
+		t.Errorf("size `%v`: expected result `60`, got `%v` (error `%v`)", r, result, err)
+	}
+	shifted, err := sro.Shift(r, 30)
+	if err != nil || !shifted.Lower.Equal(start.Add(30*time.Second)) {
+		t.Errorf("shift `%v`: expected lower `%v`, got `%v` (error `%v`)", r, start.Add(30*time.Second), shifted.Lower, err)
+	}
+
+	// a number of steps that overflows a time.Duration saturates instead of wrapping around
+	for _, tt := range []struct {
+		delta    time.Duration
+		expected time.Time
+	}{
+		{delta: math.MaxInt64 / 2, expected: start.Add(math.MaxInt64)},
+		{delta: math.MinInt64 / 2, expected: start.Add(math.MinInt64)},
+	} {
+		if shifted, err := sro.Shift(r, tt.delta); err != nil || !shifted.Lower.Equal(tt.expected) {
+			t.Errorf("shift `%v` by `%v` steps: expected lower `%v`, got `%v` (error `%v`)", r, int64(tt.delta), tt.expected, shifted.Lower, err)
+		}
+	}
+
+	// a step that is not positive has no effect
+	for _, step := range []time.Duration{0, -time.Second} {
+		if result, err := NewTimeWithStep(step).Size(r); err != nil || result != time.Minute {
+			t.Errorf("size `%v` with step `%v`: expected result `%v`, got `%v` (error `%v`)", r, step, time.Minute, result, err)
+		}
+	}
+}
+
+func TestContainsAllRanges(t *testing.T) {
+	outer := pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Inclusive, Upper: 100, UpperType: pgtype.Exclusive, Valid: true}
+	tests := []struct {
+		inners      []pgtype.Range[int64]
+		expected    bool
+		expectedErr bool
+	}{
+		{
+			inners: []pgtype.Range[int64]{
+				{Lower: 0, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+				{Lower: 20, LowerType: pgtype.Exclusive, Upper: 99, UpperType: pgtype.Inclusive, Valid: true},
+				{LowerType: pgtype.Empty, UpperType: pgtype.Empty, Valid: true},
+			},
+			expected: true,
+		},
+		{
+			inners:   nil,
+			expected: true,
+		},
+		{
+			inners: []pgtype.Range[int64]{
+				{Lower: 0, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+				{Lower: 90, LowerType: pgtype.Inclusive, Upper: 100, UpperType: pgtype.Inclusive, Valid: true},
+			},
+			expected: false,
+		},
+		{
+			inners: []pgtype.Range[int64]{
+				{Lower: 200, LowerType: pgtype.Inclusive, Upper: 300, UpperType: pgtype.Inclusive, Valid: true},
+				{Lower: 0, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: false},
+			},
+			expected: false,
+		},
+		{
+			inners: []pgtype.Range[int64]{
+				{Lower: 0, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: false},
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		result, err := iro.ContainsAllRanges(outer, tt.inners)
+		if err == nil && tt.expectedErr {
+			t.Errorf("`%v` contains all `%v`: expected error, got none", outer, tt.inners)
+		}
+		if err != nil && !tt.expectedErr {
+			t.Errorf("`%v` contains all `%v`: expected no error, got `%v`", outer, tt.inners, err)
+		}
+		if err != nil || tt.expectedErr {
+			continue
+		}
+		if tt.expected != result {
+			t.Errorf("`%v` contains all `%v`: expected result `%v`, got `%v`", outer, tt.inners, tt.expected, result)
+		}
+	}
+}
+
+func TestCoalesce(t *testing.T) {
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(hour int) time.Time { return day.Add(time.Duration(hour) * time.Hour) }
+	slot := func(from, to int) pgtype.Range[time.Time] {
+		return pgtype.Range[time.Time]{Lower: at(from), LowerType: pgtype.Inclusive, Upper: at(to), UpperType: pgtype.Exclusive, Valid: true}
+	}
+
+	covered, gaps, err := tro.Coalesce(slot(0, 24), []pgtype.Range[time.Time]{slot(13, 14), slot(9, 10), slot(23, 25), slot(9, 11), slot(14, 15)})
+	if err != nil {
+		t.Fatalf("coalesce: expected no error, got `%v`", err)
+	}
+	for _, tt := range []struct {
+		name     string
+		result   []pgtype.Range[time.Time]
+		expected []pgtype.Range[time.Time]
+	}{
+		{name: "covered", result: covered, expected: []pgtype.Range[time.Time]{slot(9, 11), slot(13, 15), slot(23, 24)}},
+		{name: "gaps", result: gaps, expected: []pgtype.Range[time.Time]{slot(0, 9), slot(11, 13), slot(15, 23)}},
+	} {
+		equal := len(tt.result) == len(tt.expected)
+		for i := 0; equal && i < len(tt.result); i++ {
+			equal, _ = tro.Equal(tt.result[i], tt.expected[i])
+		}
+		if !equal {
+			t.Errorf("coalesce %s: expected result `%v`, got `%v`", tt.name, tt.expected, tt.result)
+		}
+	}
+
+	tests := []struct {
+		bound           pgtype.Range[int64]
+		rs              []pgtype.Range[int64]
+		expectedCovered []pgtype.Range[int64]
+		expectedGaps    []pgtype.Range[int64]
+		expectedErr     bool
+	}{
+		{
+			bound: pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Inclusive, Valid: true},
+			rs: []pgtype.Range[int64]{
+				{Lower: 2, LowerType: pgtype.Inclusive, Upper: 3, UpperType: pgtype.Inclusive, Valid: true},
+				{Lower: 3, LowerType: pgtype.Exclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+			},
+			expectedCovered: []pgtype.Range[int64]{{Lower: 2, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true}},
+			expectedGaps: []pgtype.Range[int64]{
+				{Lower: 0, LowerType: pgtype.Inclusive, Upper: 2, UpperType: pgtype.Exclusive, Valid: true},
+				{Lower: 5, LowerType: pgtype.Inclusive, Upper: 11, UpperType: pgtype.Exclusive, Valid: true},
+			},
+		},
+		{
+			bound:        pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+			rs:           nil,
+			expectedGaps: []pgtype.Range[int64]{{Lower: 0, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true}},
+		},
+		{
+			bound:           pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Inclusive, UpperType: pgtype.Unbounded, Valid: true},
+			rs:              []pgtype.Range[int64]{{Lower: 5, LowerType: pgtype.Inclusive, UpperType: pgtype.Unbounded, Valid: true}},
+			expectedCovered: []pgtype.Range[int64]{{Lower: 5, LowerType: pgtype.Inclusive, UpperType: pgtype.Unbounded, Valid: true}},
+			expectedGaps:    []pgtype.Range[int64]{{Lower: 0, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true}},
+		},
+		{
+			bound:       pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Inclusive, UpperType: pgtype.Unbounded, Valid: true},
+			rs:          []pgtype.Range[int64]{{Lower: 5, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true}},
+			expectedErr: true,
+		},
+		{
+			bound:       pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+			rs:          []pgtype.Range[int64]{{Lower: 5, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: false}},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		covered, gaps, err := iro.Coalesce(tt.bound, tt.rs)
+		if err == nil && tt.expectedErr {
+			t.Errorf("coalesce `%v` in `%v`: expected error, got none", tt.rs, tt.bound)
+		}
+		if err != nil && !tt.expectedErr {
+			t.Errorf("coalesce `%v` in `%v`: expected no error, got `%v`", tt.rs, tt.bound, err)
+		}
+		if err != nil || tt.expectedErr {
+			continue
+		}
+		if !slices.Equal(covered, tt.expectedCovered) {
+			t.Errorf("coalesce `%v` in `%v`: expected covered `%v`, got `%v`", tt.rs, tt.bound, tt.expectedCovered, covered)
+		}
+		if !slices.Equal(gaps, tt.expectedGaps) {
+			t.Errorf("coalesce `%v` in `%v`: expected gaps `%v`, got `%v`", tt.rs, tt.bound, tt.expectedGaps, gaps)
+		}
+	}
+}
+
+func TestComplement(t *testing.T) {
+	universe := pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Inclusive, Upper: 100, UpperType: pgtype.Exclusive, Valid: true}
+	tests := []struct {
+		universe    pgtype.Range[int64]
+		r           pgtype.Range[int64]
+		expected    []pgtype.Range[int64]
+		expectedErr bool
+	}{
+		{
+			universe: universe,
+			r:        pgtype.Range[int64]{Lower: 20, LowerType: pgtype.Inclusive, Upper: 30, UpperType: pgtype.Inclusive, Valid: true},
+			expected: []pgtype.Range[int64]{
+				{Lower: 0, LowerType: pgtype.Inclusive, Upper: 20, UpperType: pgtype.Exclusive, Valid: true},
+				{Lower: 31, LowerType: pgtype.Inclusive, Upper: 100, UpperType: pgtype.Exclusive, Valid: true},
+			},
+		},
+		{
+			universe: universe,
+			r:        pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Inclusive, Upper: 30, UpperType: pgtype.Exclusive, Valid: true},
+			expected: []pgtype.Range[int64]{{Lower: 30, LowerType: pgtype.Inclusive, Upper: 100, UpperType: pgtype.Exclusive, Valid: true}},
+		},
+		{
+			universe: universe,
+			r:        pgtype.Range[int64]{Lower: 90, LowerType: pgtype.Inclusive, Upper: 200, UpperType: pgtype.Exclusive, Valid: true},
+			expected: []pgtype.Range[int64]{{Lower: 0, LowerType: pgtype.Inclusive, Upper: 90, UpperType: pgtype.Exclusive, Valid: true}},
+		},
+		{
+			universe: universe,
+			r:        pgtype.Range[int64]{Lower: -10, LowerType: pgtype.Inclusive, Upper: 100, UpperType: pgtype.Exclusive, Valid: true},
+			expected: nil,
+		},
+		{
+			universe: universe,
+			r:        pgtype.Range[int64]{Lower: 200, LowerType: pgtype.Inclusive, Upper: 300, UpperType: pgtype.Exclusive, Valid: true},
+			expected: []pgtype.Range[int64]{universe},
+		},
+		{
+			universe: pgtype.Range[int64]{LowerType: pgtype.Unbounded, UpperType: pgtype.Unbounded, Valid: true},
+			r:        pgtype.Range[int64]{LowerType: pgtype.Unbounded, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+			expected: []pgtype.Range[int64]{{Lower: 10, LowerType: pgtype.Inclusive, UpperType: pgtype.Unbounded, Valid: true}},
+		},
+		{
+			universe:    universe,
+			r:           pgtype.Range[int64]{Lower: 50, LowerType: pgtype.Inclusive, UpperType: pgtype.Unbounded, Valid: true},
+			expectedErr: true,
+		},
+		{
+			universe:    universe,
+			r:           pgtype.Range[int64]{Lower: 20, LowerType: pgtype.Inclusive, Upper: 30, UpperType: pgtype.Exclusive, Valid: false},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		result, err := iro.Complement(tt.universe, tt.r)
+		if err == nil && tt.expectedErr {
+			t.Errorf("complement `%v` in `%v`: expected error, got none", tt.r, tt.universe)
+		}
+		if err != nil && !tt.expectedErr {
+			t.Errorf("complement `%v` in `%v`: expected no error, got `%v`", tt.r, tt.universe, err)
+		}
+		if err != nil || tt.expectedErr {
+			continue
+		}
+		if !slices.Equal(result, tt.expected) {
+			t.Errorf("complement `%v` in `%v`: expected result `%v`, got `%v`", tt.r, tt.universe, tt.expected, result)
+		}
+	}
+}
+
+func TestHash(t *testing.T) {
+	tests := []struct {
+		first    pgtype.Range[int64]
+		second   pgtype.Range[int64]
+		expected bool
+	}{
+		{
+			first:    pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Exclusive, Upper: 5, UpperType: pgtype.Inclusive, Valid: true},
+			second:   pgtype.Range[int64]{Lower: 2, LowerType: pgtype.Inclusive, Upper: 6, UpperType: pgtype.Exclusive, Valid: true},
+			expected: true,
+		},
+		{
+			first:    pgtype.Range[int64]{Lower: 2, LowerType: pgtype.Inclusive, Upper: 7, UpperType: pgtype.Exclusive, Valid: true},
+			second:   pgtype.Range[int64]{Lower: 2, LowerType: pgtype.Inclusive, Upper: 6, UpperType: pgtype.Exclusive, Valid: true},
+			expected: false,
+		},
+		{
+			first:    pgtype.Range[int64]{Lower: 3, LowerType: pgtype.Inclusive, Upper: 3, UpperType: pgtype.Exclusive, Valid: true},
+			second:   pgtype.Range[int64]{LowerType: pgtype.Empty, UpperType: pgtype.Empty, Valid: true},
+			expected: true,
+		},
+		{
+			first:    pgtype.Range[int64]{Lower: 3, LowerType: pgtype.Unbounded, Upper: 6, UpperType: pgtype.Exclusive, Valid: true},
+			second:   pgtype.Range[int64]{Lower: 4, LowerType: pgtype.Unbounded, Upper: 5, UpperType: pgtype.Inclusive, Valid: true},
+			expected: true,
+		},
+		{
+			first:    pgtype.Range[int64]{LowerType: pgtype.Unbounded, UpperType: pgtype.Unbounded, Valid: true},
+			second:   pgtype.Range[int64]{LowerType: pgtype.Empty, UpperType: pgtype.Empty, Valid: true},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		first, err := iro.Hash(tt.first)
+		if err != nil {
+			t.Errorf("hash `%v`: expected no error, got `%v`", tt.first, err)
+			continue
+		}
+		second, err := iro.Hash(tt.second)
+		if err != nil {
+			t.Errorf("hash `%v`: expected no error, got `%v`", tt.second, err)
+			continue
+		}
+		if (first == second) != tt.expected {
+			t.Errorf("hash `%v` and `%v`: expected equal hashes `%v`, got `%v` and `%v`", tt.first, tt.second, tt.expected, first, second)
+		}
+	}
+
+	if _, err := iro.Hash(pgtype.Range[int64]{Valid: false}); !errors.Is(err, ErrInvalidRange) {
+		t.Errorf("hash invalid range: expected error `%v`, got `%v`", ErrInvalidRange, err)
+	}
+	nan := pgtype.Range[float64]{Lower: math.NaN(), LowerType: pgtype.Inclusive, Upper: 1, UpperType: pgtype.Exclusive, Valid: true}
+	if _, err := NewFloat64().Hash(nan); !errors.Is(err, ErrNaN) {
+		t.Errorf("hash `%v`: expected error `%v`, got `%v`", nan, ErrNaN, err)
+	}
+
+	first := pgtype.Range[time.Time]{Lower: time.Unix(0, 0).UTC(), LowerType: pgtype.Inclusive, Upper: time.Unix(60, 0).UTC(), UpperType: pgtype.Exclusive, Valid: true}
+	second := first
+	second.Upper = time.Unix(60, 0).UTC()
+	if firstHash, err := tro.Hash(first); err != nil {
+		t.Errorf("hash `%v`: expected no error, got `%v`", first, err)
+	} else if secondHash, _ := tro.Hash(second); firstHash != secondHash {
+		t.Errorf("hash `%v` and `%v`: expected equal hashes, got `%v` and `%v`", first, second, firstHash, secondHash)
+	}
+
+	zone := time.FixedZone("UTC+1", 3600)
+	second = pgtype.Range[time.Time]{Lower: first.Lower.In(zone), LowerType: pgtype.Inclusive, Upper: first.Upper.In(zone), UpperType: pgtype.Exclusive, Valid: true}
+	if firstHash, err := tro.Hash(first); err != nil {
+		t.Errorf("hash `%v`: expected no error, got `%v`", first, err)
+	} else if secondHash, _ := tro.Hash(second); firstHash != secondHash {
+		t.Errorf("hash `%v` and `%v`: expected equal hashes, got `%v` and `%v`", first, second, firstHash, secondHash)
+	}
+
+	dro := NewDate()
+	firstDate := pgtype.Range[time.Time]{Lower: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), LowerType: pgtype.Inclusive, Upper: time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC), UpperType: pgtype.Exclusive, Valid: true}
+	secondDate := pgtype.Range[time.Time]{Lower: time.Date(2024, 1, 1, 13, 30, 0, 0, time.UTC), LowerType: pgtype.Inclusive, Upper: time.Date(2024, 1, 5, 8, 0, 0, 0, time.UTC), UpperType: pgtype.Exclusive, Valid: true}
+	if firstHash, err := dro.Hash(firstDate); err != nil {
+		t.Errorf("hash `%v`: expected no error, got `%v`", firstDate, err)
+	} else if secondHash, _ := dro.Hash(secondDate); firstHash != secondHash {
+		t.Errorf("hash `%v` and `%v`: expected equal hashes, got `%v` and `%v`", firstDate, secondDate, firstHash, secondHash)
+	}
+
+	fro := NewFloat64()
+	firstFloat := pgtype.Range[float64]{Lower: 0, LowerType: pgtype.Inclusive, Upper: 1, UpperType: pgtype.Exclusive, Valid: true}
+	secondFloat := pgtype.Range[float64]{Lower: math.Copysign(0, -1), LowerType: pgtype.Inclusive, Upper: 1, UpperType: pgtype.Exclusive, Valid: true}
+	if firstHash, err := fro.Hash(firstFloat); err != nil {
+		t.Errorf("hash `%v`: expected no error, got `%v`", firstFloat, err)
+	} else if secondHash, _ := fro.Hash(secondFloat); firstHash != secondHash {
+		t.Errorf("hash `%v` and `%v`: expected equal hashes, got `%v` and `%v`", firstFloat, secondFloat, firstHash, secondHash)
+	}
+}
+
+func TestOffset(t *testing.T) {
+	tests := []struct {
+		r           pgtype.Range[int64]
+		v           int64
+		expected    int64
+		expectedErr error
+	}{
+		{r: pgtype.Range[int64]{Lower: 10, LowerType: pgtype.Inclusive, Upper: 20, UpperType: pgtype.Exclusive, Valid: true}, v: 10, expected: 0},
+		{r: pgtype.Range[int64]{Lower: 10, LowerType: pgtype.Inclusive, Upper: 20, UpperType: pgtype.Exclusive, Valid: true}, v: 19, expected: 9},
+		{r: pgtype.Range[int64]{Lower: 10, LowerType: pgtype.Exclusive, Upper: 20, UpperType: pgtype.Inclusive, Valid: true}, v: 20, expected: 9},
+		{r: pgtype.Range[int64]{Lower: 10, LowerType: pgtype.Inclusive, Upper: 20, UpperType: pgtype.Exclusive, Valid: true}, v: 20, expectedErr: ErrOutOfRange},
+		{r: pgtype.Range[int64]{Lower: 10, LowerType: pgtype.Exclusive, Upper: 20, UpperType: pgtype.Exclusive, Valid: true}, v: 10, expectedErr: ErrOutOfRange},
+		{r: pgtype.Range[int64]{LowerType: pgtype.Unbounded, Upper: 20, UpperType: pgtype.Exclusive, Valid: true}, v: 10, expectedErr: ErrUnboundedRange},
+		{r: pgtype.Range[int64]{LowerType: pgtype.Empty, UpperType: pgtype.Empty, Valid: true}, v: 10, expectedErr: ErrOutOfRange},
+		{r: pgtype.Range[int64]{Lower: 10, LowerType: pgtype.Inclusive, Upper: 20, UpperType: pgtype.Exclusive, Valid: false}, v: 10, expectedErr: ErrInvalidRange},
+	}
+
+	for _, tt := range tests {
+		result, err := iro.Offset(tt.r, tt.v)
+		if tt.expectedErr != nil {
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("offset of `%v` in `%v`: expected error `%v`, got `%v`", tt.v, tt.r, tt.expectedErr, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("offset of `%v` in `%v`: expected no error, got `%v`", tt.v, tt.r, err)
+			continue
+		}
+		if tt.expected != result {
+			t.Errorf("offset of `%v` in `%v`: expected result `%v`, got `%v`", tt.v, tt.r, tt.expected, result)
+		}
+	}
+
+	r := pgtype.Range[time.Time]{Lower: time.Unix(0, 0), LowerType: pgtype.Inclusive, Upper: time.Unix(3600, 0), UpperType: pgtype.Exclusive, Valid: true}
+	if result, err := tro.Offset(r, time.Unix(900, 0)); err != nil || result != 15*time.Minute {
+		t.Errorf("offset of `%v` in `%v`: expected result `%v`, got `%v` (error `%v`)", time.Unix(900, 0), r, 15*time.Minute, result, err)
+	}
+}
+
+func TestAnyOverlap(t *testing.T) {
+	slot := func(lower, upper int64) pgtype.Range[int64] {
+		return pgtype.Range[int64]{Lower: lower, LowerType: pgtype.Inclusive, Upper: upper, UpperType: pgtype.Exclusive, Valid: true}
+	}
+	tests := []struct {
+		a           []pgtype.Range[int64]
+		b           []pgtype.Range[int64]
+		expected    bool
+		expectedErr bool
+	}{
+		{
+			a:        []pgtype.Range[int64]{slot(20, 30), slot(0, 10), slot(50, 60)},
+			b:        []pgtype.Range[int64]{slot(10, 20), slot(30, 40), slot(55, 56)},
+			expected: true,
+		},
+		{
+			a:        []pgtype.Range[int64]{slot(20, 30), slot(0, 10), slot(50, 60)},
+			b:        []pgtype.Range[int64]{slot(10, 20), slot(30, 40), slot(60, 70)},
+			expected: false,
+		},
+		{
+			a:        []pgtype.Range[int64]{slot(0, 100)},
+			b:        []pgtype.Range[int64]{slot(-10, -5), slot(99, 150)},
+			expected: true,
+		},
+		{
+			a:        []pgtype.Range[int64]{slot(0, 10), {LowerType: pgtype.Unbounded, Upper: -20, UpperType: pgtype.Inclusive, Valid: true}},
+			b:        []pgtype.Range[int64]{slot(-20, -19)},
+			expected: true,
+		},
+		{
+			a:        []pgtype.Range[int64]{slot(0, 10)},
+			b:        []pgtype.Range[int64]{{LowerType: pgtype.Empty, UpperType: pgtype.Empty, Valid: true}},
+			expected: false,
+		},
+		{
+			a:        nil,
+			b:        []pgtype.Range[int64]{slot(0, 10)},
+			expected: false,
+		},
+		{
+			a:           []pgtype.Range[int64]{slot(0, 10)},
+			b:           []pgtype.Range[int64]{{Lower: 0, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: false}},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		result, err := iro.AnyOverlap(tt.a, tt.b)
+		if err == nil && tt.expectedErr {
+			t.Errorf("any overlap `%v` `%v`: expected error, got none", tt.a, tt.b)
+		}
+		if err != nil && !tt.expectedErr {
+			t.Errorf("any overlap `%v` `%v`: expected no error, got `%v`", tt.a, tt.b, err)
+		}
+		if err != nil || tt.expectedErr {
+			continue
+		}
+		if tt.expected != result {
+			t.Errorf("any overlap `%v` `%v`: expected result `%v`, got `%v`", tt.a, tt.b, tt.expected, result)
+		}
+	}
+}
+
+func TestMergeOverlapping(t *testing.T) {
+	tests := []struct {
+		rs          []pgtype.Range[int64]
+		expected    []pgtype.Range[int64]
+		expectedErr bool
+	}{
+		{
+			rs: []pgtype.Range[int64]{
+				{Lower: 20, LowerType: pgtype.Inclusive, Upper: 30, UpperType: pgtype.Exclusive, Valid: true},
+				{Lower: 0, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Inclusive, Valid: true},
+				{Lower: 10, LowerType: pgtype.Exclusive, Upper: 15, UpperType: pgtype.Exclusive, Valid: true},
+				{Lower: 25, LowerType: pgtype.Inclusive, Upper: 27, UpperType: pgtype.Exclusive, Valid: true},
+				{LowerType: pgtype.Empty, UpperType: pgtype.Empty, Valid: true},
+				{Lower: 40, LowerType: pgtype.Inclusive, UpperType: pgtype.Unbounded, Valid: true},
+				{Lower: 50, LowerType: pgtype.Inclusive, Upper: 60, UpperType: pgtype.Exclusive, Valid: true},
+			},
+			expected: []pgtype.Range[int64]{
+				{Lower: 0, LowerType: pgtype.Inclusive, Upper: 15, UpperType: pgtype.Exclusive, Valid: true},
+				{Lower: 20, LowerType: pgtype.Inclusive, Upper: 30, UpperType: pgtype.Exclusive, Valid: true},
+				{Lower: 40, LowerType: pgtype.Inclusive, UpperType: pgtype.Unbounded, Valid: true},
+			},
+		},
+		{
+			rs:       []pgtype.Range[int64]{{LowerType: pgtype.Empty, UpperType: pgtype.Empty, Valid: true}},
+			expected: []pgtype.Range[int64]{},
+		},
+		{
+			rs:          []pgtype.Range[int64]{{Lower: 0, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: false}},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		result, err := iro.MergeOverlapping(tt.rs)
+		if err == nil && tt.expectedErr {
+			t.Errorf("merge overlapping `%v`: expected error, got none", tt.rs)
+		}
+		if err != nil && !tt.expectedErr {
+			t.Errorf("merge overlapping `%v`: expected no error, got `%v`", tt.rs, err)
+		}
+		if err != nil || tt.expectedErr {
+			continue
+		}
+		if !slices.Equal(result, tt.expected) {
+			t.Errorf("merge overlapping `%v`: expected result `%v`, got `%v`", tt.rs, tt.expected, result)
+		}
+	}
+
+	// continuous ranges are only connected if the shared bound is included by one of them
+	minute := func(m int64) time.Time { return time.Unix(m*60, 0) }
+	rs := []pgtype.Range[time.Time]{
+		{Lower: minute(0), LowerType: pgtype.Inclusive, Upper: minute(1), UpperType: pgtype.Exclusive, Valid: true},
+		{Lower: minute(1), LowerType: pgtype.Exclusive, Upper: minute(2), UpperType: pgtype.Inclusive, Valid: true},
+		{Lower: minute(2), LowerType: pgtype.Exclusive, Upper: minute(3), UpperType: pgtype.Exclusive, Valid: true},
+	}
+	expected := []pgtype.Range[time.Time]{
+		rs[0],
+		{Lower: minute(1), LowerType: pgtype.Exclusive, Upper: minute(3), UpperType: pgtype.Exclusive, Valid: true},
+	}
+	if result, err := tro.MergeOverlapping(rs); err != nil || !slices.Equal(result, expected) {
+		t.Errorf("merge overlapping `%v`: expected result `%v`, got `%v` (error `%v`)", rs, expected, result, err)
+	}
+
+	nan := []pgtype.Range[float64]{
+		{Lower: 0, LowerType: pgtype.Inclusive, Upper: 1, UpperType: pgtype.Exclusive, Valid: true},
+		{Lower: math.NaN(), LowerType: pgtype.Inclusive, Upper: 2, UpperType: pgtype.Exclusive, Valid: true},
+	}
+	if _, err := NewFloat64().MergeOverlapping(nan); !errors.Is(err, ErrNaN) {
+		t.Errorf("merge overlapping `%v`: expected error `%v`, got `%v`", nan, ErrNaN, err)
+	}
+}
+
+func BenchmarkMergeOverlapping(b *testing.B) {
+	random := rand.New(rand.NewPCG(1, 2))
+	rs := make([]pgtype.Range[int64], 100_000)
+	for i := range rs {
+		lower := random.Int64N(10_000_000)
+		rs[i] = pgtype.Range[int64]{Lower: lower, Upper: lower + random.Int64N(100), Valid: true}
+		// only bounded ranges, otherwise everything would be merged into a few ranges
+		rs[i].SetBoundTypes(createBoundType(random.Int64N(2)), createBoundType(random.Int64N(2)))
+	}
+
+	b.ResetTimer()
+	for range b.N {
+		if _, err := iro.MergeOverlapping(rs); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCompareBounds(b *testing.B) {
+	first := pgtype.Range[time.Time]{Lower: time.Unix(0, 0), LowerType: pgtype.Inclusive, Upper: time.Unix(60, 0), UpperType: pgtype.Exclusive, Valid: true}
+	second := pgtype.Range[time.Time]{Lower: time.Unix(60, 0), LowerType: pgtype.Inclusive, Upper: time.Unix(120, 0), UpperType: pgtype.Inclusive, Valid: true}
+
+	b.Run("ranges", func(b *testing.B) {
+		for range b.N {
+			tro.compareBounds(first, second, false, true)
+		}
+	})
+	b.Run("values", func(b *testing.B) {
+		for range b.N {
+			tro.compareBoundValues(first.Upper, first.UpperType, false, second.Lower, second.LowerType, true)
+		}
+	})
+}
+
+func TestContainParity(t *testing.T) {
+	boundTypes := []pgtype.BoundType{pgtype.Inclusive, pgtype.Exclusive, pgtype.Unbounded}
+	rs := []pgtype.Range[int64]{{LowerType: pgtype.Empty, UpperType: pgtype.Empty, Valid: true}}
+	for lower := int64(0); lower < 4; lower++ {
+		for upper := lower; upper < 4; upper++ {
+			for _, lowerType := range boundTypes {
+				for _, upperType := range boundTypes {
+					rs = append(rs, pgtype.Range[int64]{Lower: lower, LowerType: lowerType, Upper: upper, UpperType: upperType, Valid: true})
+				}
+			}
+		}
+	}
+
+	// the result matches the intersection of the ranges being equal to the second range
+	for _, a := range rs {
+		for _, b := range rs {
+			intersect, err := iro.Intersect(a, b)
+			if err != nil {
+				t.Fatalf("`%v` * `%v`: expected no error, got `%v`", a, b, err)
+			}
+			expected, _ := iro.Equal(intersect, b)
+			if result, err := iro.Contain(a, b); err != nil || result != expected {
+				t.Errorf("`%v` @> `%v`: expected result `%v`, got `%v` (error `%v`)", a, b, expected, result, err)
+			}
+		}
+	}
+
+	if _, err := iro.Contain(rs[1], pgtype.Range[int64]{}); !errors.Is(err, ErrInvalidRange) {
+		t.Errorf("contain invalid: expected error `%v`, got `%v`", ErrInvalidRange, err)
+	}
+	fro := NewFloat32()
+	nan := pgtype.Range[float32]{Lower: float32(math.NaN()), LowerType: pgtype.Inclusive, Upper: 1, UpperType: pgtype.Exclusive, Valid: true}
+	if _, err := fro.Contain(pgtype.Range[float32]{LowerType: pgtype.Unbounded, UpperType: pgtype.Unbounded, Valid: true}, nan); !errors.Is(err, ErrNaN) {
+		t.Errorf("contain NaN: expected error `%v`, got `%v`", ErrNaN, err)
+	}
+}
+
+func BenchmarkContain(b *testing.B) {
+	r := pgtype.Range[time.Time]{Lower: time.Unix(0, 0), LowerType: pgtype.Inclusive, Upper: time.Unix(60, 0), UpperType: pgtype.Exclusive, Valid: true}
+	other := pgtype.Range[time.Time]{Lower: time.Unix(30, 0), LowerType: pgtype.Inclusive, Upper: time.Unix(45, 0), UpperType: pgtype.Exclusive, Valid: true}
+
+	b.Run("bounds", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			if _, err := tro.Contain(r, other); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("intersect equal", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			intersect, err := tro.Intersect(r, other)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if _, err := tro.Equal(intersect, other); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestIntersectUnionEmptyAndFull(t *testing.T) {
+	empty := pgtype.Range[int64]{LowerType: pgtype.Empty, UpperType: pgtype.Empty, Valid: true}
+	full := pgtype.Range[int64]{LowerType: pgtype.Unbounded, UpperType: pgtype.Unbounded, Valid: true}
+	ranges := []pgtype.Range[int64]{
+		empty,
+		full,
+		{Lower: 3, LowerType: pgtype.Inclusive, Upper: 3, UpperType: pgtype.Exclusive, Valid: true},
+		{Lower: 1, LowerType: pgtype.Exclusive, Upper: 5, UpperType: pgtype.Inclusive, Valid: true},
+		{Lower: 1, LowerType: pgtype.Inclusive, UpperType: pgtype.Unbounded, Valid: true},
+		{LowerType: pgtype.Unbounded, Upper: 1, UpperType: pgtype.Inclusive, Valid: true},
+	}
+
+	for _, r := range ranges {
+		e, _ := iro.Empty(r)
+		rewritten := iro.Rewrite(r)
+		if e {
+			rewritten = empty
+		}
+		for _, tt := range []struct {
+			name     string
+			fn       func(pgtype.Range[int64], pgtype.Range[int64]) (pgtype.Range[int64], error)
+			other    pgtype.Range[int64]
+			expected pgtype.Range[int64]
+		}{
+			{name: "intersect empty", fn: iro.Intersect, other: empty, expected: empty},
+			{name: "intersect full", fn: iro.Intersect, other: full, expected: rewritten},
+			{name: "union empty", fn: iro.Union, other: empty, expected: rewritten},
+			{name: "union full", fn: iro.Union, other: full, expected: full},
+		} {
+			for _, operands := range [][2]pgtype.Range[int64]{{r, tt.other}, {tt.other, r}} {
+				result, err := tt.fn(operands[0], operands[1])
+				if err != nil {
+					t.Errorf("%s `%v`: expected no error, got `%v`", tt.name, r, err)
+					continue
+				}
+				if !reflect.DeepEqual(tt.expected, result) {
+					t.Errorf("%s `%v`: expected result `%v`, got `%v`", tt.name, r, tt.expected, result)
+				}
+			}
+		}
+	}
+
+	// the operands are still checked
+	if _, err := iro.Intersect(full, pgtype.Range[int64]{}); !errors.Is(err, ErrInvalidRange) {
+		t.Errorf("intersect invalid: expected error `%v`, got `%v`", ErrInvalidRange, err)
+	}
+	if _, err := iro.Union(pgtype.Range[int64]{}, empty); !errors.Is(err, ErrInvalidRange) {
+		t.Errorf("union invalid: expected error `%v`, got `%v`", ErrInvalidRange, err)
+	}
+	nan := float32(math.NaN())
+	fro := NewFloat32()
+	fullFloat := pgtype.Range[float32]{LowerType: pgtype.Unbounded, UpperType: pgtype.Unbounded, Valid: true}
+	if _, err := fro.Intersect(fullFloat, pgtype.Range[float32]{Lower: nan, LowerType: pgtype.Inclusive, Upper: 1, UpperType: pgtype.Exclusive, Valid: true}); !errors.Is(err, ErrNaN) {
+		t.Errorf("intersect NaN: expected error `%v`, got `%v`", ErrNaN, err)
+	}
+}
+
+func BenchmarkIntersectUnion(b *testing.B) {
+	empty := pgtype.Range[time.Time]{LowerType: pgtype.Empty, UpperType: pgtype.Empty, Valid: true}
+	full := pgtype.Range[time.Time]{LowerType: pgtype.Unbounded, UpperType: pgtype.Unbounded, Valid: true}
+	r := pgtype.Range[time.Time]{Lower: time.Unix(0, 0), LowerType: pgtype.Inclusive, Upper: time.Unix(60, 0), UpperType: pgtype.Exclusive, Valid: true}
+	other := pgtype.Range[time.Time]{Lower: time.Unix(30, 0), LowerType: pgtype.Inclusive, Upper: time.Unix(90, 0), UpperType: pgtype.Exclusive, Valid: true}
+
+	for _, bb := range []struct {
+		name  string
+		other pgtype.Range[time.Time]
+	}{
+		{name: "empty", other: empty},
+		{name: "full", other: full},
+		{name: "bounded", other: other},
+	} {
+		b.Run("intersect "+bb.name, func(b *testing.B) {
+			for range b.N {
+				if _, err := tro.Intersect(r, bb.other); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run("union "+bb.name, func(b *testing.B) {
+			for range b.N {
+				if _, err := tro.Union(r, bb.other); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestFloat64(t *testing.T) {
+	fro := NewFloat64()
+	tests := []struct {
+		r             pgtype.Range[float64]
+		expectedSize  float64
+		expectedEmpty bool
+	}{
+		{
+			r:            pgtype.Range[float64]{Lower: 0, LowerType: pgtype.Inclusive, Upper: 1.5, UpperType: pgtype.Exclusive, Valid: true},
+			expectedSize: 1.5,
+		},
+		{
+			r:            pgtype.Range[float64]{Lower: -0.25, LowerType: pgtype.Exclusive, Upper: 0.5, UpperType: pgtype.Inclusive, Valid: true},
+			expectedSize: 0.75,
+		},
+		{
+			r:            pgtype.Range[float64]{Lower: 0.5, LowerType: pgtype.Exclusive, Upper: 0.75, UpperType: pgtype.Exclusive, Valid: true},
+			expectedSize: 0.25,
+		},
+		{
+			r:            pgtype.Range[float64]{Lower: 0.5, LowerType: pgtype.Inclusive, Upper: 0.5, UpperType: pgtype.Inclusive, Valid: true},
+			expectedSize: 0,
+		},
+		{
+			r:             pgtype.Range[float64]{Lower: 0.5, LowerType: pgtype.Inclusive, Upper: 0.5, UpperType: pgtype.Exclusive, Valid: true},
+			expectedSize:  0,
+			expectedEmpty: true,
+		},
+		{
+			r:             pgtype.Range[float64]{Lower: 0.75, LowerType: pgtype.Inclusive, Upper: 0.5, UpperType: pgtype.Exclusive, Valid: true},
+			expectedSize:  -0.25,
+			expectedEmpty: true,
+		},
+	}
+
+	for _, tt := range tests {
+		if result, err := fro.Size(tt.r); err != nil || result != tt.expectedSize {
+			t.Errorf("size `%v`: expected result `%v`, got `%v` (error `%v`)", tt.r, tt.expectedSize, result, err)
+		}
+		if result, err := fro.Empty(tt.r); err != nil || result != tt.expectedEmpty {
+			t.Errorf("empty `%v`: expected result `%v`, got `%v` (error `%v`)", tt.r, tt.expectedEmpty, result, err)
+		}
+	}
+
+	// a continuous range can be partitioned regardless of its size
+	r := pgtype.Range[float64]{Lower: 0, LowerType: pgtype.Inclusive, Upper: 0.5, UpperType: pgtype.Exclusive, Valid: true}
+	partitions, err := fro.Partition(r, 4)
+	if err != nil || len(partitions) != 4 || partitions[1].Lower != 0.125 || partitions[3].Upper != 0.5 {
+		t.Errorf("partition `%v` in `4`: expected subranges of `0.125`, got `%v` (error `%v`)", r, partitions, err)
+	}
+	if result, err := fro.Midpoint(r); err != nil || result != 0.25 {
+		t.Errorf("midpoint `%v`: expected result `0.25`, got `%v` (error `%v`)", r, result, err)
+	}
+	if _, err := fro.SizeBig(r); err == nil {
+		t.Errorf("size big `%v`: expected error, got none", r)
+	}
+	if result, err := NewRange(fro, 1, 2.5).Size(); err != nil || result != 1.5 {
+		t.Errorf("size `[1,2.5)`: expected result `1.5`, got `%v` (error `%v`)", result, err)
+	}
+}
+
+func TestFloat32(t *testing.T) {
+	fro := NewFloat32()
+	r := pgtype.Range[float32]{Lower: 0.5, LowerType: pgtype.Inclusive, Upper: 1.5, UpperType: pgtype.Exclusive, Valid: true}
+
+	tests := []struct {
+		other           pgtype.Range[float32]
+		expectedOverlap bool
+		expectedContain bool
+	}{
+		{
+			other:           pgtype.Range[float32]{Lower: 0.75, LowerType: pgtype.Inclusive, Upper: 1.25, UpperType: pgtype.Inclusive, Valid: true},
+			expectedOverlap: true,
+			expectedContain: true,
+		},
+		{
+			other:           pgtype.Range[float32]{Lower: 1.5, LowerType: pgtype.Inclusive, Upper: 2.5, UpperType: pgtype.Exclusive, Valid: true},
+			expectedOverlap: false,
+			expectedContain: false,
+		},
+		{
+			other:           pgtype.Range[float32]{Lower: -1, LowerType: pgtype.Inclusive, Upper: 0.5, UpperType: pgtype.Inclusive, Valid: true},
+			expectedOverlap: true,
+			expectedContain: false,
+		},
+	}
+
+	for _, tt := range tests {
+		if result, err := fro.Overlap(r, tt.other); err != nil || result != tt.expectedOverlap {
+			t.Errorf("`%v` && `%v`: expected result `%v`, got `%v` (error `%v`)", r, tt.other, tt.expectedOverlap, result, err)
+		}
+		if result, err := fro.Contain(r, tt.other); err != nil || result != tt.expectedContain {
+			t.Errorf("`%v` @> `%v`: expected result `%v`, got `%v` (error `%v`)", r, tt.other, tt.expectedContain, result, err)
+		}
+	}
+
+	// the size is the length of the range
+	unit := pgtype.Range[float32]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 2.5, UpperType: pgtype.Exclusive, Valid: true}
+	if result, err := fro.Size(unit); err != nil || result != 1.5 {
+		t.Errorf("size `%v`: expected result `1.5`, got `%v` (error `%v`)", unit, result, err)
+	}
+	// both zeros are the same value
+	zero := pgtype.Range[float32]{Lower: float32(math.Copysign(0, -1)), LowerType: pgtype.Inclusive, Upper: 0, UpperType: pgtype.Inclusive, Valid: true}
+	if result, err := fro.Size(zero); err != nil || result != 0 {
+		t.Errorf("size `%v`: expected result `0`, got `%v` (error `%v`)", zero, result, err)
+	}
+
+	// NaN values are ordered before all other values, but ranges with a NaN bound are rejected
+	if result := fro.cmp(float32(math.NaN()), float32(math.Inf(-1))); result != -1 {
+		t.Errorf("compare NaN and -Inf: expected result `-1`, got `%v`", result)
+	}
+}
+
+func TestFloat32Special(t *testing.T) {
+	fro := NewFloat32()
+	inf := float32(math.Inf(1))
+	nan := float32(math.NaN())
+
+	tests := []struct {
+		r                pgtype.Range[float32]
+		expectedLowerInf bool
+		expectedUpperInf bool
+		expectedErr      error
+	}{
+		{
+			r:                pgtype.Range[float32]{Lower: -inf, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+			expectedLowerInf: true,
+		},
+		{
+			r:                pgtype.Range[float32]{Lower: 3, LowerType: pgtype.Exclusive, Upper: inf, UpperType: pgtype.Inclusive, Valid: true},
+			expectedUpperInf: true,
+		},
+		{
+			// a positive infinite lower bound is a value, only the upper bound becomes unbounded
+			r:                pgtype.Range[float32]{Lower: inf, LowerType: pgtype.Inclusive, Upper: inf, UpperType: pgtype.Inclusive, Valid: true},
+			expectedUpperInf: true,
+		},
+		{
+			r:           pgtype.Range[float32]{Lower: nan, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+			expectedErr: ErrNaN,
+		},
+		{
+			r:           pgtype.Range[float32]{Lower: 3, LowerType: pgtype.Inclusive, Upper: nan, UpperType: pgtype.Exclusive, Valid: true},
+			expectedErr: ErrNaN,
+		},
+	}
+
+	other := pgtype.Range[float32]{Lower: 4, LowerType: pgtype.Inclusive, Upper: 4.5, UpperType: pgtype.Inclusive, Valid: true}
+	for _, tt := range tests {
+		err := fro.Validate(tt.r)
+		if tt.expectedErr != nil {
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("validate `%v`: expected error `%v`, got `%v`", tt.r, tt.expectedErr, err)
+			}
+			for name, fn := range map[string]func(pgtype.Range[float32], pgtype.Range[float32]) (bool, error){
+				"&&": fro.Overlap,
+				"@>": fro.Contain,
+				"=":  fro.Equal,
+				"<":  fro.LessThan,
+			} {
+				if _, err := fn(tt.r, other); !errors.Is(err, tt.expectedErr) {
+					t.Errorf("`%v` %s `%v`: expected error `%v`, got `%v`", tt.r, name, other, tt.expectedErr, err)
+				}
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("validate `%v`: expected no error, got `%v`", tt.r, err)
+			continue
+		}
+
+		rewritten := fro.Rewrite(tt.r)
+		if result := rewritten.LowerType == pgtype.Unbounded; result != tt.expectedLowerInf {
+			t.Errorf("lower_inf `%v`: expected result `%v`, got `%v`", tt.r, tt.expectedLowerInf, result)
+		}
+		if result := rewritten.UpperType == pgtype.Unbounded; result != tt.expectedUpperInf {
+			t.Errorf("upper_inf `%v`: expected result `%v`, got `%v`", tt.r, tt.expectedUpperInf, result)
+		}
+		if _, err := fro.Size(tt.r); !errors.Is(err, ErrUnboundedRange) {
+			t.Errorf("size `%v`: expected error `%v`, got `%v`", tt.r, ErrUnboundedRange, err)
+		}
+	}
+
+	r := pgtype.Range[float32]{Lower: -inf, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true}
+	unbounded := pgtype.Range[float32]{LowerType: pgtype.Unbounded, Upper: 5, UpperType: pgtype.Exclusive, Valid: true}
+	if result, err := fro.Equal(r, unbounded); err != nil || !result {
+		t.Errorf("`%v` = `%v`: expected result `true`, got `%v` (error `%v`)", r, unbounded, result, err)
+	}
+	if result, err := fro.ContainElement(r, -1e38); err != nil || !result {
+		t.Errorf("`%v` @> `-1e38`: expected result `true`, got `%v` (error `%v`)", r, result, err)
+	}
+}
+
+func TestForEachStep(t *testing.T) {
+	tests := []struct {
+		r           pgtype.Range[int64]
+		stride      int64
+		expected    []int64
+		expectedErr bool
+	}{
+		{
+			r:        pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+			stride:   2,
+			expected: []int64{0, 2, 4, 6, 8},
+		},
+		{
+			r:        pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Exclusive, Upper: 10, UpperType: pgtype.Inclusive, Valid: true},
+			stride:   3,
+			expected: []int64{1, 4, 7, 10},
+		},
+		{
+			r:        pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Inclusive, Upper: 3, UpperType: pgtype.Exclusive, Valid: true},
+			stride:   5,
+			expected: []int64{0},
+		},
+		{
+			r:           pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+			stride:      0,
+			expectedErr: true,
+		},
+		{
+			r:           pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+			stride:      -1,
+			expectedErr: true,
+		},
+		{
+			r:           pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Inclusive, UpperType: pgtype.Unbounded, Valid: true},
+			stride:      1,
+			expectedErr: true,
+		},
+		{
+			r:           pgtype.Range[int64]{Lower: 5, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+			stride:      1,
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		var result []int64
+		err := iro.ForEachStep(tt.r, tt.stride, func(v int64) error {
+			result = append(result, v)
+			return nil
+		})
+		if err == nil && tt.expectedErr {
+			t.Errorf("for each step `%v` by `%d`: expected error, got none", tt.r, tt.stride)
+		}
+		if err != nil && !tt.expectedErr {
+			t.Errorf("for each step `%v` by `%d`: expected no error, got `%v`", tt.r, tt.stride, err)
+		}
+		if err != nil || tt.expectedErr {
+			continue
+		}
+		if !slices.Equal(result, tt.expected) {
+			t.Errorf("for each step `%v` by `%d`: expected values `%v`, got `%v`", tt.r, tt.stride, tt.expected, result)
+		}
+	}
+
+	var sum int64
+	r := pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true}
+	if err := iro.ForEachStep(r, 2, func(v int64) error { sum += v; return nil }); err != nil || sum != 20 {
+		t.Errorf("sum every 2nd value of `%v`: expected `20`, got `%v` (error `%v`)", r, sum, err)
+	}
+
+	stop := errors.New("stop")
+	count := 0
+	if err := iro.ForEachStep(r, 1, func(v int64) error {
+		count++
+		if v == 3 {
+			return stop
+		}
+		return nil
+	}); !errors.Is(err, stop) || count != 4 {
+		t.Errorf("for each step `%v`: expected to stop after `4` values with error `%v`, got `%v` values and `%v`", r, stop, count, err)
+	}
+
+	// the iteration stops at the maximum value of the type instead of wrapping around
+	top := pgtype.Range[int64]{Lower: math.MaxInt64 - 5, LowerType: pgtype.Inclusive, Upper: math.MaxInt64, UpperType: pgtype.Inclusive, Valid: true}
+	var values []int64
+	if err := iro.ForEachStep(top, 10, func(v int64) error { values = append(values, v); return nil }); err != nil || !slices.Equal(values, []int64{math.MaxInt64 - 5}) {
+		t.Errorf("for each step `%v` by `10`: expected values `%v`, got `%v` (error `%v`)", top, []int64{math.MaxInt64 - 5}, values, err)
+	}
+	values = nil
+	if err := iro.ForEachStep(top, 3, func(v int64) error { values = append(values, v); return nil }); err != nil || !slices.Equal(values, []int64{math.MaxInt64 - 5, math.MaxInt64 - 2}) {
+		t.Errorf("for each step `%v` by `3`: expected values `%v`, got `%v` (error `%v`)", top, []int64{math.MaxInt64 - 5, math.MaxInt64 - 2}, values, err)
+	}
+
+	fro := NewFloat64()
+	floats := pgtype.Range[float64]{Lower: 0, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true}
+	if err := fro.ForEachStep(floats, math.NaN(), func(float64) error { return nil }); err == nil {
+		t.Errorf("for each step `%v` by `NaN`: expected error, got none", floats)
+	}
+	// a stride too small to change the value stops after the first value
+	large := pgtype.Range[float64]{Lower: 1e20, LowerType: pgtype.Inclusive, Upper: 2e20, UpperType: pgtype.Exclusive, Valid: true}
+	count = 0
+	if err := fro.ForEachStep(large, 1, func(float64) error { count++; return nil }); err != nil || count != 1 {
+		t.Errorf("for each step `%v` by `1`: expected `1` value, got `%v` (error `%v`)", large, count, err)
+	}
+
+	week := pgtype.Range[time.Time]{Lower: time.Unix(0, 0), LowerType: pgtype.Inclusive, Upper: time.Unix(0, 0).AddDate(0, 0, 28), UpperType: pgtype.Exclusive, Valid: true}
+	days := 0
+	if err := tro.ForEachStep(week, 7*24*time.Hour, func(time.Time) error { days++; return nil }); err != nil || days != 4 {
+		t.Errorf("for each step `%v` by a week: expected `4` values, got `%v` (error `%v`)", week, days, err)
+	}
+}
+
+func TestCover(t *testing.T) {
+	tests := []struct {
+		rs          []pgtype.Range[int64]
+		expected    pgtype.Range[int64]
+		expectedErr bool
+	}{
+		{
+			rs: []pgtype.Range[int64]{
+				{Lower: 20, LowerType: pgtype.Inclusive, Upper: 30, UpperType: pgtype.Exclusive, Valid: true},
+				{Lower: 0, LowerType: pgtype.Exclusive, Upper: 5, UpperType: pgtype.Inclusive, Valid: true},
+				{Lower: 50, LowerType: pgtype.Inclusive, Upper: 60, UpperType: pgtype.Inclusive, Valid: true},
+			},
+			expected: pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 61, UpperType: pgtype.Exclusive, Valid: true},
+		},
+		{
+			rs: []pgtype.Range[int64]{
+				{Lower: 20, LowerType: pgtype.Inclusive, Upper: 30, UpperType: pgtype.Exclusive, Valid: true},
+				{LowerType: pgtype.Empty, UpperType: pgtype.Empty, Valid: true},
+				{Lower: 25, LowerType: pgtype.Inclusive, UpperType: pgtype.Unbounded, Valid: true},
+			},
+			expected: pgtype.Range[int64]{Lower: 20, LowerType: pgtype.Inclusive, UpperType: pgtype.Unbounded, Valid: true},
+		},
+		{
+			rs: []pgtype.Range[int64]{
+				{LowerType: pgtype.Unbounded, Upper: 0, UpperType: pgtype.Exclusive, Valid: true},
+				{Lower: 25, LowerType: pgtype.Inclusive, Upper: 30, UpperType: pgtype.Exclusive, Valid: true},
+			},
+			expected: pgtype.Range[int64]{LowerType: pgtype.Unbounded, Upper: 30, UpperType: pgtype.Exclusive, Valid: true},
+		},
+		{
+			rs:       nil,
+			expected: pgtype.Range[int64]{LowerType: pgtype.Empty, UpperType: pgtype.Empty, Valid: true},
+		},
+		{
+			rs:          []pgtype.Range[int64]{{Lower: 0, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: false}},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		result, err := iro.Cover(tt.rs)
+		if err == nil && tt.expectedErr {
+			t.Errorf("cover `%v`: expected error, got none", tt.rs)
+		}
+		if err != nil && !tt.expectedErr {
+			t.Errorf("cover `%v`: expected no error, got `%v`", tt.rs, err)
+		}
+		if err != nil || tt.expectedErr {
+			continue
+		}
+		if equal, _ := iro.Equal(tt.expected, result); !equal {
+			t.Errorf("cover `%v`: expected result `%v`, got `%v`", tt.rs, tt.expected, result)
+		}
+	}
+
+	nan := []pgtype.Range[float64]{
+		{Lower: 0, LowerType: pgtype.Inclusive, Upper: 1, UpperType: pgtype.Exclusive, Valid: true},
+		{Lower: 0.5, LowerType: pgtype.Inclusive, Upper: math.NaN(), UpperType: pgtype.Exclusive, Valid: true},
+	}
+	if _, err := NewFloat64().Cover(nan); !errors.Is(err, ErrNaN) {
+		t.Errorf("cover `%v`: expected error `%v`, got `%v`", nan, ErrNaN, err)
+	}
+}
+
+func TestStrictlyOrdered(t *testing.T) {
+	tests := []struct {
+		first    pgtype.Range[int64]
+		second   pgtype.Range[int64]
+		expected bool
+	}{
+		{
+			first:    pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+			second:   pgtype.Range[int64]{Lower: 5, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+			expected: true,
+		},
+		{
+			first:    pgtype.Range[int64]{Lower: 5, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+			second:   pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+			expected: false,
+		},
+		{
+			first:    pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Inclusive, Valid: true},
+			second:   pgtype.Range[int64]{Lower: 5, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+			expected: false,
+		},
+		{
+			first:    pgtype.Range[int64]{LowerType: pgtype.Unbounded, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+			second:   pgtype.Range[int64]{Lower: 5, LowerType: pgtype.Inclusive, UpperType: pgtype.Unbounded, Valid: true},
+			expected: true,
+		},
+		{
+			first:    pgtype.Range[int64]{LowerType: pgtype.Empty, UpperType: pgtype.Empty, Valid: true},
+			second:   pgtype.Range[int64]{Lower: 5, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		result, err := iro.StrictlyOrdered(tt.first, tt.second)
+		if err != nil {
+			t.Errorf("strictly ordered `%v` `%v`: expected no error, got `%v`", tt.first, tt.second, err)
+			continue
+		}
+		if tt.expected != result {
+			t.Errorf("strictly ordered `%v` `%v`: expected result `%v`, got `%v`", tt.first, tt.second, tt.expected, result)
+		}
+		if leftOf, _ := iro.LeftOf(tt.first, tt.second); leftOf != result {
+			t.Errorf("strictly ordered `%v` `%v`: expected the same result as left of `%v`, got `%v`", tt.first, tt.second, leftOf, result)
+		}
+	}
+}
+
+func TestAdd(t *testing.T) {
+	ro := NewInteger()
+	for _, d := range []int{-3, 0, 1, 7} {
+		if result := ro.add(5, d); result != 5+d || ro.diff(result, 5) != d {
+			t.Errorf("add `5` and `%d`: expected result `%d`, got `%d`", d, 5+d, result)
+		}
+	}
+
+	start := time.Unix(0, 0)
+	for _, d := range []time.Duration{-time.Hour, 0, time.Microsecond, 90 * time.Minute} {
+		if result := tro.add(start, d); !result.Equal(start.Add(d)) || tro.diff(result, start) != d {
+			t.Errorf("add `%v` and `%v`: expected result `%v`, got `%v`", start, d, start.Add(d), result)
+		}
+	}
+
+	// the next value is derived from add
+	if result := iro.addOne(41); result != 42 {
+		t.Errorf("add one to `41`: expected result `42`, got `%d`", result)
+	}
+
+	// operations that need add fail for an operator without one
+	ro64 := New(cmp.Compare[int64], func(a, b int64) int64 { return a - b }, func(a int64) int64 { return a + 1 }, true)
+	r := pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true}
+	if _, err := ro64.Shift(r, 1); !errors.Is(err, ErrNoAdd) {
+		t.Errorf("shift without add: expected error `%v`, got `%v`", ErrNoAdd, err)
+	}
+	if _, err := ro64.Midpoint(r); !errors.Is(err, ErrNoAdd) {
+		t.Errorf("midpoint without add: expected error `%v`, got `%v`", ErrNoAdd, err)
+	}
+	if _, err := ro64.Clamp(r, 10); !errors.Is(err, ErrNoAdd) {
+		t.Errorf("clamp without add: expected error `%v`, got `%v`", ErrNoAdd, err)
+	}
+	if contain, err := ro64.ContainElement(r, 4); err != nil || !contain {
+		t.Errorf("contain element without add: expected result `true`, got `%v` (error `%v`)", contain, err)
+	}
+}
+
+func TestContainElement(t *testing.T) {
+	tests := []struct {
+		r           pgtype.Range[int64]
+		elem        int64
+		expected    bool
+		expectedErr bool
+	}{
+		{
+			r:        pgtype.Range[int64]{Lower: math.MaxInt64 - 1, LowerType: pgtype.Inclusive, Upper: math.MaxInt64, UpperType: pgtype.Inclusive, Valid: true},
+			elem:     math.MaxInt64,
+			expected: true,
+		},
+		{
+			r:        pgtype.Range[int64]{Lower: math.MaxInt64 - 1, LowerType: pgtype.Inclusive, Upper: math.MaxInt64, UpperType: pgtype.Exclusive, Valid: true},
+			elem:     math.MaxInt64,
+			expected: false,
+		},
+		{
+			r:        pgtype.Range[int64]{Lower: math.MinInt64, LowerType: pgtype.Inclusive, Upper: math.MinInt64 + 1, UpperType: pgtype.Exclusive, Valid: true},
+			elem:     math.MinInt64,
+			expected: true,
+		},
+		{
+			r:        pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Exclusive, Upper: 5, UpperType: pgtype.Inclusive, Valid: true},
+			elem:     1,
+			expected: false,
+		},
+		{
+			r:        pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Exclusive, Upper: 5, UpperType: pgtype.Inclusive, Valid: true},
+			elem:     5,
+			expected: true,
+		},
+		{
+			r:        pgtype.Range[int64]{LowerType: pgtype.Unbounded, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+			elem:     math.MinInt64,
+			expected: true,
+		},
+		{
+			r:        pgtype.Range[int64]{Lower: 3, LowerType: pgtype.Inclusive, Upper: 3, UpperType: pgtype.Exclusive, Valid: true},
+			elem:     3,
+			expected: false,
+		},
+		{
+			r:        pgtype.Range[int64]{LowerType: pgtype.Empty, UpperType: pgtype.Empty, Valid: true},
+			elem:     0,
+			expected: false,
+		},
+		{
+			r:           pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive},
+			elem:        3,
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		result, err := iro.ContainElement(tt.r, tt.elem)
+		if err == nil && tt.expectedErr {
+			t.Errorf("contain element `%v` and `%v`: expected error, got none", tt.r, tt.elem)
+		}
+		if err != nil && !tt.expectedErr {
+			t.Errorf("contain element `%v` and `%v`: expected no error, got `%v`", tt.r, tt.elem, err)
+		}
+		if err != nil || tt.expectedErr {
+			continue
+		}
+		if result != tt.expected {
+			t.Errorf("contain element `%v` and `%v`: expected result `%v`, got `%v`", tt.r, tt.elem, tt.expected, result)
+		}
+	}
+}
+
+func TestAllDisjoint(t *testing.T) {
+	slot := func(lower, upper int64) pgtype.Range[int64] {
+		return pgtype.Range[int64]{Lower: lower, LowerType: pgtype.Inclusive, Upper: upper, UpperType: pgtype.Exclusive, Valid: true}
+	}
+	tests := []struct {
+		rs          []pgtype.Range[int64]
+		expected    bool
+		expectedA   int
+		expectedB   int
+		expectedErr bool
+	}{
+		{
+			rs:        []pgtype.Range[int64]{slot(20, 30), slot(0, 10), slot(10, 20), slot(50, 60)},
+			expected:  true,
+			expectedA: -1,
+			expectedB: -1,
+		},
+		{
+			rs:        []pgtype.Range[int64]{slot(20, 30), slot(0, 10), slot(50, 60), slot(25, 26)},
+			expected:  false,
+			expectedA: 0,
+			expectedB: 3,
+		},
+		{
+			// the first range ends after the second one, so it overlaps the third
+			rs:        []pgtype.Range[int64]{slot(0, 100), slot(10, 20), slot(30, 40)},
+			expected:  false,
+			expectedA: 0,
+			expectedB: 1,
+		},
+		{
+			rs:        []pgtype.Range[int64]{slot(40, 50), slot(0, 100)},
+			expected:  false,
+			expectedA: 0,
+			expectedB: 1,
+		},
+		{
+			// (9,10] and [10,20) share the element 10
+			rs:        []pgtype.Range[int64]{slot(10, 20), {Lower: 9, LowerType: pgtype.Exclusive, Upper: 10, UpperType: pgtype.Inclusive, Valid: true}},
+			expected:  false,
+			expectedA: 0,
+			expectedB: 1,
+		},
+		{
+			rs:        []pgtype.Range[int64]{slot(0, 10), {LowerType: pgtype.Unbounded, Upper: 0, UpperType: pgtype.Exclusive, Valid: true}, slot(5, 5)},
+			expected:  true,
+			expectedA: -1,
+			expectedB: -1,
+		},
+		{
+			rs:        nil,
+			expected:  true,
+			expectedA: -1,
+			expectedB: -1,
+		},
+		{
+			rs:          []pgtype.Range[int64]{slot(0, 10), {}},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		result, a, b, err := iro.AllDisjoint(tt.rs)
+		if err == nil && tt.expectedErr {
+			t.Errorf("all disjoint `%v`: expected error, got none", tt.rs)
+		}
+		if err != nil && !tt.expectedErr {
+			t.Errorf("all disjoint `%v`: expected no error, got `%v`", tt.rs, err)
+		}
+		if err != nil || tt.expectedErr {
+			continue
+		}
+		if result != tt.expected || a != tt.expectedA || b != tt.expectedB {
+			t.Errorf("all disjoint `%v`: expected result `%v` with `%d` and `%d`, got `%v` with `%d` and `%d`", tt.rs, tt.expected, tt.expectedA, tt.expectedB, result, a, b)
+		}
+	}
+}
+
+func TestProperContain(t *testing.T) {
+	empty := pgtype.Range[int64]{LowerType: pgtype.Empty, UpperType: pgtype.Empty, Valid: true}
+	tests := []struct {
+		first       pgtype.Range[int64]
+		second      pgtype.Range[int64]
+		expected    bool
+		expectedErr bool
+	}{
+		{
+			first:    pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+			second:   pgtype.Range[int64]{Lower: 2, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+			expected: true,
+		},
+		{
+			first:    pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+			second:   pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 9, UpperType: pgtype.Exclusive, Valid: true},
+			expected: true,
+		},
+		{
+			// equal after canonicalization
+			first:    pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+			second:   pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Exclusive, Upper: 9, UpperType: pgtype.Inclusive, Valid: true},
+			expected: false,
+		},
+		{
+			first:    pgtype.Range[int64]{Lower: 2, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+			second:   pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+			expected: false,
+		},
+		{
+			first:    pgtype.Range[int64]{LowerType: pgtype.Unbounded, UpperType: pgtype.Unbounded, Valid: true},
+			second:   pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, UpperType: pgtype.Unbounded, Valid: true},
+			expected: true,
+		},
+		{
+			first:    pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+			second:   empty,
+			expected: true,
+		},
+		{
+			first:    empty,
+			second:   empty,
+			expected: false,
+		},
+		{
+			first:    empty,
+			second:   pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+			expected: false,
+		},
+		{
+			first:       pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive},
+			second:      empty,
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		result, err := iro.ProperContain(tt.first, tt.second)
+		if err == nil && tt.expectedErr {
+			t.Errorf("proper contain `%v` and `%v`: expected error, got none", tt.first, tt.second)
+		}
+		if err != nil && !tt.expectedErr {
+			t.Errorf("proper contain `%v` and `%v`: expected no error, got `%v`", tt.first, tt.second, err)
+		}
+		if err != nil || tt.expectedErr {
+			continue
+		}
+		if result != tt.expected {
+			t.Errorf("proper contain `%v` and `%v`: expected result `%v`, got `%v`", tt.first, tt.second, tt.expected, result)
+		}
+	}
+}
+
+func TestSnapToStep(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, 3, 1, hour, minute, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		r        pgtype.Range[time.Time]
+		expected pgtype.Range[time.Time]
+	}{
+		{
+			r:        pgtype.Range[time.Time]{Lower: at(10, 7), LowerType: pgtype.Inclusive, Upper: at(10, 52), UpperType: pgtype.Exclusive, Valid: true},
+			expected: pgtype.Range[time.Time]{Lower: at(10, 0), LowerType: pgtype.Inclusive, Upper: at(11, 0), UpperType: pgtype.Exclusive, Valid: true},
+		},
+		{
+			r:        pgtype.Range[time.Time]{Lower: at(10, 15), LowerType: pgtype.Inclusive, Upper: at(10, 45), UpperType: pgtype.Exclusive, Valid: true},
+			expected: pgtype.Range[time.Time]{Lower: at(10, 15), LowerType: pgtype.Inclusive, Upper: at(10, 45), UpperType: pgtype.Exclusive, Valid: true},
+		},
+		{
+			r:        pgtype.Range[time.Time]{Lower: at(10, 15), LowerType: pgtype.Exclusive, Upper: at(10, 45), UpperType: pgtype.Inclusive, Valid: true},
+			expected: pgtype.Range[time.Time]{Lower: at(10, 15), LowerType: pgtype.Inclusive, Upper: at(11, 0), UpperType: pgtype.Exclusive, Valid: true},
+		},
+		{
+			r:        pgtype.Range[time.Time]{LowerType: pgtype.Unbounded, Upper: at(10, 52), UpperType: pgtype.Exclusive, Valid: true},
+			expected: pgtype.Range[time.Time]{LowerType: pgtype.Unbounded, Upper: at(11, 0), UpperType: pgtype.Exclusive, Valid: true},
+		},
+		{
+			r:        pgtype.Range[time.Time]{LowerType: pgtype.Empty, UpperType: pgtype.Empty, Valid: true},
+			expected: pgtype.Range[time.Time]{LowerType: pgtype.Empty, UpperType: pgtype.Empty, Valid: true},
+		},
+	}
+
+	for _, tt := range tests {
+		if result := SnapToStep(tt.r, 15*time.Minute); !reflect.DeepEqual(tt.expected, result) {
+			t.Errorf("snap `%v` to `%v`: expected result `%v`, got `%v`", tt.r, 15*time.Minute, tt.expected, result)
+		}
+	}
+
+	// snapped ranges of consecutive slots are adjacent
+	first := SnapToStep(pgtype.Range[time.Time]{Lower: at(10, 7), LowerType: pgtype.Inclusive, Upper: at(10, 29), UpperType: pgtype.Exclusive, Valid: true}, 15*time.Minute)
+	second := SnapToStep(pgtype.Range[time.Time]{Lower: at(10, 31), LowerType: pgtype.Inclusive, Upper: at(10, 52), UpperType: pgtype.Exclusive, Valid: true}, 15*time.Minute)
+	if adjacent, err := tro.Adjacent(first, second); err != nil || !adjacent {
+		t.Errorf("adjacent `%v` and `%v`: expected result `true`, got `%v` (error `%v`)", first, second, adjacent, err)
+	}
+
+	// a step that is not positive leaves the range unchanged, like time.Time.Truncate
+	r := pgtype.Range[time.Time]{Lower: at(10, 7), LowerType: pgtype.Exclusive, Upper: at(10, 52), UpperType: pgtype.Inclusive, Valid: true}
+	for _, step := range []time.Duration{0, -15 * time.Minute} {
+		if result := SnapToStep(r, step); !reflect.DeepEqual(r, result) {
+			t.Errorf("snap `%v` to `%v`: expected result `%v`, got `%v`", r, step, r, result)
+		}
+	}
+}
+
+func TestBetween(t *testing.T) {
+	tests := []struct {
+		lower         int64
+		upper         int64
+		lowerType     pgtype.BoundType
+		upperType     pgtype.BoundType
+		expected      pgtype.Range[int64]
+		expectedEmpty bool
+		expectedErr   bool
+	}{
+		{
+			lower: 1, upper: 5, lowerType: pgtype.Inclusive, upperType: pgtype.Exclusive,
+			expected: pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+		},
+		{
+			lower: 3, upper: 3, lowerType: pgtype.Inclusive, upperType: pgtype.Inclusive,
+			expected: pgtype.Range[int64]{Lower: 3, LowerType: pgtype.Inclusive, Upper: 3, UpperType: pgtype.Inclusive, Valid: true},
+		},
+		{
+			lower: 3, upper: 3, lowerType: pgtype.Inclusive, upperType: pgtype.Exclusive,
+			expected:      pgtype.Range[int64]{Lower: 3, LowerType: pgtype.Inclusive, Upper: 3, UpperType: pgtype.Exclusive, Valid: true},
+			expectedEmpty: true,
+		},
+		{
+			lower: 7, upper: 5, lowerType: pgtype.Unbounded, upperType: pgtype.Inclusive,
+			expected: pgtype.Range[int64]{LowerType: pgtype.Unbounded, Upper: 5, UpperType: pgtype.Inclusive, Valid: true},
+		},
+		{
+			lower: 5, upper: 1, lowerType: pgtype.Inclusive, upperType: pgtype.Exclusive,
+			expectedErr: true,
+		},
+		{
+			lower: 1, upper: 5, lowerType: pgtype.Empty, upperType: pgtype.Exclusive,
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		result, err := iro.Between(tt.lower, tt.upper, tt.lowerType, tt.upperType)
+		if err == nil && tt.expectedErr {
+			t.Errorf("between `%v` and `%v`: expected error, got none", tt.lower, tt.upper)
+		}
+		if err != nil && !tt.expectedErr {
+			t.Errorf("between `%v` and `%v`: expected no error, got `%v`", tt.lower, tt.upper, err)
+		}
+		if err != nil || tt.expectedErr {
+			continue
+		}
+		if !reflect.DeepEqual(tt.expected, result) {
+			t.Errorf("between `%v` and `%v`: expected result `%v`, got `%v`", tt.lower, tt.upper, tt.expected, result)
+		}
+		if empty, _ := iro.Empty(result); empty != tt.expectedEmpty {
+			t.Errorf("between `%v` and `%v`: expected empty `%v`, got `%v`", tt.lower, tt.upper, tt.expectedEmpty, empty)
+		}
+	}
+}
+
+func TestHalfEmpty(t *testing.T) {
+	normal := pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true}
+	tests := []pgtype.Range[int64]{
+		{Lower: 1, LowerType: pgtype.Empty, Upper: 5, UpperType: pgtype.Inclusive, Valid: true},
+		{Lower: 1, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Empty, Valid: true},
+		{LowerType: pgtype.Empty, UpperType: pgtype.Unbounded, Valid: true},
+		{LowerType: pgtype.Unbounded, UpperType: pgtype.Empty, Valid: true},
+	}
+
+	for _, r := range tests {
+		if result, err := iro.Empty(r); err != nil || !result {
+			t.Errorf("isempty `%v`: expected result `true`, got `%v` (error `%v`)", r, result, err)
+		}
+		if result := iro.Rewrite(r); !reflect.DeepEqual(result, makeEmptyRange[int64]()) {
+			t.Errorf("rewrite `%v`: expected result `%v`, got `%v`", r, makeEmptyRange[int64](), result)
+		}
+		if result, err := iro.Equal(r, makeEmptyRange[int64]()); err != nil || !result {
+			t.Errorf("`%v` = `empty`: expected result `true`, got `%v` (error `%v`)", r, result, err)
+		}
+		if result, err := iro.LessThan(r, normal); err != nil || !result {
+			t.Errorf("`%v` < `%v`: expected result `true`, got `%v` (error `%v`)", r, normal, result, err)
+		}
+		if result, err := iro.Overlap(r, normal); err != nil || result {
+			t.Errorf("`%v` && `%v`: expected result `false`, got `%v` (error `%v`)", r, normal, result, err)
+		}
+		if result, err := iro.Contain(normal, r); err != nil || !result {
+			t.Errorf("`%v` @> `%v`: expected result `true`, got `%v` (error `%v`)", normal, r, result, err)
+		}
+		if result, err := iro.ContainElement(r, 3); err != nil || result {
+			t.Errorf("`%v` @> `3`: expected result `false`, got `%v` (error `%v`)", r, result, err)
+		}
+		if result, err := iro.Union(r, normal); err != nil || !reflect.DeepEqual(result, normal) {
+			t.Errorf("`%v` + `%v`: expected result `%v`, got `%v` (error `%v`)", r, normal, normal, result, err)
+		}
+	}
+}
+
+func TestMergeInfo(t *testing.T) {
+	tests := []struct {
+		first              pgtype.Range[int64]
+		second             pgtype.Range[int64]
+		expected           pgtype.Range[int64]
+		expectedContiguous bool
+		expectedErr        bool
+	}{
+		{
+			// overlapping
+			first:              pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+			second:             pgtype.Range[int64]{Lower: 3, LowerType: pgtype.Inclusive, Upper: 8, UpperType: pgtype.Exclusive, Valid: true},
+			expected:           pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 8, UpperType: pgtype.Exclusive, Valid: true},
+			expectedContiguous: true,
+		},
+		{
+			// adjacent
+			first:              pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+			second:             pgtype.Range[int64]{Lower: 5, LowerType: pgtype.Inclusive, Upper: 8, UpperType: pgtype.Exclusive, Valid: true},
+			expected:           pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 8, UpperType: pgtype.Exclusive, Valid: true},
+			expectedContiguous: true,
+		},
+		{
+			// gapped
+			first:              pgtype.Range[int64]{Lower: 6, LowerType: pgtype.Inclusive, Upper: 8, UpperType: pgtype.Exclusive, Valid: true},
+			second:             pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+			expected:           pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 8, UpperType: pgtype.Exclusive, Valid: true},
+			expectedContiguous: false,
+		},
+		{
+			first:       pgtype.Range[int64]{},
+			second:      pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		result, contiguous, err := iro.MergeInfo(tt.first, tt.second)
+		if err == nil && tt.expectedErr {
+			t.Errorf("merge `%v` and `%v`: expected error, got none", tt.first, tt.second)
+		}
+		if err != nil && !tt.expectedErr {
+			t.Errorf("merge `%v` and `%v`: expected no error, got `%v`", tt.first, tt.second, err)
+		}
+		if err != nil || tt.expectedErr {
+			continue
+		}
+		if !reflect.DeepEqual(tt.expected, result) {
+			t.Errorf("merge `%v` and `%v`: expected result `%v`, got `%v`", tt.first, tt.second, tt.expected, result)
+		}
+		if contiguous != tt.expectedContiguous {
+			t.Errorf("merge `%v` and `%v`: expected contiguous `%v`, got `%v`", tt.first, tt.second, tt.expectedContiguous, contiguous)
+		}
+	}
+}
+
+func TestCoverage(t *testing.T) {
+	target := pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Inclusive, Upper: 100, UpperType: pgtype.Exclusive, Valid: true}
+	tests := []struct {
+		target      pgtype.Range[int64]
+		rs          []pgtype.Range[int64]
+		expected    float64
+		expectedErr bool
+	}{
+		{
+			// two half overlapping ranges cover three quarters of the target
+			target: target,
+			rs: []pgtype.Range[int64]{
+				{Lower: 0, LowerType: pgtype.Inclusive, Upper: 50, UpperType: pgtype.Exclusive, Valid: true},
+				{Lower: 25, LowerType: pgtype.Inclusive, Upper: 75, UpperType: pgtype.Exclusive, Valid: true},
+			},
+			expected: 0.75,
+		},
+		{
+			// parts outside the target are clipped
+			target: target,
+			rs: []pgtype.Range[int64]{
+				{Lower: -50, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+				{Lower: 90, LowerType: pgtype.Inclusive, UpperType: pgtype.Unbounded, Valid: true},
+			},
+			expected: 0.2,
+		},
+		{
+			target: target,
+			rs: []pgtype.Range[int64]{
+				{LowerType: pgtype.Unbounded, UpperType: pgtype.Unbounded, Valid: true},
+			},
+			expected: 1,
+		},
+		{
+			target:   target,
+			rs:       nil,
+			expected: 0,
+		},
+		{
+			target:      pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Inclusive, UpperType: pgtype.Unbounded, Valid: true},
+			expectedErr: true,
+		},
+		{
+			target:      makeEmptyRange[int64](),
+			expectedErr: true,
+		},
+		{
+			target:      target,
+			rs:          []pgtype.Range[int64]{{}},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		result, err := iro.Coverage(tt.target, tt.rs)
+		if err == nil && tt.expectedErr {
+			t.Errorf("coverage of `%v` by `%v`: expected error, got none", tt.target, tt.rs)
+		}
+		if err != nil && !tt.expectedErr {
+			t.Errorf("coverage of `%v` by `%v`: expected no error, got `%v`", tt.target, tt.rs, err)
+		}
+		if err != nil || tt.expectedErr {
+			continue
+		}
+		if result != tt.expected {
+			t.Errorf("coverage of `%v` by `%v`: expected result `%v`, got `%v`", tt.target, tt.rs, tt.expected, result)
+		}
+	}
+}
+
+func TestScale(t *testing.T) {
+	tests := []struct {
+		r           pgtype.Range[int64]
+		factor      float64
+		expected    pgtype.Range[int64]
+		expectedErr bool
+	}{
+		{
+			r:        pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+			factor:   2,
+			expected: pgtype.Range[int64]{Lower: -5, LowerType: pgtype.Inclusive, Upper: 15, UpperType: pgtype.Exclusive, Valid: true},
+		},
+		{
+			r:        pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+			factor:   0.5,
+			expected: pgtype.Range[int64]{Lower: 2, LowerType: pgtype.Inclusive, Upper: 7, UpperType: pgtype.Exclusive, Valid: true},
+		},
+		{
+			r:        pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Inclusive, Valid: true},
+			factor:   1,
+			expected: pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Inclusive, Upper: 11, UpperType: pgtype.Exclusive, Valid: true},
+		},
+		{
+			r:        pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+			factor:   0,
+			expected: pgtype.Range[int64]{LowerType: pgtype.Empty, UpperType: pgtype.Empty, Valid: true},
+		},
+		{
+			r:        pgtype.Range[int64]{LowerType: pgtype.Empty, UpperType: pgtype.Empty, Valid: true},
+			factor:   2,
+			expected: pgtype.Range[int64]{LowerType: pgtype.Empty, UpperType: pgtype.Empty, Valid: true},
+		},
+		{
+			r:           pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+			factor:      -1,
+			expectedErr: true,
+		},
+		{
+			r:           pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+			factor:      1e30,
+			expectedErr: true,
+		},
+		{
+			r:           pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Inclusive, UpperType: pgtype.Unbounded, Valid: true},
+			factor:      2,
+			expectedErr: true,
+		},
+		{
+			r:           pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: false},
+			factor:      2,
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		result, err := iro.Scale(tt.r, tt.factor)
+		if err == nil && tt.expectedErr {
+			t.Errorf("scale `%v` by `%v`: expected error, got none", tt.r, tt.factor)
+		}
+		if err != nil && !tt.expectedErr {
+			t.Errorf("scale `%v` by `%v`: expected no error, got `%v`", tt.r, tt.factor, err)
+		}
+		if err != nil || tt.expectedErr {
+			continue
+		}
+		if !reflect.DeepEqual(tt.expected, result) {
+			t.Errorf("scale `%v` by `%v`: expected result `%v`, got `%v`", tt.r, tt.factor, tt.expected, result)
+		}
+	}
+
+	// continuous ranges are scaled without rounding
+	r := pgtype.Range[float64]{Lower: 0, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true}
+	expected := pgtype.Range[float64]{Lower: 2.5, LowerType: pgtype.Inclusive, Upper: 7.5, UpperType: pgtype.Exclusive, Valid: true}
+	if result, err := NewFloat64().Scale(r, 0.5); err != nil || !reflect.DeepEqual(expected, result) {
+		t.Errorf("scale `%v` by `0.5`: expected result `%v`, got `%v` (error `%v`)", r, expected, result, err)
+	}
+}
+
+func TestLess(t *testing.T) {
+	rs := []pgtype.Range[int64]{
+		{Lower: 5, LowerType: pgtype.Inclusive, Upper: 9, UpperType: pgtype.Exclusive, Valid: true},
+		{},
+		{Lower: 1, LowerType: pgtype.Inclusive, Upper: 9, UpperType: pgtype.Exclusive, Valid: true},
+		{LowerType: pgtype.Empty, UpperType: pgtype.Empty, Valid: true},
+		{LowerType: pgtype.Unbounded, Upper: 3, UpperType: pgtype.Inclusive, Valid: true},
+		{Lower: 0, LowerType: pgtype.Exclusive, Upper: 4, UpperType: pgtype.Inclusive, Valid: true},
+		{Lower: 1, LowerType: pgtype.Inclusive, UpperType: pgtype.Unbounded, Valid: true},
+	}
+	expected := []pgtype.Range[int64]{
+		{LowerType: pgtype.Empty, UpperType: pgtype.Empty, Valid: true},
+		{LowerType: pgtype.Unbounded, Upper: 3, UpperType: pgtype.Inclusive, Valid: true},
+		{Lower: 0, LowerType: pgtype.Exclusive, Upper: 4, UpperType: pgtype.Inclusive, Valid: true},
+		{Lower: 1, LowerType: pgtype.Inclusive, Upper: 9, UpperType: pgtype.Exclusive, Valid: true},
+		{Lower: 1, LowerType: pgtype.Inclusive, UpperType: pgtype.Unbounded, Valid: true},
+		{Lower: 5, LowerType: pgtype.Inclusive, Upper: 9, UpperType: pgtype.Exclusive, Valid: true},
+		{},
+	}
+
+	sorted := slices.Clone(rs)
+	slices.SortFunc(sorted, func(a, b pgtype.Range[int64]) int {
+		if iro.Less(a, b) {
+			return -1
+		}
+		if iro.Less(b, a) {
+			return 1
+		}
+		return 0
+	})
+	if !reflect.DeepEqual(expected, sorted) {
+		t.Errorf("sort less `%v`: expected result `%v`, got `%v`", rs, expected, sorted)
+	}
+	sorted = slices.Clone(rs)
+	slices.SortFunc(sorted, iro.Compare)
+	if !reflect.DeepEqual(expected, sorted) {
+		t.Errorf("sort func `%v`: expected result `%v`, got `%v`", rs, expected, sorted)
+	}
+
+	// the order of the valid ranges matches LessThan of the wrapper
+	var wrapped []Range[int64, int64]
+	for _, r := range rs {
+		if r.Valid {
+			wrapped = append(wrapped, Range[int64, int64]{r: r, ro: iro})
+		}
+	}
+	slices.SortFunc(wrapped, func(a, b Range[int64, int64]) int {
+		if less, _ := a.LessThan(b); less {
+			return -1
+		}
+		if greater, _ := a.GreaterThan(b); greater {
+			return 1
+		}
+		return 0
+	})
+	for i, r := range wrapped {
+		if !reflect.DeepEqual(expected[i], r.r) {
+			t.Errorf("sort wrapped: expected result `%v` at %d, got `%v`", expected[i], i, r.r)
+		}
+	}
+}
+
+func TestEqualEmpty(t *testing.T) {
+	empties := []pgtype.Range[int64]{
+		makeEmptyRange[int64](),
+		{Lower: 5, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+		{Lower: 3, LowerType: pgtype.Exclusive, Upper: 3, UpperType: pgtype.Inclusive, Valid: true},
+		{Lower: 5, LowerType: pgtype.Exclusive, Upper: 6, UpperType: pgtype.Exclusive, Valid: true},
+		{Lower: 4, LowerType: pgtype.Exclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+		{Lower: math.MaxInt64, LowerType: pgtype.Inclusive, Upper: math.MaxInt64, UpperType: pgtype.Exclusive, Valid: true},
+		{LowerType: pgtype.Empty, UpperType: pgtype.Unbounded, Valid: true},
+	}
+	for _, first := range empties {
+		for _, second := range empties {
+			if result, err := iro.Equal(first, second); err != nil || !result {
+				t.Errorf("`%v` = `%v`: expected result `true`, got `%v` (error `%v`)", first, second, result, err)
+			}
+		}
+		nonEmpty := pgtype.Range[int64]{Lower: 5, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Inclusive, Valid: true}
+		if result, err := iro.Equal(first, nonEmpty); err != nil || result {
+			t.Errorf("`%v` = `%v`: expected result `false`, got `%v` (error `%v`)", first, nonEmpty, result, err)
+		}
+	}
+
+	// a continuous range with equal bounds is only empty if a bound is exclusive
+	fro := NewFloat64()
+	floatEmpties := []pgtype.Range[float64]{
+		makeEmptyRange[float64](),
+		{Lower: 5, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+		{Lower: 3, LowerType: pgtype.Exclusive, Upper: 3, UpperType: pgtype.Inclusive, Valid: true},
+		{Lower: 3, LowerType: pgtype.Exclusive, Upper: 3, UpperType: pgtype.Exclusive, Valid: true},
+	}
+	for _, first := range floatEmpties {
+		for _, second := range floatEmpties {
+			if result, err := fro.Equal(first, second); err != nil || !result {
+				t.Errorf("`%v` = `%v`: expected result `true`, got `%v` (error `%v`)", first, second, result, err)
+			}
+		}
+		nonEmpty := pgtype.Range[float64]{Lower: 5, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Inclusive, Valid: true}
+		if result, err := fro.Equal(first, nonEmpty); err != nil || result {
+			t.Errorf("`%v` = `%v`: expected result `false`, got `%v` (error `%v`)", first, nonEmpty, result, err)
+		}
+		open := pgtype.Range[float64]{Lower: 4, LowerType: pgtype.Exclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true}
+		if result, err := fro.Equal(first, open); err != nil || result {
+			t.Errorf("`%v` = `%v`: expected result `false`, got `%v` (error `%v`)", first, open, result, err)
+		}
+	}
+
+	now := time.Now()
+	timeEmpties := []pgtype.Range[time.Time]{
+		makeEmptyRange[time.Time](),
+		{Lower: now, LowerType: pgtype.Inclusive, Upper: now, UpperType: pgtype.Exclusive, Valid: true},
+		{Lower: now, LowerType: pgtype.Exclusive, Upper: now, UpperType: pgtype.Inclusive, Valid: true},
+	}
+	for _, first := range timeEmpties {
+		for _, second := range timeEmpties {
+			if result, err := tro.Equal(first, second); err != nil || !result {
+				t.Errorf("`%v` = `%v`: expected result `true`, got `%v` (error `%v`)", first, second, result, err)
+			}
+		}
+	}
+}
+
+func TestSingleton(t *testing.T) {
+	r := iro.Singleton(5)
+	if size, err := iro.Size(r); err != nil || size != 1 {
+		t.Errorf("size `%v`: expected result `1`, got `%v` (error `%v`)", r, size, err)
+	}
+	for _, elem := range []int64{4, 5, 6} {
+		if result, err := iro.ContainElement(r, elem); err != nil || result != (elem == 5) {
+			t.Errorf("`%v` @> `%v`: expected result `%v`, got `%v` (error `%v`)", r, elem, elem == 5, result, err)
+		}
+	}
+
+	ir := NewSingletonIntegerRange(5)
+	if size, err := ir.Size(); err != nil || size != 1 {
+		t.Errorf("size `%v`: expected result `1`, got `%v` (error `%v`)", ir, size, err)
+	}
+	for _, elem := range []int{4, 5, 6} {
+		if result, err := ir.ContainElement(elem); err != nil || result != (elem == 5) {
+			t.Errorf("`%v` @> `%v`: expected result `%v`, got `%v` (error `%v`)", ir, elem, elem == 5, result, err)
+		}
+	}
+
+	// a continuous singleton is not empty
+	fr := NewFloat64().Singleton(0.5)
+	if result, err := NewFloat64().Empty(fr); err != nil || result {
+		t.Errorf("isempty `%v`: expected result `false`, got `%v` (error `%v`)", fr, result, err)
+	}
+}
+
+func TestToClosed(t *testing.T) {
+	tests := []struct {
+		r           pgtype.Range[int64]
+		expected    pgtype.Range[int64]
+		expectedErr bool
+	}{
+		{
+			r:        pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+			expected: pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 4, UpperType: pgtype.Inclusive, Valid: true},
+		},
+		{
+			r:        pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Exclusive, Upper: 4, UpperType: pgtype.Inclusive, Valid: true},
+			expected: pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 4, UpperType: pgtype.Inclusive, Valid: true},
+		},
+		{
+			r:        pgtype.Range[int64]{Lower: 3, LowerType: pgtype.Inclusive, Upper: 3, UpperType: pgtype.Inclusive, Valid: true},
+			expected: pgtype.Range[int64]{Lower: 3, LowerType: pgtype.Inclusive, Upper: 3, UpperType: pgtype.Inclusive, Valid: true},
+		},
+		{
+			r:           pgtype.Range[int64]{Lower: 3, LowerType: pgtype.Inclusive, Upper: 3, UpperType: pgtype.Exclusive, Valid: true},
+			expectedErr: true,
+		},
+		{
+			r:           pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, UpperType: pgtype.Unbounded, Valid: true},
+			expectedErr: true,
+		},
+		{
+			r:           pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: math.MaxInt64, UpperType: pgtype.Inclusive, Valid: true},
+			expectedErr: true,
+		},
+		{
+			r:           pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: false},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		result, err := iro.ToClosed(tt.r)
+		if err == nil && tt.expectedErr {
+			t.Errorf("to closed `%v`: expected error, got none", tt.r)
+		}
+		if err != nil && !tt.expectedErr {
+			t.Errorf("to closed `%v`: expected no error, got `%v`", tt.r, err)
+		}
+		if err != nil || tt.expectedErr {
+			continue
+		}
+		if !reflect.DeepEqual(tt.expected, result) {
+			t.Errorf("to closed `%v`: expected result `%v`, got `%v`", tt.r, tt.expected, result)
+		}
+		// and back to the canonical form
+		back, err := iro.ToHalfOpen(result)
+		if err != nil {
+			t.Errorf("to half-open `%v`: expected no error, got `%v`", result, err)
+		} else if !reflect.DeepEqual(iro.Rewrite(tt.r), back) {
+			t.Errorf("to half-open `%v`: expected result `%v`, got `%v`", result, iro.Rewrite(tt.r), back)
+		}
+	}
+
+	if _, err := NewFloat64().ToClosed(pgtype.Range[float64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true}); err == nil {
+		t.Errorf("to closed continuous range: expected error, got none")
+	}
+}
+
+func TestAdjacentWithin(t *testing.T) {
+	fro := NewFloat64()
+	tests := []struct {
+		first       pgtype.Range[float64]
+		second      pgtype.Range[float64]
+		epsilon     float64
+		expected    bool
+		expectedErr bool
+	}{
+		{
+			first:    pgtype.Range[float64]{Lower: 1.0, LowerType: pgtype.Inclusive, Upper: 2.0, UpperType: pgtype.Exclusive, Valid: true},
+			second:   pgtype.Range[float64]{Lower: 2.0000001, LowerType: pgtype.Inclusive, Upper: 3.0, UpperType: pgtype.Exclusive, Valid: true},
+			epsilon:  1e-5,
+			expected: true,
+		},
+		{
+			first:    pgtype.Range[float64]{Lower: 2.0000001, LowerType: pgtype.Inclusive, Upper: 3.0, UpperType: pgtype.Exclusive, Valid: true},
+			second:   pgtype.Range[float64]{Lower: 1.0, LowerType: pgtype.Inclusive, Upper: 2.0, UpperType: pgtype.Exclusive, Valid: true},
+			epsilon:  1e-5,
+			expected: true,
+		},
+		{
+			// the upper bound is slightly above the lower bound of the other range
+			first:    pgtype.Range[float64]{Lower: 1.0, LowerType: pgtype.Inclusive, Upper: 2.0000001, UpperType: pgtype.Exclusive, Valid: true},
+			second:   pgtype.Range[float64]{Lower: 2.0, LowerType: pgtype.Inclusive, Upper: 3.0, UpperType: pgtype.Exclusive, Valid: true},
+			epsilon:  1e-5,
+			expected: true,
+		},
+		{
+			first:    pgtype.Range[float64]{Lower: 1.0, LowerType: pgtype.Inclusive, Upper: 2.0, UpperType: pgtype.Exclusive, Valid: true},
+			second:   pgtype.Range[float64]{Lower: 2.0000001, LowerType: pgtype.Inclusive, Upper: 3.0, UpperType: pgtype.Exclusive, Valid: true},
+			epsilon:  0,
+			expected: false,
+		},
+		{
+			// both bounds are inclusive, the ranges overlap
+			first:    pgtype.Range[float64]{Lower: 1.0, LowerType: pgtype.Inclusive, Upper: 2.0, UpperType: pgtype.Inclusive, Valid: true},
+			second:   pgtype.Range[float64]{Lower: 2.0000001, LowerType: pgtype.Inclusive, Upper: 3.0, UpperType: pgtype.Exclusive, Valid: true},
+			epsilon:  1e-5,
+			expected: false,
+		},
+		{
+			first:    pgtype.Range[float64]{Lower: 1.0, LowerType: pgtype.Inclusive, Upper: 2.0, UpperType: pgtype.Exclusive, Valid: true},
+			second:   pgtype.Range[float64]{Lower: 2.1, LowerType: pgtype.Inclusive, Upper: 3.0, UpperType: pgtype.Exclusive, Valid: true},
+			epsilon:  1e-5,
+			expected: false,
+		},
+		{
+			first:    pgtype.Range[float64]{Lower: 1.0, LowerType: pgtype.Inclusive, Upper: 2.0, UpperType: pgtype.Exclusive, Valid: true},
+			second:   pgtype.Range[float64]{LowerType: pgtype.Empty, UpperType: pgtype.Empty, Valid: true},
+			epsilon:  1e-5,
+			expected: false,
+		},
+		{
+			first:       pgtype.Range[float64]{Lower: 1.0, LowerType: pgtype.Inclusive, Upper: 2.0, UpperType: pgtype.Exclusive, Valid: true},
+			second:      pgtype.Range[float64]{Lower: 2.0, LowerType: pgtype.Inclusive, Upper: 3.0, UpperType: pgtype.Exclusive, Valid: true},
+			epsilon:     -1,
+			expectedErr: true,
+		},
+		{
+			first:       pgtype.Range[float64]{Lower: 1.0, LowerType: pgtype.Inclusive, Upper: 2.0, UpperType: pgtype.Exclusive, Valid: false},
+			second:      pgtype.Range[float64]{Lower: 2.0, LowerType: pgtype.Inclusive, Upper: 3.0, UpperType: pgtype.Exclusive, Valid: true},
+			epsilon:     1e-5,
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		result, err := fro.AdjacentWithin(tt.first, tt.second, tt.epsilon)
+		if err == nil && tt.expectedErr {
+			t.Errorf("`%v` -|- `%v` within `%v`: expected error, got none", tt.first, tt.second, tt.epsilon)
+		}
+		if err != nil && !tt.expectedErr {
+			t.Errorf("`%v` -|- `%v` within `%v`: expected no error, got `%v`", tt.first, tt.second, tt.epsilon, err)
+		}
+		if err != nil || tt.expectedErr {
+			continue
+		}
+		if result != tt.expected {
+			t.Errorf("`%v` -|- `%v` within `%v`: expected result `%v`, got `%v`", tt.first, tt.second, tt.epsilon, tt.expected, result)
+		}
+	}
+
+	if _, err := iro.AdjacentWithin(pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 2, UpperType: pgtype.Exclusive, Valid: true}, pgtype.Range[int64]{Lower: 2, LowerType: pgtype.Inclusive, Upper: 3, UpperType: pgtype.Exclusive, Valid: true}, 1); err == nil {
+		t.Errorf("adjacent within for a discrete range: expected error, got none")
+	}
+}
+
+func TestCompareAllBounds(t *testing.T) {
+	first := pgtype.Range[int64]{Lower: 3, LowerType: pgtype.Inclusive, Upper: 7, UpperType: pgtype.Exclusive, Valid: true}
+	tests := []struct {
+		second   pgtype.Range[int64]
+		expected [4]int
+	}{
+		{
+			// before
+			second:   pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Inclusive, Upper: 2, UpperType: pgtype.Exclusive, Valid: true},
+			expected: [4]int{1, 1, 1, 1},
+		},
+		{
+			// adjacent before, an exclusive upper bound is below an inclusive lower bound
+			second:   pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Inclusive, Upper: 2, UpperType: pgtype.Inclusive, Valid: true},
+			expected: [4]int{1, 1, 1, 1},
+		},
+		{
+			// overlapping the lower bound
+			second:   pgtype.Range[int64]{Lower: 0, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+			expected: [4]int{1, -1, 1, 1},
+		},
+		{
+			// equal after canonicalization
+			second:   pgtype.Range[int64]{Lower: 2, LowerType: pgtype.Exclusive, Upper: 6, UpperType: pgtype.Inclusive, Valid: true},
+			expected: [4]int{0, -1, 1, 0},
+		},
+		{
+			// within
+			second:   pgtype.Range[int64]{Lower: 4, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+			expected: [4]int{-1, -1, 1, 1},
+		},
+		{
+			// overlapping the upper bound
+			second:   pgtype.Range[int64]{Lower: 5, LowerType: pgtype.Inclusive, Upper: 9, UpperType: pgtype.Exclusive, Valid: true},
+			expected: [4]int{-1, -1, 1, -1},
+		},
+		{
+			// adjacent after
+			second:   pgtype.Range[int64]{Lower: 7, LowerType: pgtype.Inclusive, Upper: 9, UpperType: pgtype.Exclusive, Valid: true},
+			expected: [4]int{-1, -1, -1, -1},
+		},
+		{
+			second:   pgtype.Range[int64]{LowerType: pgtype.Unbounded, UpperType: pgtype.Unbounded, Valid: true},
+			expected: [4]int{1, -1, 1, -1},
+		},
+	}
+
+	for _, tt := range tests {
+		l1l2, l1u2, u1l2, u1u2 := iro.CompareAllBounds(first, tt.second)
+		result := [4]int{sign(l1l2), sign(l1u2), sign(u1l2), sign(u1u2)}
+		if result != tt.expected {
+			t.Errorf("compare all bounds `%v` `%v`: expected result `%v`, got `%v`", first, tt.second, tt.expected, result)
+		}
+	}
+
+	// overlap derived from the bounds matches overlap derived from the elements
+	boundTypes := []pgtype.BoundType{pgtype.Inclusive, pgtype.Exclusive, pgtype.Unbounded}
+	var rs []pgtype.Range[int64]
+	for lower := int64(0); lower < 4; lower++ {
+		for upper := lower; upper < 4; upper++ {
+			for _, lowerType := range boundTypes {
+				for _, upperType := range boundTypes {
+					rs = append(rs, pgtype.Range[int64]{Lower: lower, LowerType: lowerType, Upper: upper, UpperType: upperType, Valid: true})
+				}
+			}
+		}
+	}
+	for _, a := range rs {
+		for _, b := range rs {
+			expected := false
+			for v := int64(-1); v < 5; v++ {
+				inA, _ := iro.ContainElement(a, v)
+				inB, _ := iro.ContainElement(b, v)
+				expected = expected || (inA && inB)
+			}
+			if result, err := iro.Overlap(a, b); err != nil || result != expected {
+				t.Errorf("`%v` && `%v`: expected result `%v`, got `%v` (error `%v`)", a, b, expected, result, err)
+			}
+		}
+	}
+}
+
+func TestDuration(t *testing.T) {
+	dro := NewDuration()
+	r := pgtype.Range[time.Duration]{Lower: 5 * time.Minute, LowerType: pgtype.Inclusive, Upper: 30 * time.Minute, UpperType: pgtype.Inclusive, Valid: true}
+
+	if size, err := dro.Size(r); err != nil || size != 25*time.Minute+1 {
+		t.Errorf("size `%v`: expected result `%v`, got `%v` (error `%v`)", r, 25*time.Minute+1, size, err)
+	}
+	for _, tt := range []struct {
+		elem     time.Duration
+		expected bool
+	}{
+		{elem: 5 * time.Minute, expected: true},
+		{elem: 30 * time.Minute, expected: true},
+		{elem: 30*time.Minute + 1, expected: false},
+		{elem: 5*time.Minute - 1, expected: false},
+	} {
+		if result, err := dro.ContainElement(r, tt.elem); err != nil || result != tt.expected {
+			t.Errorf("`%v` @> `%v`: expected result `%v`, got `%v` (error `%v`)", r, tt.elem, tt.expected, result, err)
+		}
+	}
+
+	other := pgtype.Range[time.Duration]{Lower: 30 * time.Minute, LowerType: pgtype.Exclusive, Upper: time.Hour, UpperType: pgtype.Exclusive, Valid: true}
+	if result, err := dro.Overlap(r, other); err != nil || result {
+		t.Errorf("`%v` && `%v`: expected result `false`, got `%v` (error `%v`)", r, other, result, err)
+	}
+	if result, err := dro.Adjacent(r, other); err != nil || !result {
+		t.Errorf("`%v` -|- `%v`: expected result `true`, got `%v` (error `%v`)", r, other, result, err)
+	}
+	if result := NewRange(dro, 5*time.Minute, 30*time.Minute).String(); result != "[5m0s,30m0s)" {
+		t.Errorf("string: expected result `[5m0s,30m0s)`, got `%v`", result)
+	}
+}
+
+func TestMergeWithTolerance(t *testing.T) {
+	start := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	meeting := func(from, to time.Duration) pgtype.Range[time.Time] {
+		return pgtype.Range[time.Time]{Lower: start.Add(from), LowerType: pgtype.Inclusive, Upper: start.Add(to), UpperType: pgtype.Exclusive, Valid: true}
+	}
+
+	tests := []struct {
+		rs          []pgtype.Range[time.Time]
+		maxGap      time.Duration
+		expected    []pgtype.Range[time.Time]
+		expectedErr bool
+	}{
+		{
+			// a 1 second gap is closed, a 10 second gap is preserved
+			rs: []pgtype.Range[time.Time]{
+				meeting(time.Hour+time.Second, 2*time.Hour),
+				meeting(0, time.Hour),
+				meeting(2*time.Hour+10*time.Second, 3*time.Hour),
+			},
+			maxGap: 5 * time.Second,
+			expected: []pgtype.Range[time.Time]{
+				meeting(0, 2*time.Hour),
+				meeting(2*time.Hour+10*time.Second, 3*time.Hour),
+			},
+		},
+		{
+			// the gap is exactly the maximum gap
+			rs:       []pgtype.Range[time.Time]{meeting(0, time.Hour), meeting(time.Hour+5*time.Second, 2*time.Hour)},
+			maxGap:   5 * time.Second,
+			expected: []pgtype.Range[time.Time]{meeting(0, 2*time.Hour)},
+		},
+		{
+			// a range within a gap that is closed
+			rs:       []pgtype.Range[time.Time]{meeting(0, time.Hour), meeting(time.Hour+2*time.Second, time.Hour+3*time.Second), meeting(time.Hour+4*time.Second, 2*time.Hour)},
+			maxGap:   2 * time.Second,
+			expected: []pgtype.Range[time.Time]{meeting(0, 2*time.Hour)},
+		},
+		{
+			rs:       []pgtype.Range[time.Time]{meeting(0, time.Hour), meeting(time.Hour+time.Second, 2*time.Hour)},
+			maxGap:   0,
+			expected: []pgtype.Range[time.Time]{meeting(0, time.Hour), meeting(time.Hour+time.Second, 2*time.Hour)},
+		},
+		{
+			rs:       nil,
+			maxGap:   time.Second,
+			expected: []pgtype.Range[time.Time]{},
+		},
+		{
+			rs:          []pgtype.Range[time.Time]{meeting(0, time.Hour)},
+			maxGap:      -time.Second,
+			expectedErr: true,
+		},
+		{
+			rs:          []pgtype.Range[time.Time]{{}},
+			maxGap:      time.Second,
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		result, err := tro.MergeWithTolerance(tt.rs, tt.maxGap)
+		if err == nil && tt.expectedErr {
+			t.Errorf("merge `%v` with tolerance `%v`: expected error, got none", tt.rs, tt.maxGap)
+		}
+		if err != nil && !tt.expectedErr {
+			t.Errorf("merge `%v` with tolerance `%v`: expected no error, got `%v`", tt.rs, tt.maxGap, err)
+		}
+		if err != nil || tt.expectedErr {
+			continue
+		}
+		if !reflect.DeepEqual(tt.expected, result) {
+			t.Errorf("merge `%v` with tolerance `%v`: expected result `%v`, got `%v`", tt.rs, tt.maxGap, tt.expected, result)
+		}
+	}
+
+	// the distance between these ranges doesn't fit an int64
+	far := []pgtype.Range[int64]{
+		{Lower: math.MinInt64, LowerType: pgtype.Inclusive, Upper: -10, UpperType: pgtype.Exclusive, Valid: true},
+		{Lower: math.MaxInt64 - 5, LowerType: pgtype.Inclusive, Upper: math.MaxInt64, UpperType: pgtype.Exclusive, Valid: true},
+	}
+	if result, err := iro.MergeWithTolerance(far, 5); err != nil || !reflect.DeepEqual(result, far) {
+		t.Errorf("merge `%v` with tolerance `5`: expected result `%v`, got `%v` (error `%v`)", far, far, result, err)
+	}
+
+	// without tolerance the result is the same as merging overlapping ranges, also for a gap of a
+	// single value of a continuous range
+	fro := NewFloat64()
+	point := []pgtype.Range[float64]{
+		{Lower: 0, LowerType: pgtype.Inclusive, Upper: 1, UpperType: pgtype.Exclusive, Valid: true},
+		{Lower: 1, LowerType: pgtype.Exclusive, Upper: 2, UpperType: pgtype.Inclusive, Valid: true},
+	}
+	expected, _ := fro.MergeOverlapping(point)
+	if result, err := fro.MergeWithTolerance(point, 0); err != nil || !reflect.DeepEqual(result, expected) {
+		t.Errorf("merge `%v` with tolerance `0`: expected result `%v`, got `%v` (error `%v`)", point, expected, result, err)
+	}
+	merged := []pgtype.Range[float64]{{Lower: 0, LowerType: pgtype.Inclusive, Upper: 2, UpperType: pgtype.Inclusive, Valid: true}}
+	if result, err := fro.MergeWithTolerance(point, 0.5); err != nil || !reflect.DeepEqual(result, merged) {
+		t.Errorf("merge `%v` with tolerance `0.5`: expected result `%v`, got `%v` (error `%v`)", point, merged, result, err)
+	}
+}
+
+func TestFirstOverlap(t *testing.T) {
+	tests := []struct {
+		rs              []pgtype.Range[int64]
+		expectedA       pgtype.Range[int64]
+		expectedB       pgtype.Range[int64]
+		expectedOverlap pgtype.Range[int64]
+		expectedFound   bool
+		expectedErr     bool
+	}{
+		{
+			rs: []pgtype.Range[int64]{
+				{Lower: 0, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+				{Lower: 20, LowerType: pgtype.Inclusive, Upper: 30, UpperType: pgtype.Exclusive, Valid: true},
+				{Lower: 5, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+				{Lower: 8, LowerType: pgtype.Inclusive, Upper: 12, UpperType: pgtype.Inclusive, Valid: true},
+			},
+			expectedA:       pgtype.Range[int64]{Lower: 5, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+			expectedB:       pgtype.Range[int64]{Lower: 8, LowerType: pgtype.Inclusive, Upper: 12, UpperType: pgtype.Inclusive, Valid: true},
+			expectedOverlap: pgtype.Range[int64]{Lower: 8, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+			expectedFound:   true,
+		},
+		{
+			rs: []pgtype.Range[int64]{
+				{Lower: 0, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+				{Lower: 5, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+				{LowerType: pgtype.Empty, UpperType: pgtype.Empty, Valid: true},
+				{Lower: 20, LowerType: pgtype.Inclusive, UpperType: pgtype.Unbounded, Valid: true},
+			},
+			expectedFound: false,
+		},
+		{
+			rs:          []pgtype.Range[int64]{{}},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		a, b, overlap, found, err := iro.FirstOverlap(tt.rs)
+		if err == nil && tt.expectedErr {
+			t.Errorf("first overlap `%v`: expected error, got none", tt.rs)
+		}
+		if err != nil && !tt.expectedErr {
+			t.Errorf("first overlap `%v`: expected no error, got `%v`", tt.rs, err)
+		}
+		if err != nil || tt.expectedErr {
+			continue
+		}
+		if found != tt.expectedFound {
+			t.Errorf("first overlap `%v`: expected found `%v`, got `%v`", tt.rs, tt.expectedFound, found)
+		}
+		if !found {
+			continue
+		}
+		if !reflect.DeepEqual(tt.expectedA, a) || !reflect.DeepEqual(tt.expectedB, b) || !reflect.DeepEqual(tt.expectedOverlap, overlap) {
+			t.Errorf("first overlap `%v`: expected result `%v`, `%v` and `%v`, got `%v`, `%v` and `%v`", tt.rs, tt.expectedA, tt.expectedB, tt.expectedOverlap, a, b, overlap)
+		}
+	}
+}
+
+func TestUnionAdjacent(t *testing.T) {
+	fro := NewFloat64()
+	boundTypes := []pgtype.BoundType{pgtype.Inclusive, pgtype.Exclusive}
+	for _, lowerType := range boundTypes {
+		for _, firstUpperType := range boundTypes {
+			for _, secondLowerType := range boundTypes {
+				for _, upperType := range boundTypes {
+					// the ranges share the value 5, they are contiguous if one of them includes it
+					contiguous := firstUpperType == pgtype.Inclusive || secondLowerType == pgtype.Inclusive
+
+					first := pgtype.Range[float64]{Lower: 1, LowerType: lowerType, Upper: 5, UpperType: firstUpperType, Valid: true}
+					second := pgtype.Range[float64]{Lower: 5, LowerType: secondLowerType, Upper: 10, UpperType: upperType, Valid: true}
+					expected := pgtype.Range[float64]{Lower: 1, LowerType: lowerType, Upper: 10, UpperType: upperType, Valid: true}
+					for _, operands := range [][2]pgtype.Range[float64]{{first, second}, {second, first}} {
+						result, err := fro.Union(operands[0], operands[1])
+						if !contiguous {
+							if err == nil {
+								t.Errorf("`%v` + `%v`: expected error, got none", operands[0], operands[1])
+							}
+							continue
+						}
+						if err != nil || !reflect.DeepEqual(expected, result) {
+							t.Errorf("`%v` + `%v`: expected result `%v`, got `%v` (error `%v`)", operands[0], operands[1], expected, result, err)
+						}
+					}
+
+					// discrete ranges are canonicalized to [,)
+					firstInt := pgtype.Range[int64]{Lower: 1, LowerType: lowerType, Upper: 5, UpperType: firstUpperType, Valid: true}
+					secondInt := pgtype.Range[int64]{Lower: 5, LowerType: secondLowerType, Upper: 10, UpperType: upperType, Valid: true}
+					expectedInt := pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true}
+					if lowerType == pgtype.Exclusive {
+						expectedInt.Lower = 2
+					}
+					if upperType == pgtype.Inclusive {
+						expectedInt.Upper = 11
+					}
+					for _, operands := range [][2]pgtype.Range[int64]{{firstInt, secondInt}, {secondInt, firstInt}} {
+						result, err := iro.Union(operands[0], operands[1])
+						if !contiguous {
+							if err == nil {
+								t.Errorf("`%v` + `%v`: expected error, got none", operands[0], operands[1])
+							}
+							continue
+						}
+						if err != nil || !reflect.DeepEqual(expectedInt, result) {
+							t.Errorf("`%v` + `%v`: expected result `%v`, got `%v` (error `%v`)", operands[0], operands[1], expectedInt, result, err)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestTouches(t *testing.T) {
+	r := pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true}
+	tests := []struct {
+		other       pgtype.Range[int64]
+		expected    bool
+		expectedErr bool
+	}{
+		{other: pgtype.Range[int64]{Lower: 3, LowerType: pgtype.Inclusive, Upper: 8, UpperType: pgtype.Exclusive, Valid: true}, expected: true},
+		{other: pgtype.Range[int64]{Lower: 5, LowerType: pgtype.Inclusive, Upper: 8, UpperType: pgtype.Exclusive, Valid: true}, expected: true},
+		{other: pgtype.Range[int64]{LowerType: pgtype.Unbounded, Upper: 0, UpperType: pgtype.Inclusive, Valid: true}, expected: true},
+		{other: pgtype.Range[int64]{Lower: 5, LowerType: pgtype.Exclusive, Upper: 8, UpperType: pgtype.Exclusive, Valid: true}, expected: false},
+		{other: pgtype.Range[int64]{LowerType: pgtype.Empty, UpperType: pgtype.Empty, Valid: true}, expected: false},
+		{other: pgtype.Range[int64]{Lower: 3, LowerType: pgtype.Inclusive, Upper: 3, UpperType: pgtype.Exclusive, Valid: true}, expected: false},
+		{other: pgtype.Range[int64]{Lower: 3, LowerType: pgtype.Inclusive, Upper: 8, UpperType: pgtype.Exclusive, Valid: false}, expectedErr: true},
+	}
+
+	for _, tt := range tests {
+		for _, operands := range [][2]pgtype.Range[int64]{{r, tt.other}, {tt.other, r}} {
+			result, err := iro.Touches(operands[0], operands[1])
+			if err == nil && tt.expectedErr {
+				t.Errorf("`%v` touches `%v`: expected error, got none", operands[0], operands[1])
+			}
+			if err != nil && !tt.expectedErr {
+				t.Errorf("`%v` touches `%v`: expected no error, got `%v`", operands[0], operands[1], err)
+			}
+			if err != nil || tt.expectedErr {
+				continue
+			}
+			if result != tt.expected {
+				t.Errorf("`%v` touches `%v`: expected result `%v`, got `%v`", operands[0], operands[1], tt.expected, result)
+			}
+		}
+	}
+}
+
+func TestWindows(t *testing.T) {
+	start := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	window := func(from, to time.Duration) pgtype.Range[time.Time] {
+		return pgtype.Range[time.Time]{Lower: start.Add(from), LowerType: pgtype.Inclusive, Upper: start.Add(to), UpperType: pgtype.Exclusive, Valid: true}
+	}
+
+	tests := []struct {
+		r           pgtype.Range[time.Time]
+		size        time.Duration
+		step        time.Duration
+		expected    []pgtype.Range[time.Time]
+		expectedErr bool
+	}{
+		{
+			// 1 hour windows every 30 minutes, the last window is clipped
+			r:    window(0, 2*time.Hour),
+			size: time.Hour,
+			step: 30 * time.Minute,
+			expected: []pgtype.Range[time.Time]{
+				window(0, time.Hour),
+				window(30*time.Minute, 90*time.Minute),
+				window(time.Hour, 2*time.Hour),
+				window(90*time.Minute, 2*time.Hour),
+			},
+		},
+		{
+			// the upper bound of the range is kept for a clipped window
+			r:    pgtype.Range[time.Time]{Lower: start, LowerType: pgtype.Exclusive, Upper: start.Add(90 * time.Minute), UpperType: pgtype.Inclusive, Valid: true},
+			size: time.Hour,
+			step: time.Hour,
+			expected: []pgtype.Range[time.Time]{
+				{Lower: start, LowerType: pgtype.Exclusive, Upper: start.Add(time.Hour), UpperType: pgtype.Exclusive, Valid: true},
+				{Lower: start.Add(time.Hour), LowerType: pgtype.Inclusive, Upper: start.Add(90 * time.Minute), UpperType: pgtype.Inclusive, Valid: true},
+			},
+		},
+		{
+			// gaps between the windows
+			r:    window(0, 2*time.Hour),
+			size: 15 * time.Minute,
+			step: time.Hour,
+			expected: []pgtype.Range[time.Time]{
+				window(0, 15*time.Minute),
+				window(time.Hour, 75*time.Minute),
+			},
+		},
+		{
+			r:           window(0, time.Hour),
+			size:        0,
+			step:        time.Minute,
+			expectedErr: true,
+		},
+		{
+			r:           window(0, time.Hour),
+			size:        time.Minute,
+			step:        -time.Minute,
+			expectedErr: true,
+		},
+		{
+			r:           pgtype.Range[time.Time]{Lower: start, LowerType: pgtype.Inclusive, UpperType: pgtype.Unbounded, Valid: true},
+			size:        time.Minute,
+			step:        time.Minute,
+			expectedErr: true,
+		},
+		{
+			r:           window(0, 0),
+			size:        time.Minute,
+			step:        time.Minute,
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		result, err := tro.Windows(tt.r, tt.size, tt.step)
+		if err == nil && tt.expectedErr {
+			t.Errorf("windows `%v` of `%v` every `%v`: expected error, got none", tt.r, tt.size, tt.step)
+		}
+		if err != nil && !tt.expectedErr {
+			t.Errorf("windows `%v` of `%v` every `%v`: expected no error, got `%v`", tt.r, tt.size, tt.step, err)
+		}
+		if err != nil || tt.expectedErr {
+			continue
+		}
+		if !reflect.DeepEqual(tt.expected, result) {
+			t.Errorf("windows `%v` of `%v` every `%v`: expected result `%v`, got `%v`", tt.r, tt.size, tt.step, tt.expected, result)
+		}
+	}
+
+	// the windows stop at the maximum value of the type
+	r := pgtype.Range[int64]{Lower: math.MaxInt64 - 5, LowerType: pgtype.Inclusive, Upper: math.MaxInt64, UpperType: pgtype.Inclusive, Valid: true}
+	if result, err := iro.Windows(r, 4, 4); err != nil || len(result) != 2 {
+		t.Errorf("windows `%v` of `4` every `4`: expected 2 windows, got `%v` (error `%v`)", r, result, err)
+	}
+}
+
+func TestZeroOperator(t *testing.T) {
+	var ro operator[int64, int64]
+	if !ro.IsZero() {
+		t.Errorf("expected zero operator")
+	}
+	if iro.IsZero() {
+		t.Errorf("expected operator created by NewInteger not to be zero")
+	}
+
+	// every method is called with valid arguments, the methods that return an error return
+	// ErrUninitialized and the other methods either work or panic with ErrUninitialized
+	r := pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true}
+	arguments := map[reflect.Type]reflect.Value{
+		reflect.TypeFor[pgtype.Range[int64]]():   reflect.ValueOf(r),
+		reflect.TypeFor[[]pgtype.Range[int64]](): reflect.ValueOf([]pgtype.Range[int64]{r, r}),
+		reflect.TypeFor[int64]():                 reflect.ValueOf(int64(1)),
+		reflect.TypeFor[[]int64]():               reflect.ValueOf([]int64{1, 2}),
+		reflect.TypeFor[int]():                   reflect.ValueOf(2),
+		reflect.TypeFor[float64]():               reflect.ValueOf(0.5),
+		reflect.TypeFor[pgtype.BoundType]():      reflect.ValueOf(pgtype.Inclusive),
+	}
+	errorType := reflect.TypeFor[error]()
+	value := reflect.ValueOf(ro)
+	for i := range value.NumMethod() {
+		method := value.Type().Method(i)
+		fn := value.Method(i)
+		if method.Type.NumOut() == 1 && method.Type.Out(0) == value.Type() {
+			// the methods that configure the operator
+			continue
+		}
+		args := make([]reflect.Value, fn.Type().NumIn())
+		for j := range args {
+			in := fn.Type().In(j)
+			if in.Kind() == reflect.Func {
+				args[j] = reflect.MakeFunc(in, func([]reflect.Value) []reflect.Value {
+					out := make([]reflect.Value, in.NumOut())
+					for k := range out {
+						out[k] = reflect.Zero(in.Out(k))
+					}
+					return out
+				})
+				continue
+			}
+			arg, ok := arguments[in]
+			if !ok {
+				t.Fatalf("%s: no argument of type %v", method.Name, in)
+			}
+			args[j] = arg
+		}
+
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					if err, ok := r.(error); !ok || !errors.Is(err, ErrUninitialized) {
+						t.Errorf("%s on zero operator: expected no panic or a panic with `%v`, got `%v`", method.Name, ErrUninitialized, r)
+					}
+				}
+			}()
+			var out []reflect.Value
+			if fn.Type().IsVariadic() {
+				out = fn.CallSlice(args)
+			} else {
+				out = fn.Call(args)
+			}
+			if last := fn.Type().NumOut() - 1; last >= 0 && fn.Type().Out(last) == errorType {
+				if err, _ := out[last].Interface().(error); !errors.Is(err, ErrUninitialized) {
+					t.Errorf("%s on zero operator: expected error `%v`, got `%v`", method.Name, ErrUninitialized, err)
+				}
+			}
+		}()
+	}
+}
+
+func TestConcurrentUse(t *testing.T) {
+	type result struct {
+		union     pgtype.Range[int64]
+		intersect pgtype.Range[int64]
+		contain   bool
+	}
+	operate := func(ro operator[int64, int64], first, second pgtype.Range[int64]) (result, error) {
+		var r result
+		var err error
+		if r.union, err = ro.Union(first, second); err != nil && !errors.Is(err, ErrNotContiguous) {
+			return r, err
+		}
+		if r.intersect, err = ro.Intersect(first, second); err != nil {
+			return r, err
+		}
+		r.contain, err = ro.Contain(first, second)
+		return r, err
+	}
+
+	random := rand.New(rand.NewPCG(1, 2))
+	operands := make([][2]pgtype.Range[int64], 1_000)
+	expected := make([]result, len(operands))
+	for i := range operands {
+		for j := range operands[i] {
+			lower := random.Int64N(100)
+			operands[i][j] = pgtype.Range[int64]{Lower: lower, Upper: lower + random.Int64N(20), Valid: true}
+			operands[i][j].SetBoundTypes(createBoundType(random.Int64N(3)), createBoundType(random.Int64N(3)))
+		}
+		var err error
+		if expected[i], err = operate(iro, operands[i][0], operands[i][1]); err != nil {
+			t.Fatalf("operate `%v` `%v`: expected no error, got `%v`", operands[i][0], operands[i][1], err)
+		}
+	}
+
+	// a single operator is shared by all goroutines, run with -race to detect shared state
+	for _, ro := range []operator[int64, int64]{iro, NewDebug(iro).Operator()} {
+		var wg sync.WaitGroup
+		for g := range 16 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range operands {
+					i = (i + g*len(operands)/16) % len(operands)
+					r, err := operate(ro, operands[i][0], operands[i][1])
+					if err != nil || r != expected[i] {
+						t.Errorf("operate `%v` `%v`: expected result `%v`, got `%v` (error `%v`)", operands[i][0], operands[i][1], expected[i], r, err)
+						return
+					}
+				}
+			}()
+		}
+		wg.Wait()
+	}
+}
+
+func TestBucketize(t *testing.T) {
+	buckets := []pgtype.Range[int64]{
+		{LowerType: pgtype.Unbounded, Upper: 0, UpperType: pgtype.Exclusive, Valid: true},
+		{Lower: 0, LowerType: pgtype.Inclusive, Upper: 10, UpperType: pgtype.Exclusive, Valid: true},
+		{Lower: 10, LowerType: pgtype.Inclusive, Upper: 20, UpperType: pgtype.Exclusive, Valid: true},
+		{Lower: 25, LowerType: pgtype.Inclusive, Upper: 25, UpperType: pgtype.Exclusive, Valid: true},
+		{Lower: 29, LowerType: pgtype.Exclusive, Upper: 40, UpperType: pgtype.Inclusive, Valid: true},
+	}
+	values := []int64{-5, 0, 3, 9, 10, 19, 20, 25, 29, 30, 40, 41, 7, 15, 15}
+	expected := []int{1, 4, 4, 0, 2}
+	if result, err := iro.Bucketize(buckets, values); err != nil || !slices.Equal(result, expected) {
+		t.Errorf("bucketize `%v` `%v`: expected result `%v`, got `%v` (error `%v`)", buckets, values, expected, result, err)
+	}
+
+	// the counts match containment of every value
+	random := rand.New(rand.NewPCG(1, 2))
+	values = make([]int64, 1_000)
+	for i := range values {
+		values[i] = random.Int64N(60) - 10
+	}
+	expected = make([]int, len(buckets))
+	for _, v := range values {
+		for i, b := range buckets {
+			if contain, _ := iro.ContainElement(b, v); contain {
+				expected[i]++
+			}
+		}
+	}
+	if result, err := iro.Bucketize(buckets, values); err != nil || !slices.Equal(result, expected) {
+		t.Errorf("bucketize `%v`: expected result `%v`, got `%v` (error `%v`)", buckets, expected, result, err)
+	}
+
+	for _, invalid := range [][]pgtype.Range[int64]{
+		// not sorted
+		{buckets[2], buckets[1]},
+		// overlapping
+		{buckets[1], {Lower: 9, LowerType: pgtype.Inclusive, Upper: 12, UpperType: pgtype.Exclusive, Valid: true}},
+		{buckets[1], {}},
+	} {
+		if _, err := iro.Bucketize(invalid, values); err == nil {
+			t.Errorf("bucketize `%v`: expected error, got none", invalid)
+		}
+	}
+
+	fro := NewFloat64()
+	floatBuckets := []pgtype.Range[float64]{{Lower: 0, LowerType: pgtype.Inclusive, Upper: 1, UpperType: pgtype.Exclusive, Valid: true}}
+	if _, err := fro.Bucketize(floatBuckets, []float64{0.5, math.NaN()}); !errors.Is(err, ErrNaN) {
+		t.Errorf("bucketize NaN: expected error `%v`, got `%v`", ErrNaN, err)
+	}
+}
+
+func TestExtremeBound(t *testing.T) {
+	type bound struct {
+		value     int64
+		boundType pgtype.BoundType
+	}
+	unbounded := bound{boundType: pgtype.Unbounded}
+	empty := bound{boundType: pgtype.Empty}
+	tests := []struct {
+		a, b     bound
+		lower    bool
+		outer    bool
+		expected bound
+	}{
+		// hull
+		{a: unbounded, b: bound{3, pgtype.Inclusive}, lower: true, outer: true, expected: unbounded},
+		{a: unbounded, b: bound{3, pgtype.Inclusive}, lower: false, outer: true, expected: unbounded},
+		{a: bound{1, pgtype.Exclusive}, b: bound{3, pgtype.Inclusive}, lower: true, outer: true, expected: bound{1, pgtype.Exclusive}},
+		{a: bound{1, pgtype.Exclusive}, b: bound{3, pgtype.Inclusive}, lower: false, outer: true, expected: bound{3, pgtype.Inclusive}},
+		{a: bound{3, pgtype.Exclusive}, b: bound{3, pgtype.Inclusive}, lower: true, outer: true, expected: bound{3, pgtype.Inclusive}},
+		{a: bound{3, pgtype.Exclusive}, b: bound{3, pgtype.Inclusive}, lower: false, outer: true, expected: bound{3, pgtype.Inclusive}},
+		{a: empty, b: bound{3, pgtype.Exclusive}, lower: true, outer: true, expected: bound{3, pgtype.Exclusive}},
+		{a: empty, b: unbounded, lower: false, outer: true, expected: unbounded},
+		{a: empty, b: empty, lower: true, outer: true, expected: empty},
+		// intersection
+		{a: unbounded, b: bound{3, pgtype.Inclusive}, lower: true, outer: false, expected: bound{3, pgtype.Inclusive}},
+		{a: unbounded, b: bound{3, pgtype.Inclusive}, lower: false, outer: false, expected: bound{3, pgtype.Inclusive}},
+		{a: bound{1, pgtype.Exclusive}, b: bound{3, pgtype.Inclusive}, lower: true, outer: false, expected: bound{3, pgtype.Inclusive}},
+		{a: bound{1, pgtype.Exclusive}, b: bound{3, pgtype.Inclusive}, lower: false, outer: false, expected: bound{1, pgtype.Exclusive}},
+		{a: bound{3, pgtype.Exclusive}, b: bound{3, pgtype.Inclusive}, lower: true, outer: false, expected: bound{3, pgtype.Exclusive}},
+		{a: bound{3, pgtype.Exclusive}, b: bound{3, pgtype.Inclusive}, lower: false, outer: false, expected: bound{3, pgtype.Exclusive}},
+		{a: empty, b: bound{3, pgtype.Exclusive}, lower: false, outer: false, expected: empty},
+	}
+
+	for _, tt := range tests {
+		// the order of the bounds doesn't matter
+		for _, operands := range [][2]bound{{tt.a, tt.b}, {tt.b, tt.a}} {
+			value, boundType := iro.extremeBound(operands[0].value, operands[0].boundType, operands[1].value, operands[1].boundType, tt.lower, tt.outer)
+			if result := (bound{value, boundType}); result != tt.expected {
+				t.Errorf("extreme bound `%v` `%v` (lower `%v`, outer `%v`): expected result `%v`, got `%v`", operands[0], operands[1], tt.lower, tt.outer, tt.expected, result)
+			}
+		}
+	}
+
+	// the hull of ranges with empty and unbounded ones
+	fro := NewFloat64()
+	rs := []pgtype.Range[float64]{
+		makeEmptyRange[float64](),
+		{LowerType: pgtype.Unbounded, Upper: 3, UpperType: pgtype.Exclusive, Valid: true},
+		{Lower: 5, LowerType: pgtype.Inclusive, Upper: 7, UpperType: pgtype.Exclusive, Valid: true},
+		{Lower: 6, LowerType: pgtype.Exclusive, Upper: 7, UpperType: pgtype.Inclusive, Valid: true},
+		makeEmptyRange[float64](),
+	}
+	expected := pgtype.Range[float64]{LowerType: pgtype.Unbounded, Upper: 7, UpperType: pgtype.Inclusive, Valid: true}
+	if result, err := fro.Cover(rs); err != nil || result != expected {
+		t.Errorf("cover `%v`: expected result `%v`, got `%v` (error `%v`)", rs, expected, result, err)
 	}
-	return expected, nil
 }
 
 func sort(lower, upper int64) (int64, int64) {
@@ -664,8 +4673,8 @@ func createBoundType(i int64) pgtype.BoundType {
 		pgtype.Inclusive,
 		pgtype.Exclusive,
 		pgtype.Unbounded,
-		// todo: what to do with pgtype.Empty
-		// pgtype.Empty,
+		// pgx can't encode a range with only an empty upper bound type, ranges with an empty
+		// bound type are tested in TestHalfEmpty instead
 	}
 	i %= int64(len(types))
 	if i < 0 {
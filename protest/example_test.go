@@ -0,0 +1,62 @@
+package protest_test
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	pro "github.com/munnik/pgx_range_operator"
+	"github.com/munnik/pgx_range_operator/protest"
+)
+
+// reporter prints the mismatches reported by the helpers, in a test the *testing.T is passed
+// instead
+type reporter struct {
+	testing.TB
+}
+
+func (reporter) Helper() {}
+
+func (reporter) Errorf(format string, args ...any) {
+	fmt.Printf(format+"\n", args...)
+}
+
+// This example verifies a custom operator against PostgreSQL, in a test t is passed instead of a
+// reporter. It needs PROTEST_DATABASE_URL to point to a database that can be used for testing, so
+// it is compiled but not run by go test.
+func ExampleCheckBoolOperator() {
+	pool, err := pgxpool.New(context.Background(), os.Getenv("PROTEST_DATABASE_URL"))
+	if err != nil {
+		fmt.Printf("could not connect to the database: %v\n", err)
+		return
+	}
+	defer pool.Close()
+
+	// float ranges are continuous, the size is measured in thousandths
+	fro := pro.NewWithAdd(
+		cmp.Compare[float64],
+		func(a, b float64) int64 { return int64((a - b) * 1000) },
+		func(a float64, d int64) float64 { return a + float64(d)/1000 },
+		false,
+	)
+
+	ranges := []pgtype.Range[float64]{
+		{Lower: 0.5, LowerType: pgtype.Inclusive, Upper: 1.5, UpperType: pgtype.Exclusive, Valid: true},
+		{Lower: 1.5, LowerType: pgtype.Inclusive, Upper: 2.5, UpperType: pgtype.Inclusive, Valid: true},
+		{Lower: 0.75, LowerType: pgtype.Exclusive, Upper: 1.25, UpperType: pgtype.Exclusive, Valid: true},
+		{LowerType: pgtype.Unbounded, Upper: 1, UpperType: pgtype.Inclusive, Valid: true},
+	}
+	t := reporter{}
+	for _, first := range ranges {
+		for _, second := range ranges {
+			protest.CheckBoolOperator(t, pool, "@>", "numrange", first, second, fro.Contain)
+			protest.CheckBoolOperator(t, pool, "&&", "numrange", first, second, fro.Overlap)
+			protest.CheckBoolOperator(t, pool, "-|-", "numrange", first, second, fro.Adjacent)
+			protest.CheckElementOperator(t, pool, "@>", "numrange", "numeric", first, second.Upper, fro.ContainElement)
+		}
+	}
+}
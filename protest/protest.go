@@ -0,0 +1,115 @@
+// Package protest contains helpers to verify range operators against PostgreSQL. Every helper runs
+// the equivalent SQL expression on the pool and compares the outcome with the outcome of the Go
+// function, both the result and whether an error occurred.
+package protest
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// CheckBoolOperator compares fn with the SQL expression `first sqlOperator second` where both
+// operands are ranges and the result is a boolean, for example the @> or && operator.
+func CheckBoolOperator[T any](t testing.TB, pool *pgxpool.Pool, sqlOperator, sqlRangeType string, first, second pgtype.Range[T], fn func(pgtype.Range[T], pgtype.Range[T]) (bool, error)) {
+	t.Helper()
+	expected, expectedErr := RetrieveExpected[bool](
+		pool,
+		fmt.Sprintf(`SELECT @first::%s %s @second::%s`, sqlRangeType, sqlOperator, sqlRangeType),
+		pgx.NamedArgs{"first": first, "second": second},
+	)
+	result, err := fn(first, second)
+	compare(t, fmt.Sprintf("`%v` %s `%v`", first, sqlOperator, second), expected, expectedErr, result, err)
+}
+
+// CheckElementOperator compares fn with the SQL expression `first sqlOperator second` where the
+// first operand is a range, the second operand an element and the result is a boolean.
+func CheckElementOperator[T any](t testing.TB, pool *pgxpool.Pool, sqlOperator, sqlRangeType, sqlElementType string, first pgtype.Range[T], second T, fn func(pgtype.Range[T], T) (bool, error)) {
+	t.Helper()
+	expected, expectedErr := RetrieveExpected[bool](
+		pool,
+		fmt.Sprintf(`SELECT @first::%s %s @second::%s`, sqlRangeType, sqlOperator, sqlElementType),
+		pgx.NamedArgs{"first": first, "second": second},
+	)
+	result, err := fn(first, second)
+	compare(t, fmt.Sprintf("`%v` %s `%v`", first, sqlOperator, second), expected, expectedErr, result, err)
+}
+
+// CheckElementRangeOperator compares fn with the SQL expression `first sqlOperator second` where
+// the first operand is an element, the second operand a range and the result is a boolean.
+func CheckElementRangeOperator[T any](t testing.TB, pool *pgxpool.Pool, sqlOperator, sqlElementType, sqlRangeType string, first T, second pgtype.Range[T], fn func(T, pgtype.Range[T]) (bool, error)) {
+	t.Helper()
+	expected, expectedErr := RetrieveExpected[bool](
+		pool,
+		fmt.Sprintf(`SELECT @first::%s %s @second::%s`, sqlElementType, sqlOperator, sqlRangeType),
+		pgx.NamedArgs{"first": first, "second": second},
+	)
+	result, err := fn(first, second)
+	compare(t, fmt.Sprintf("`%v` %s `%v`", first, sqlOperator, second), expected, expectedErr, result, err)
+}
+
+// CheckRangeOperator compares fn with the SQL expression `first sqlOperator second` where both
+// operands and the result are ranges, for example the + or * operator.
+func CheckRangeOperator[T any](t testing.TB, pool *pgxpool.Pool, sqlOperator, sqlRangeType string, first, second pgtype.Range[T], fn func(pgtype.Range[T], pgtype.Range[T]) (pgtype.Range[T], error)) {
+	t.Helper()
+	expected, expectedErr := RetrieveExpected[pgtype.Range[T]](
+		pool,
+		fmt.Sprintf(`SELECT @first::%s %s @second::%s`, sqlRangeType, sqlOperator, sqlRangeType),
+		pgx.NamedArgs{"first": first, "second": second},
+	)
+	result, err := fn(first, second)
+	compare(t, fmt.Sprintf("`%v` %s `%v`", first, sqlOperator, second), expected, expectedErr, result, err)
+}
+
+// CheckRangeFunction compares fn with the SQL expression `sqlFunction(first, second)` where both
+// arguments and the result are ranges, for example the range_merge function.
+func CheckRangeFunction[T any](t testing.TB, pool *pgxpool.Pool, sqlFunction, sqlRangeType string, first, second pgtype.Range[T], fn func(pgtype.Range[T], pgtype.Range[T]) (pgtype.Range[T], error)) {
+	t.Helper()
+	expected, expectedErr := RetrieveExpected[pgtype.Range[T]](
+		pool,
+		fmt.Sprintf(`SELECT %s(@first::%s, @second::%s)`, sqlFunction, sqlRangeType, sqlRangeType),
+		pgx.NamedArgs{"first": first, "second": second},
+	)
+	result, err := fn(first, second)
+	compare(t, fmt.Sprintf("%s(`%v`, `%v`)", sqlFunction, first, second), expected, expectedErr, result, err)
+}
+
+// RetrieveExpected runs the query, which must return exactly one row with one column, and returns
+// the value of that column.
+func RetrieveExpected[T any](pool *pgxpool.Pool, query string, args pgx.NamedArgs) (T, error) {
+	rows, err := pool.Query(
+		context.Background(),
+		query,
+		args,
+	)
+	if err != nil {
+		return *new(T), fmt.Errorf("excuting query failed: %v", err)
+	}
+	defer rows.Close()
+	expected, err := pgx.CollectExactlyOneRow(rows, pgx.RowTo[T])
+	if err != nil {
+		return *new(T), fmt.Errorf("collecting the row failed: %v", err)
+	}
+	return expected, nil
+}
+
+func compare[R any](t testing.TB, description string, expected R, expectedErr error, result R, err error) {
+	t.Helper()
+	if err == nil && expectedErr != nil {
+		t.Errorf("%s: expected error `%v`, got none", description, expectedErr)
+	}
+	if err != nil && expectedErr == nil {
+		t.Errorf("%s: expected no error, got `%v`", description, err)
+	}
+	if err != nil || expectedErr != nil {
+		return
+	}
+	if !reflect.DeepEqual(expected, result) {
+		t.Errorf("%s: expected result `%v`, got `%v`", description, expected, result)
+	}
+}
@@ -1,65 +1,141 @@
 package pro
 
 import (
+	"bytes"
+	"database/sql/driver"
+	"encoding"
+	"encoding/binary"
 	"fmt"
+	"io"
+	"iter"
+	"math/big"
+	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
-	"golang.org/x/exp/constraints"
 )
 
-type Range[T any, S constraints.Integer] struct {
+type Range[T any, S Number] struct {
 	r  pgtype.Range[T]
 	ro operator[T, S]
 }
 
-type RangeOption[T any, S constraints.Integer] func(*Range[T, S])
+type RangeOption[T any, S Number] func(*Range[T, S])
 
-func WithLowerType[T any, S constraints.Integer](t pgtype.BoundType) RangeOption[T, S] {
+func WithLowerType[T any, S Number](t pgtype.BoundType) RangeOption[T, S] {
 	return func(r *Range[T, S]) {
 		r.r.LowerType = t
 	}
 }
 
-func WithLowerInf[T any, S constraints.Integer]() RangeOption[T, S] {
+func WithLowerInf[T any, S Number]() RangeOption[T, S] {
 	return func(r *Range[T, S]) {
 		r.r.Lower = r.ro.zero
 		r.r.LowerType = pgtype.Unbounded
 	}
 }
 
-func WithUpperType[T any, S constraints.Integer](t pgtype.BoundType) RangeOption[T, S] {
+func WithUpperType[T any, S Number](t pgtype.BoundType) RangeOption[T, S] {
 	return func(r *Range[T, S]) {
 		r.r.UpperType = t
 	}
 }
 
-func WithUpperInf[T any, S constraints.Integer]() RangeOption[T, S] {
+func WithUpperInf[T any, S Number]() RangeOption[T, S] {
 	return func(r *Range[T, S]) {
 		r.r.Lower = r.ro.zero
 		r.r.LowerType = pgtype.Unbounded
 	}
 }
 
-func WithInvalid[T any, S constraints.Integer]() RangeOption[T, S] {
+// WithValidity sets if the range is valid. A range that is not valid represents a NULL value
+// in PostgreSQL and all operators return [ErrInvalidRange] for it. The bound types are kept, so
+// a range made valid again by a later option has the bounds set by the earlier options.
+func WithValidity[T any, S Number](valid bool) RangeOption[T, S] {
 	return func(r *Range[T, S]) {
-		r.r.Valid = false
+		r.r.Valid = valid
+	}
+}
+
+// WithInvalid marks the range as not valid, see WithValidity.
+func WithInvalid[T any, S Number]() RangeOption[T, S] {
+	return WithValidity[T, S](false)
+}
+
+func WithEmpty[T any, S Number]() RangeOption[T, S] {
+	return func(r *Range[T, S]) {
+		r.r = makeEmptyRange[T]()
+	}
+}
+
+// WithOperator replaces the operator of the range, for example to use NewTimeWithStep for a
+// TimeRange.
+func WithOperator[T any, S Number](ro operator[T, S]) RangeOption[T, S] {
+	return func(r *Range[T, S]) {
+		r.ro = ro
 	}
 }
 
 type TimeRange = Range[time.Time, time.Duration]
 type IntegerRange = Range[int, int]
+type RealRange = Range[float32, float32]
+type DateRange = Range[time.Time, int]
 
-func NewIntegerRange(lower, upper int, opts ...RangeOption[int, int]) IntegerRange {
-	result := &IntegerRange{
-		r: pgtype.Range[int]{
+// RegisterRangeTypes registers the range wrappers as the default PostgreSQL range type for their
+// Go type. This is needed for pgx when the type of a value can not be determined from the query.
+func RegisterRangeTypes(m *pgtype.Map) {
+	for _, t := range []struct {
+		value any
+		name  string
+	}{
+		{value: IntegerRange{}, name: "int8range"},
+		{value: &IntegerRange{}, name: "int8range"},
+		{value: TimeRange{}, name: "tstzrange"},
+		{value: &TimeRange{}, name: "tstzrange"},
+		{value: DateRange{}, name: "daterange"},
+		{value: &DateRange{}, name: "daterange"},
+		{value: DecimalRange{}, name: "numrange"},
+		{value: &DecimalRange{}, name: "numrange"},
+	} {
+		m.RegisterDefaultPgType(t.value, t.name)
+	}
+}
+
+// operatorFactories creates the operator for a built-in PostgreSQL range type
+var operatorFactories = map[string]func() any{
+	"int4range": func() any { return NewInt32() },
+	"int8range": func() any { return NewInteger() },
+	"numrange":  func() any { return NewDecimal() },
+	"tsrange":   func() any { return NewTime() },
+	"tstzrange": func() any { return NewTime() },
+	"daterange": func() any { return NewDate() },
+}
+
+// OperatorForType returns the operator for the PostgreSQL range type with the given name, for
+// tooling that discovers the type of a column at runtime. The result has to be type asserted, for
+// example the operator for int8range is the one returned by NewInteger.
+func OperatorForType(name string) (any, error) {
+	factory, ok := operatorFactories[name]
+	if !ok {
+		return nil, fmt.Errorf("no operator for range type %q", name)
+	}
+	return factory(), nil
+}
+
+// NewRange creates a range wrapper around a custom operator, for example one created with New.
+// The range includes the lower bound and excludes the upper bound unless changed by the options.
+// The options are applied in order, so a later option overrides an earlier one.
+func NewRange[T any, S Number](ro operator[T, S], lower, upper T, opts ...RangeOption[T, S]) Range[T, S] {
+	result := &Range[T, S]{
+		r: pgtype.Range[T]{
 			Lower:     lower,
 			LowerType: pgtype.Inclusive,
 			Upper:     upper,
 			UpperType: pgtype.Exclusive,
 			Valid:     true,
 		},
-		ro: NewInteger(),
+		ro: ro,
 	}
 	for _, opt := range opts {
 		opt(result)
@@ -67,21 +143,112 @@ func NewIntegerRange(lower, upper int, opts ...RangeOption[int, int]) IntegerRan
 	return *result
 }
 
+func NewIntegerRange(lower, upper int, opts ...RangeOption[int, int]) IntegerRange {
+	return NewRange(NewInteger(), lower, upper, opts...)
+}
+
 func NewTimeRange(lower, upper time.Time, opts ...RangeOption[time.Time, time.Duration]) TimeRange {
-	result := &TimeRange{
-		r: pgtype.Range[time.Time]{
-			Lower:     lower,
-			LowerType: pgtype.Inclusive,
-			Upper:     upper,
-			UpperType: pgtype.Exclusive,
-			Valid:     true,
-		},
-		ro: NewTime(),
+	return NewRange(NewTime(), lower, upper, opts...)
+}
+
+// NewDateRange creates a date range, the bounds are truncated to midnight UTC.
+func NewDateRange(lower, upper time.Time, opts ...RangeOption[time.Time, int]) DateRange {
+	return NewRange(NewDate(), truncateToDate(lower), truncateToDate(upper), opts...)
+}
+
+func NewRealRange(lower, upper float32, opts ...RangeOption[float32, float32]) RealRange {
+	return NewRange(NewFloat32(), lower, upper, opts...)
+}
+
+// NewIntegerRangeChecked is like NewIntegerRange but returns an error when the resulting range is
+// malformed, for example when the lower bound is greater than the upper bound.
+func NewIntegerRangeChecked(lower, upper int, opts ...RangeOption[int, int]) (IntegerRange, error) {
+	result := NewIntegerRange(lower, upper, opts...)
+	if err := result.Validate(); err != nil {
+		return IntegerRange{}, err
 	}
-	for _, opt := range opts {
-		opt(result)
+	return result, nil
+}
+
+// NewTimeRangeChecked is like NewTimeRange but returns an error when the resulting range is
+// malformed, for example when the lower bound is after the upper bound.
+func NewTimeRangeChecked(lower, upper time.Time, opts ...RangeOption[time.Time, time.Duration]) (TimeRange, error) {
+	result := NewTimeRange(lower, upper, opts...)
+	if err := result.Validate(); err != nil {
+		return TimeRange{}, err
 	}
-	return *result
+	return result, nil
+}
+
+// TimeRangeFromWindow creates the time range [start,end) from a window given by its start and
+// end, it returns an error when end is before start.
+func TimeRangeFromWindow(start, end time.Time) (TimeRange, error) {
+	return NewTimeRangeChecked(start, end)
+}
+
+// RowToRange returns a function that scans a row with a single range column, for use with
+// pgx.CollectRows and the other pgx functions that accept a pgx.RowToFunc.
+func RowToRange[T any, S Number](ro operator[T, S]) pgx.RowToFunc[Range[T, S]] {
+	return func(row pgx.CollectableRow) (Range[T, S], error) {
+		result := Range[T, S]{ro: ro}
+		err := row.Scan(&result)
+		return result, err
+	}
+}
+
+// CopyFromRanges returns a pgx.CopyFromSource over the ranges for a table with a single range
+// column, so a slice of ranges can be loaded with CopyFrom instead of one insert per range.
+func CopyFromRanges[T any, S Number](rs []Range[T, S]) pgx.CopyFromSource {
+	return pgx.CopyFromSlice(len(rs), func(i int) ([]any, error) {
+		return []any{rs[i]}, nil
+	})
+}
+
+// RowToIntegerRange scans a row with a single range column into an IntegerRange.
+func RowToIntegerRange(row pgx.CollectableRow) (IntegerRange, error) {
+	return RowToRange(NewInteger())(row)
+}
+
+// RowToTimeRange scans a row with a single range column into a TimeRange.
+func RowToTimeRange(row pgx.CollectableRow) (TimeRange, error) {
+	return RowToRange(NewTime())(row)
+}
+
+// NewSingletonIntegerRange returns the integer range [v,v] that contains only v.
+func NewSingletonIntegerRange(v int) IntegerRange {
+	return NewIntegerRange(v, v, WithUpperType[int, int](pgtype.Inclusive))
+}
+
+// NewEmptyIntegerRange returns an empty integer range.
+func NewEmptyIntegerRange() IntegerRange {
+	return NewIntegerRange(0, 0, WithEmpty[int, int]())
+}
+
+// NewEmptyTimeRange returns an empty time range.
+func NewEmptyTimeRange() TimeRange {
+	return NewTimeRange(time.Time{}, time.Time{}, WithEmpty[time.Time, time.Duration]())
+}
+
+// Clone returns an independent copy of the range, including its operator. Bound values
+// are copied by value, so for reference types like pointers or slices the copy is shallow.
+func (r Range[T, S]) Clone() Range[T, S] {
+	return Range[T, S]{
+		r:  r.r,
+		ro: r.ro,
+	}
+}
+
+// FromPgtypeRange wraps a pgtype.Range using the given operator.
+func FromPgtypeRange[T any, S Number](r pgtype.Range[T], ro operator[T, S]) Range[T, S] {
+	return Range[T, S]{
+		r:  r,
+		ro: ro,
+	}
+}
+
+// Unwrap returns a copy of the wrapped pgtype.Range.
+func (r Range[T, S]) Unwrap() pgtype.Range[T] {
+	return r.r
 }
 
 // Implement RangeValuer interface
@@ -97,9 +264,18 @@ func (r Range[T, S]) Bounds() (lower, upper any) {
 	return r.r.Bounds()
 }
 
+// TypedBounds is like Bounds but returns the bound values as T, together with the bound types.
+// The value of an unbounded or empty side, and both values of an invalid range, are the zero T.
+func (r Range[T, S]) TypedBounds() (lower, upper T, lowerType, upperType pgtype.BoundType) {
+	lower, lowerType, _ = r.LowerBound()
+	upper, upperType, _ = r.UpperBound()
+	return lower, upper, lowerType, upperType
+}
+
 // Implement RangeScanner interface
 func (r *Range[T, S]) ScanNull() error {
-	*r = Range[T, S]{}
+	// keep the operator, so the range can still be used after scanning a NULL
+	r.r = pgtype.Range[T]{}
 	return nil
 }
 
@@ -111,25 +287,465 @@ func (r *Range[T, S]) SetBoundTypes(lower, upper pgtype.BoundType) error {
 	return r.r.SetBoundTypes(lower, upper)
 }
 
+// Implement Stringer interface
+func (r Range[T, S]) String() string {
+	if r.IsNull() {
+		return "NULL"
+	}
+	text, err := r.formatText()
+	if err != nil {
+		return fmt.Sprint(r.r)
+	}
+	return text
+}
+
+// Implement driver.Valuer interface
+func (r Range[T, S]) Value() (driver.Value, error) {
+	if r.IsNull() {
+		return nil, nil
+	}
+	if r.ro.format != nil && !hasRangeType[T]() {
+		// pgx has no type for T, like decimal.Decimal, so the operator formats the bounds
+		return r.formatText()
+	}
+	return r.encodeText()
+}
+
+// Implement sql.Scanner interface
+func (r *Range[T, S]) Scan(src any) error {
+	// only the range is scanned, the operator is kept
+	ro := r.ro
+	defer func() { r.ro = ro }()
+
+	var text []byte
+	switch src := src.(type) {
+	case nil:
+		return r.ScanNull()
+	case string:
+		text = []byte(src)
+	case []byte:
+		text = src
+	default:
+		return fmt.Errorf("cannot scan %T into a range", src)
+	}
+
+	m := pgtype.NewMap()
+	t, err := rangeTypeFor[T](m)
+	if err != nil {
+		if r.ro.parse != nil {
+			// pgx has no type for T, like decimal.Decimal, so the operator parses the bounds
+			return r.UnmarshalText(text)
+		}
+		return err
+	}
+	return m.Scan(t.OID, pgtype.TextFormatCode, text, r)
+}
+
+// Implement encoding.TextMarshaler interface, the range is encoded in the PostgreSQL text
+// format like String. A range that is not valid is encoded as empty text.
+func (r Range[T, S]) MarshalText() ([]byte, error) {
+	if r.IsNull() {
+		return []byte{}, nil
+	}
+	text, err := r.formatText()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(text), nil
+}
+
+// Implement encoding.TextUnmarshaler interface, empty text is decoded as a range that is not
+// valid. The bounds are parsed with the operator of the receiver, see WithFormat.
+func (r *Range[T, S]) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		return r.ScanNull()
+	}
+	if r.ro.parse == nil {
+		return r.Scan(text)
+	}
+
+	lower, upper, lowerType, upperType, err := parseRangeText(string(text))
+	if err != nil {
+		return err
+	}
+	if lowerType == pgtype.Empty {
+		r.r = makeEmptyRange[T]()
+		return nil
+	}
+	result := pgtype.Range[T]{LowerType: lowerType, UpperType: upperType, Valid: true}
+	if lowerType != pgtype.Unbounded {
+		if result.Lower, err = r.ro.parse(lower); err != nil {
+			return fmt.Errorf("lower bound: %w", err)
+		}
+	}
+	if upperType != pgtype.Unbounded {
+		if result.Upper, err = r.ro.parse(upper); err != nil {
+			return fmt.Errorf("upper bound: %w", err)
+		}
+	}
+	r.r = result
+	return nil
+}
+
+// binaryBoundTypes are the bound types in the order of their code in the binary format
+var binaryBoundTypes = [...]pgtype.BoundType{pgtype.Inclusive, pgtype.Exclusive, pgtype.Unbounded, pgtype.Empty}
+
+// Implement encoding.BinaryMarshaler interface. The first byte holds the validity in the lowest
+// bit followed by two bits for the lower and two bits for the upper bound type, the bounds follow
+// for the sides that are not unbounded or empty. Bounds of a fixed-size type, like int64, are
+// written big endian, int and uint as 64 bits. Other bounds are written by the binary encoder of
+// the operator, see WithBinary, or by their own MarshalBinary method, like time.Time, with the
+// length as a uvarint in front.
+func (r Range[T, S]) MarshalBinary() ([]byte, error) {
+	if r.IsNull() {
+		return []byte{0}, nil
+	}
+	lowerCode, ok := binaryBoundCode(r.r.LowerType)
+	if !ok {
+		return nil, fmt.Errorf("lower bound type %q: %w", r.r.LowerType, ErrMalformedRange)
+	}
+	upperCode, ok := binaryBoundCode(r.r.UpperType)
+	if !ok {
+		return nil, fmt.Errorf("upper bound type %q: %w", r.r.UpperType, ErrMalformedRange)
+	}
+	var buf bytes.Buffer
+	buf.WriteByte(1 | lowerCode<<1 | upperCode<<3)
+	if r.r.LowerType == pgtype.Inclusive || r.r.LowerType == pgtype.Exclusive {
+		if err := r.writeBinaryBound(&buf, r.r.Lower); err != nil {
+			return nil, fmt.Errorf("lower bound: %w", err)
+		}
+	}
+	if r.r.UpperType == pgtype.Inclusive || r.r.UpperType == pgtype.Exclusive {
+		if err := r.writeBinaryBound(&buf, r.r.Upper); err != nil {
+			return nil, fmt.Errorf("upper bound: %w", err)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// Implement encoding.BinaryUnmarshaler interface, the inverse of MarshalBinary. The bounds are
+// decoded with the operator of the receiver, see WithBinary.
+func (r *Range[T, S]) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return fmt.Errorf("binary range: %w", io.ErrUnexpectedEOF)
+	}
+	flags := data[0]
+	if flags&1 == 0 {
+		if len(data) != 1 || flags != 0 {
+			return fmt.Errorf("binary range: %w", ErrMalformedRange)
+		}
+		return r.ScanNull()
+	}
+	if flags>>5 != 0 {
+		return fmt.Errorf("binary range: %w", ErrMalformedRange)
+	}
+	result := pgtype.Range[T]{
+		LowerType: binaryBoundTypes[flags>>1&3],
+		UpperType: binaryBoundTypes[flags>>3&3],
+		Valid:     true,
+	}
+	reader := bytes.NewReader(data[1:])
+	var err error
+	if result.LowerType == pgtype.Inclusive || result.LowerType == pgtype.Exclusive {
+		if result.Lower, err = r.readBinaryBound(reader); err != nil {
+			return fmt.Errorf("lower bound: %w", err)
+		}
+	}
+	if result.UpperType == pgtype.Inclusive || result.UpperType == pgtype.Exclusive {
+		if result.Upper, err = r.readBinaryBound(reader); err != nil {
+			return fmt.Errorf("upper bound: %w", err)
+		}
+	}
+	if reader.Len() != 0 {
+		return fmt.Errorf("binary range: %d trailing bytes: %w", reader.Len(), ErrMalformedRange)
+	}
+	r.r = result
+	return nil
+}
+
+func binaryBoundCode(t pgtype.BoundType) (byte, bool) {
+	for i, bt := range binaryBoundTypes {
+		if bt == t {
+			return byte(i), true
+		}
+	}
+	return 0, false
+}
+
+// writeBinaryBound writes a single bound in the binary format of MarshalBinary
+func (r Range[T, S]) writeBinaryBound(buf *bytes.Buffer, v T) error {
+	var data []byte
+	var err error
+	if r.ro.marshalBinary != nil {
+		data, err = r.ro.marshalBinary(v)
+	} else {
+		switch b := any(v).(type) {
+		case int:
+			return binary.Write(buf, binary.BigEndian, int64(b))
+		case uint:
+			return binary.Write(buf, binary.BigEndian, uint64(b))
+		case encoding.BinaryMarshaler:
+			data, err = b.MarshalBinary()
+		default:
+			if binary.Size(v) < 0 {
+				return fmt.Errorf("no binary encoding for %T", v)
+			}
+			return binary.Write(buf, binary.BigEndian, v)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	buf.Write(binary.AppendUvarint(nil, uint64(len(data))))
+	buf.Write(data)
+	return nil
+}
+
+// readBinaryBound reads a single bound written by writeBinaryBound
+func (r Range[T, S]) readBinaryBound(reader *bytes.Reader) (T, error) {
+	var v T
+	if r.ro.unmarshalBinary == nil {
+		switch p := any(&v).(type) {
+		case *int:
+			var i int64
+			err := binary.Read(reader, binary.BigEndian, &i)
+			*p = int(i)
+			return v, err
+		case *uint:
+			var i uint64
+			err := binary.Read(reader, binary.BigEndian, &i)
+			*p = uint(i)
+			return v, err
+		case encoding.BinaryUnmarshaler:
+		default:
+			if binary.Size(v) < 0 {
+				return v, fmt.Errorf("no binary decoding for %T", v)
+			}
+			return v, binary.Read(reader, binary.BigEndian, &v)
+		}
+	}
+
+	n, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return v, err
+	}
+	if n > uint64(reader.Len()) {
+		return v, io.ErrUnexpectedEOF
+	}
+	data := make([]byte, n)
+	if _, err := io.ReadFull(reader, data); err != nil {
+		return v, err
+	}
+	if r.ro.unmarshalBinary != nil {
+		return r.ro.unmarshalBinary(data)
+	}
+	err = any(&v).(encoding.BinaryUnmarshaler).UnmarshalBinary(data)
+	return v, err
+}
+
+// formatText encodes the range in the PostgreSQL text format using the format function of the
+// operator, without a format function the range is encoded like encodeText
+func (r Range[T, S]) formatText() (string, error) {
+	if r.ro.format == nil {
+		return r.encodeText()
+	}
+	if !r.r.Valid {
+		return "", ErrInvalidRange
+	}
+	if r.r.LowerType == pgtype.Empty || r.r.UpperType == pgtype.Empty {
+		return "empty", nil
+	}
+
+	var b strings.Builder
+	if r.r.LowerType == pgtype.Inclusive {
+		b.WriteByte('[')
+	} else {
+		b.WriteByte('(')
+	}
+	if r.r.LowerType != pgtype.Unbounded {
+		writeRangeBound(&b, r.ro.format(r.r.Lower))
+	}
+	b.WriteByte(',')
+	if r.r.UpperType != pgtype.Unbounded {
+		writeRangeBound(&b, r.ro.format(r.r.Upper))
+	}
+	if r.r.UpperType == pgtype.Inclusive {
+		b.WriteByte(']')
+	} else {
+		b.WriteByte(')')
+	}
+	return b.String(), nil
+}
+
+// writeRangeBound writes a bound of a range literal, quoted when needed like PostgreSQL does
+func writeRangeBound(b *strings.Builder, text string) {
+	if text != "" && !strings.ContainsAny(text, "\"\\()[], \t\n\r\v\f") {
+		b.WriteString(text)
+		return
+	}
+	b.WriteByte('"')
+	for _, c := range text {
+		if c == '"' || c == '\\' {
+			b.WriteRune(c)
+		}
+		b.WriteRune(c)
+	}
+	b.WriteByte('"')
+}
+
+// parseRangeText splits a range literal in the PostgreSQL text format into its bounds, both
+// bound types are empty for the empty range
+func parseRangeText(text string) (lower, upper string, lowerType, upperType pgtype.BoundType, err error) {
+	text = strings.TrimSpace(text)
+	if strings.EqualFold(text, "empty") {
+		return "", "", pgtype.Empty, pgtype.Empty, nil
+	}
+	if len(text) < 3 {
+		return "", "", 0, 0, fmt.Errorf("range literal %q: %w", text, ErrMalformedRange)
+	}
+
+	switch text[0] {
+	case '[':
+		lowerType = pgtype.Inclusive
+	case '(':
+		lowerType = pgtype.Exclusive
+	default:
+		return "", "", 0, 0, fmt.Errorf("range literal %q: %w", text, ErrMalformedRange)
+	}
+	lower, rest, lowerUnbounded, ok := readRangeBound(text[1:], ",")
+	if !ok {
+		return "", "", 0, 0, fmt.Errorf("range literal %q: %w", text, ErrMalformedRange)
+	}
+	upper, rest, upperUnbounded, ok := readRangeBound(rest[1:], "])")
+	if !ok || len(rest) != 1 {
+		return "", "", 0, 0, fmt.Errorf("range literal %q: %w", text, ErrMalformedRange)
+	}
+	upperType = pgtype.Exclusive
+	if rest[0] == ']' {
+		upperType = pgtype.Inclusive
+	}
+	if lowerUnbounded {
+		lowerType = pgtype.Unbounded
+	}
+	if upperUnbounded {
+		upperType = pgtype.Unbounded
+	}
+	return lower, upper, lowerType, upperType, nil
+}
+
+// readRangeBound reads a bound of a range literal up to the first unquoted delimiter, the rest
+// starts at that delimiter
+func readRangeBound(text string, delimiters string) (bound, rest string, unbounded, ok bool) {
+	var b strings.Builder
+	quoted, escaped, empty := false, false, true
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case escaped:
+			b.WriteByte(c)
+			escaped = false
+		case c == '\\':
+			escaped = true
+		case c == '"' && quoted && i+1 < len(text) && text[i+1] == '"':
+			b.WriteByte(c)
+			i++
+		case c == '"':
+			quoted = !quoted
+			empty = false
+		case !quoted && strings.IndexByte(delimiters, c) >= 0:
+			return b.String(), text[i:], empty && b.Len() == 0, true
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", "", false, false
+}
+
+// encodeText encodes the range in the PostgreSQL text format, e.g. [1,5)
+func (r Range[T, S]) encodeText() (string, error) {
+	m := pgtype.NewMap()
+	t, err := rangeTypeFor[T](m)
+	if err != nil {
+		return "", err
+	}
+	buf, err := m.Encode(t.OID, pgtype.TextFormatCode, r, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
+
+// hasRangeType reports if there is a built-in PostgreSQL range type with T as element type
+func hasRangeType[T any]() bool {
+	_, err := rangeTypeFor[T](pgtype.NewMap())
+	return err == nil
+}
+
+// rangeTypeFor returns the built-in PostgreSQL range type with T as element type
+func rangeTypeFor[T any](m *pgtype.Map) (*pgtype.Type, error) {
+	elementType, ok := m.TypeForValue(*new(T))
+	if !ok {
+		return nil, fmt.Errorf("no PostgreSQL type found for %T", *new(T))
+	}
+	for _, name := range []string{"int4range", "int8range", "numrange", "tsrange", "tstzrange", "daterange"} {
+		t, ok := m.TypeForName(name)
+		if !ok {
+			continue
+		}
+		if c, ok := t.Codec.(*pgtype.RangeCodec); ok && c.ElementType.OID == elementType.OID {
+			return t, nil
+		}
+	}
+	return nil, fmt.Errorf("no PostgreSQL range type found for %T", *new(T))
+}
+
 // Implement operators and functions
 func (r Range[T, S]) Empty() (bool, error) {
 	return r.ro.Empty(r.r)
 }
 
+// IsEmpty is like Empty but returns false instead of an error, for example for a range that is
+// not valid.
+func (r Range[T, S]) IsEmpty() bool {
+	e, err := r.Empty()
+	return err == nil && e
+}
+
 func (r Range[T, S]) Lower() (T, error) {
 	if r.LowerInf() {
-		return r.ro.zero, fmt.Errorf("lower bound is infinite")
+		return r.ro.zero, fmt.Errorf("lower bound: %w", ErrUnboundedRange)
 	}
 	if r.r.LowerType == pgtype.Empty {
-		return r.ro.zero, fmt.Errorf("lower bound is empty")
+		return r.ro.zero, fmt.Errorf("lower bound: %w", ErrEmptyUndefined)
 	}
 	return r.r.Lower, nil
 }
 
+// LowerBound returns the lower bound with its type, ok is false when the range is not valid or the
+// bound is unbounded or empty.
+func (r Range[T, S]) LowerBound() (value T, boundType pgtype.BoundType, ok bool) {
+	boundType = r.r.LowerType
+	if !r.r.Valid || boundType == pgtype.Unbounded || boundType == pgtype.Empty {
+		return r.ro.zero, boundType, false
+	}
+	return r.r.Lower, boundType, true
+}
+
 func (r Range[T, S]) LowerInf() bool {
 	return r.ro.LowerInf(r.r)
 }
 
+// Does the range cover every value, that is, is it valid and unbounded on both sides?
+func (r Range[T, S]) IsFull() bool {
+	return r.ro.IsFull(r.r)
+}
+
+// Is the lower bound inclusive?
+// PostgreSQL equivalent: lower_inc(anyrange) → boolean
+func (r Range[T, S]) LowerInc() bool {
+	return r.ro.LowerInc(r.r)
+}
+
 func (r *Range[T, S]) SetLower(v T) *Range[T, S] {
 	r.r.Lower = v
 	return r
@@ -153,18 +769,34 @@ func (r *Range[T, S]) SetLowerInf() *Range[T, S] {
 
 func (r Range[T, S]) Upper() (T, error) {
 	if r.UpperInf() {
-		return r.ro.zero, fmt.Errorf("upper bound is infinite")
+		return r.ro.zero, fmt.Errorf("upper bound: %w", ErrUnboundedRange)
 	}
 	if r.r.UpperType == pgtype.Empty {
-		return r.ro.zero, fmt.Errorf("upper bound is empty")
+		return r.ro.zero, fmt.Errorf("upper bound: %w", ErrEmptyUndefined)
 	}
 	return r.r.Upper, nil
 }
 
+// UpperBound returns the upper bound with its type, ok is false when the range is not valid or the
+// bound is unbounded or empty.
+func (r Range[T, S]) UpperBound() (value T, boundType pgtype.BoundType, ok bool) {
+	boundType = r.r.UpperType
+	if !r.r.Valid || boundType == pgtype.Unbounded || boundType == pgtype.Empty {
+		return r.ro.zero, boundType, false
+	}
+	return r.r.Upper, boundType, true
+}
+
 func (r Range[T, S]) UpperInf() bool {
 	return r.ro.UpperInf(r.r)
 }
 
+// Is the upper bound inclusive?
+// PostgreSQL equivalent: upper_inc(anyrange) → boolean
+func (r Range[T, S]) UpperInc() bool {
+	return r.ro.UpperInc(r.r)
+}
+
 func (r *Range[T, S]) SetUpper(v T) *Range[T, S] {
 	r.r.Upper = v
 	return r
@@ -186,6 +818,30 @@ func (r *Range[T, S]) SetUpperInf() *Range[T, S] {
 	return r
 }
 
+// Normalize makes the range consistent with its bound types. A range with a single empty bound
+// type is contradictory and is marked as not valid. A range with two empty bound types is the
+// empty range, every other range is marked as valid. The values of empty and unbounded bounds
+// are reset to the zero value.
+func (r *Range[T, S]) Normalize() *Range[T, S] {
+	lowerEmpty, upperEmpty := r.r.LowerType == pgtype.Empty, r.r.UpperType == pgtype.Empty
+	switch {
+	case lowerEmpty && upperEmpty:
+		r.r = makeEmptyRange[T]()
+		return r
+	case lowerEmpty || upperEmpty:
+		r.r.Valid = false
+	default:
+		r.r.Valid = true
+	}
+	if r.r.LowerType == pgtype.Unbounded {
+		r.r.Lower = r.ro.zero
+	}
+	if r.r.UpperType == pgtype.Unbounded {
+		r.r.Upper = r.ro.zero
+	}
+	return r
+}
+
 // Is the first range equal to the second?
 // PostgreSQL equivalent: anyrange = anyrange → boolean
 func (r Range[T, S]) Equal(other Range[T, S]) (bool, error) {
@@ -222,18 +878,99 @@ func (r Range[T, S]) Contain(other Range[T, S]) (bool, error) {
 	return r.ro.Contain(r.r, other.r)
 }
 
+// Does the range properly contain the other range, that is, contain it without being equal to it?
+func (r Range[T, S]) ProperContain(other Range[T, S]) (bool, error) {
+	return r.ro.ProperContain(r.r, other.r)
+}
+
 // Does the range contain the element?
 // PostgreSQL equivalent: anyrange @> anyelement → boolean
 func (r Range[T, S]) ContainElement(elem T) (bool, error) {
 	return r.ro.ContainElement(r.r, elem)
 }
 
+// Does the range contain all the other ranges?
+func (r Range[T, S]) ContainsAllRanges(others []Range[T, S]) (bool, error) {
+	inners := make([]pgtype.Range[T], len(others))
+	for i, other := range others {
+		inners[i] = other.r
+	}
+	return r.ro.ContainsAllRanges(r.r, inners)
+}
+
+// Is the range contained by the other range?
+// PostgreSQL equivalent: anyrange <@ anyrange → boolean
+func (r Range[T, S]) ContainedBy(other Range[T, S]) (bool, error) {
+	return r.ro.ContainedBy(r.r, other.r)
+}
+
+// Does the range contain all the elements?
+func (r Range[T, S]) ContainsAll(elems []T) (bool, error) {
+	if !r.r.Valid {
+		return false, ErrInvalidRange
+	}
+	for _, elem := range elems {
+		contains, err := r.ContainElement(elem)
+		if err != nil || !contains {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// Does the range contain any of the elements?
+func (r Range[T, S]) ContainsAny(elems []T) (bool, error) {
+	if !r.r.Valid {
+		return false, ErrInvalidRange
+	}
+	for _, elem := range elems {
+		contains, err := r.ContainElement(elem)
+		if err != nil || contains {
+			return contains, err
+		}
+	}
+	return false, nil
+}
+
+// ContainsRange is the same as Contain.
+func (r Range[T, S]) ContainsRange(other Range[T, S]) (bool, error) {
+	return r.Contain(other)
+}
+
+// ContainsValue is the same as ContainElement.
+func (r Range[T, S]) ContainsValue(elem T) (bool, error) {
+	return r.ContainElement(elem)
+}
+
+// ContainsValues is the same as ContainsAll.
+func (r Range[T, S]) ContainsValues(elems ...T) (bool, error) {
+	return r.ContainsAll(elems)
+}
+
 // Do the ranges overlap, that is, have any elements in common?
 // PostgreSQL equivalent: anyrange && anyrange → boolean
 func (r Range[T, S]) Overlap(other Range[T, S]) (bool, error) {
 	return r.ro.Overlap(r.r, other.r)
 }
 
+// Does the range overlap any of the other ranges? The ranges are checked in order and the check
+// stops at the first overlapping range or the first error.
+func (r Range[T, S]) OverlapsAny(others ...Range[T, S]) (bool, error) {
+	if !r.r.Valid {
+		return false, ErrInvalidRange
+	}
+	for i, other := range others {
+		overlap, err := r.Overlap(other)
+		if err != nil {
+			return false, fmt.Errorf("range %d: %w", i, err)
+		}
+		if overlap {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // Is the first range strictly left of the second?
 // PostgreSQL equivalent: anyrange << anyrange → boolean
 func (r Range[T, S]) LeftOf(other Range[T, S]) (bool, error) {
@@ -246,6 +983,18 @@ func (r Range[T, S]) RightOf(other Range[T, S]) (bool, error) {
 	return r.ro.RightOf(r.r, other.r)
 }
 
+// Before is the same as LeftOf, it reads better for time ranges. The range ends before the other
+// range starts, so they don't overlap and don't share any value.
+func (r Range[T, S]) Before(other Range[T, S]) (bool, error) {
+	return r.LeftOf(other)
+}
+
+// After is the same as RightOf, it reads better for time ranges. The range starts after the other
+// range ends, so they don't overlap and don't share any value.
+func (r Range[T, S]) After(other Range[T, S]) (bool, error) {
+	return r.RightOf(other)
+}
+
 // Does the first range not extend to the right of the second?
 // PostgreSQL equivalent: anyrange &< anyrange → boolean
 func (r Range[T, S]) NotExtendRight(other Range[T, S]) (bool, error) {
@@ -264,38 +1013,337 @@ func (r Range[T, S]) Adjacent(other Range[T, S]) (bool, error) {
 	return r.ro.Adjacent(r.r, other.r)
 }
 
+// Do the ranges overlap or are they adjacent?
+func (r Range[T, S]) Touches(other Range[T, S]) (bool, error) {
+	return r.ro.Touches(r.r, other.r)
+}
+
+// Are the ranges adjacent when bounds that differ at most epsilon are treated as equal?
+func (r Range[T, S]) AdjacentWithin(other Range[T, S], epsilon S) (bool, error) {
+	return r.ro.AdjacentWithin(r.r, other.r, epsilon)
+}
+
+// Computes the union of the ranges.
+// PostgreSQL equivalent: anyrange + anyrange → anyrange
+//
+// The result is the receiver with its range replaced, on an error the receiver is returned.
+//
+// Deprecated: use Unioned, which returns a new range and the zero range on an error.
 func (r Range[T, S]) Union(other Range[T, S]) (Range[T, S], error) {
 	result, err := r.ro.Union(r.r, other.r)
+	if err != nil {
+		return r, err
+	}
 	r.r = result
-	return r, err
+	return r, nil
 }
 
+// Computes the smallest range that contains both ranges.
+// PostgreSQL equivalent: range_merge(anyrange, anyrange) → anyrange
+//
+// The result is the receiver with its range replaced, on an error the receiver is returned.
+//
+// Deprecated: use Merged, which returns a new range and the zero range on an error.
 func (r Range[T, S]) Merge(other Range[T, S]) (Range[T, S], error) {
 	result, err := r.ro.Merge(r.r, other.r)
+	if err != nil {
+		return r, err
+	}
+	r.r = result
+	return r, nil
+}
+
+// MergeInfo merges the ranges and reports if they are contiguous, see operator.MergeInfo.
+func (r Range[T, S]) MergeInfo(other Range[T, S]) (Range[T, S], bool, error) {
+	result, contiguous, err := r.ro.MergeInfo(r.r, other.r)
+	if err != nil {
+		return r, false, err
+	}
 	r.r = result
-	return r, err
+	return r, contiguous, nil
 }
 
 // Computes the intersection of the ranges.
 // PostgreSQL equivalent: anyrange * anyrange → anyrange
+//
+// The result is the receiver with its range replaced, on an error the receiver is returned.
+//
+// Deprecated: use Intersected, which returns a new range and the zero range on an error.
 func (r Range[T, S]) Intersect(other Range[T, S]) (Range[T, S], error) {
 	result, err := r.ro.Intersect(r.r, other.r)
+	if err != nil {
+		return r, err
+	}
 	r.r = result
-	return r, err
+	return r, nil
 }
 
+// Computes the difference of the ranges.
+// PostgreSQL equivalent: anyrange - anyrange → anyrange
+//
+// The result is the receiver with its range replaced, on an error the receiver is returned.
+//
+// Deprecated: use Differenced, which returns a new range and the zero range on an error.
 func (r Range[T, S]) Difference(other Range[T, S]) (Range[T, S], error) {
 	result, err := r.ro.Difference(r.r, other.r)
+	if err != nil {
+		return r, err
+	}
+	r.r = result
+	return r, nil
+}
+
+// Unioned returns a new range with the union of the ranges, the receiver is not changed.
+func (r Range[T, S]) Unioned(other Range[T, S]) (Range[T, S], error) {
+	return r.combined(other, r.ro.Union)
+}
+
+// Merged returns a new range with the smallest range that contains both ranges, the receiver is
+// not changed.
+func (r Range[T, S]) Merged(other Range[T, S]) (Range[T, S], error) {
+	return r.combined(other, r.ro.Merge)
+}
+
+// Intersected returns a new range with the intersection of the ranges, the receiver is not
+// changed.
+func (r Range[T, S]) Intersected(other Range[T, S]) (Range[T, S], error) {
+	return r.combined(other, r.ro.Intersect)
+}
+
+// Differenced returns a new range with the difference of the ranges, the receiver is not changed.
+func (r Range[T, S]) Differenced(other Range[T, S]) (Range[T, S], error) {
+	return r.combined(other, r.ro.Difference)
+}
+
+// combined returns a new range with the result of fn, or the zero range if fn fails
+func (r Range[T, S]) combined(other Range[T, S], fn func(first, second pgtype.Range[T]) (pgtype.Range[T], error)) (Range[T, S], error) {
+	result, err := fn(r.r, other.r)
+	if err != nil {
+		return Range[T, S]{}, err
+	}
+	return Range[T, S]{r: result, ro: r.ro}, nil
+}
+
+// Computes the range strictly between the ranges.
+func (r Range[T, S]) Gap(other Range[T, S]) (Range[T, S], error) {
+	result, err := r.ro.Gap(r.r, other.r)
+	if err != nil {
+		return r, err
+	}
+	r.r = result
+	return r, nil
+}
+
+// Moves both bounds of the range by delta.
+func (r Range[T, S]) Shift(delta S) (Range[T, S], error) {
+	result, err := r.ro.Shift(r.r, delta)
+	if err != nil {
+		return r, err
+	}
+	r.r = result
+	return r, nil
+}
+
+// Moves the lower bound down and the upper bound up by amount.
+func (r Range[T, S]) Expand(amount S) (Range[T, S], error) {
+	result, err := r.ro.Expand(r.r, amount)
+	if err != nil {
+		return r, err
+	}
+	r.r = result
+	return r, nil
+}
+
+// Moves the lower bound up and the upper bound down by amount.
+func (r Range[T, S]) Contract(amount S) (Range[T, S], error) {
+	result, err := r.ro.Contract(r.r, amount)
+	if err != nil {
+		return r, err
+	}
 	r.r = result
-	return r, err
+	return r, nil
+}
+
+// Converts a discrete range to the closed form [a,b].
+func (r Range[T, S]) ToClosed() (Range[T, S], error) {
+	result, err := r.ro.ToClosed(r.r)
+	if err != nil {
+		return r, err
+	}
+	r.r = result
+	return r, nil
+}
+
+// Converts a discrete range to the half-open form [a,b).
+func (r Range[T, S]) ToHalfOpen() (Range[T, S], error) {
+	result, err := r.ro.ToHalfOpen(r.r)
+	if err != nil {
+		return r, err
+	}
+	r.r = result
+	return r, nil
+}
+
+// Scales the range by factor while keeping its midpoint in place.
+func (r Range[T, S]) Scale(factor float64) (Range[T, S], error) {
+	result, err := r.ro.Scale(r.r, factor)
+	if err != nil {
+		return r, err
+	}
+	r.r = result
+	return r, nil
+}
+
+// Returns the value if the range contains it, otherwise the value of the range that is
+// closest to it.
+func (r Range[T, S]) Clamp(v T) (T, error) {
+	return r.ro.Clamp(r.r, v)
+}
+
+// Returns the value halfway between the bounds of the range.
+func (r Range[T, S]) Midpoint() (T, error) {
+	return r.ro.Midpoint(r.r)
+}
+
+// Splits the range into n contiguous and adjacent subranges of (nearly) equal size.
+func (r Range[T, S]) Partition(n int) ([]Range[T, S], error) {
+	partitions, err := r.ro.Partition(r.r, n)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]Range[T, S], len(partitions))
+	for i, p := range partitions {
+		result[i] = Range[T, S]{r: p, ro: r.ro}
+	}
+	return result, nil
+}
+
+// Creates windows of the given size that start at the lower bound and advance by step, windows
+// that extend beyond the range are clipped.
+func (r Range[T, S]) Windows(size, step S) ([]Range[T, S], error) {
+	windows, err := r.ro.Windows(r.r, size, step)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]Range[T, S], len(windows))
+	for i, w := range windows {
+		result[i] = Range[T, S]{r: w, ro: r.ro}
+	}
+	return result, nil
+}
+
+// Splits the range at the value into the part before and the part from the value.
+func (r Range[T, S]) SplitAt(at T) ([]Range[T, S], error) {
+	parts, err := r.ro.SplitAt(r.r, at)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]Range[T, S], len(parts))
+	for i, p := range parts {
+		result[i] = Range[T, S]{r: p, ro: r.ro}
+	}
+	return result, nil
+}
+
+// Returns an iterator over all the elements of a discrete range.
+func (r Range[T, S]) Elements() (iter.Seq[T], error) {
+	return r.ro.Elements(r.r)
+}
+
+// Calls fn for every value of the range starting at the lower bound and advancing by stride.
+func (r Range[T, S]) ForEachStep(stride S, fn func(T) error) error {
+	return r.ro.ForEachStep(r.r, stride, fn)
+}
+
+// Computes the distance between the canonical lower bound of the range and the value.
+func (r Range[T, S]) Offset(v T) (S, error) {
+	return r.ro.Offset(r.r, v)
+}
+
+// Computes the distance between the nearest bounds of the ranges.
+func (r Range[T, S]) Distance(other Range[T, S]) (S, error) {
+	return r.ro.Distance(r.r, other.r)
+}
+
+// Computes the size of the intersection of the ranges.
+func (r Range[T, S]) OverlapSize(other Range[T, S]) (S, error) {
+	return r.ro.OverlapSize(r.r, other.r)
+}
+
+// Computes the Jaccard similarity of the ranges.
+func (r Range[T, S]) Jaccard(other Range[T, S]) (float64, error) {
+	return r.ro.Jaccard(r.r, other.r)
+}
+
+// Computes a hash of the canonicalized range.
+func (r Range[T, S]) Hash() (uint64, error) {
+	return r.ro.Hash(r.r)
+}
+
+// Checks that the range is valid and well-formed.
+func (r Range[T, S]) Validate() error {
+	return r.ro.Validate(r.r)
 }
 
 func (r Range[T, S]) Size() (S, error) {
 	return r.ro.Size(r.r)
 }
 
+// Interval returns the lower bound and the size of the canonicalized range, for a TimeRange
+// this is the start and the duration.
+func (r Range[T, S]) Interval() (T, S, error) {
+	var size S
+	if e, err := r.ro.Empty(r.r); err != nil {
+		return r.ro.zero, size, err
+	} else if e {
+		return r.ro.zero, size, fmt.Errorf("interval: %w", ErrEmptyUndefined)
+	}
+	if r.r.LowerType == pgtype.Unbounded || r.r.UpperType == pgtype.Unbounded {
+		return r.ro.zero, size, fmt.Errorf("interval: %w", ErrUnboundedRange)
+	}
+
+	rewritten := r.ro.Rewrite(r.r)
+	size, err := r.ro.Size(rewritten)
+	if err != nil {
+		return r.ro.zero, size, err
+	}
+	return rewritten.Lower, size, nil
+}
+
+// Window returns the start and end of the canonicalized range [start,end), it is the inverse of
+// TimeRangeFromWindow. Continuous ranges with other bound types, like [start,end], have no
+// window.
+func (r Range[T, S]) Window() (start, end T, err error) {
+	if e, err := r.ro.Empty(r.r); err != nil {
+		return r.ro.zero, r.ro.zero, err
+	} else if e {
+		return r.ro.zero, r.ro.zero, fmt.Errorf("window: %w", ErrEmptyUndefined)
+	}
+	if r.r.LowerType == pgtype.Unbounded || r.r.UpperType == pgtype.Unbounded {
+		return r.ro.zero, r.ro.zero, fmt.Errorf("window: %w", ErrUnboundedRange)
+	}
+
+	rewritten := r.ro.Rewrite(r.r)
+	if rewritten.LowerType != pgtype.Inclusive || rewritten.UpperType != pgtype.Exclusive {
+		return r.ro.zero, r.ro.zero, fmt.Errorf("window: range %v is not half-open", r)
+	}
+	return rewritten.Lower, rewritten.Upper, nil
+}
+
+// SizeBig is like Size but the result doesn't wrap around for very wide ranges.
+func (r Range[T, S]) SizeBig() (*big.Int, error) {
+	return r.ro.SizeBig(r.r)
+}
+
 func (r Range[T, S]) Rewrite() Range[T, S] {
 	result := r.ro.Rewrite(r.r)
 	r.r = result
 	return r
 }
+
+// Canonical returns a new range in canonical form. For discrete types bounded ranges are
+// converted to the form [ , ) and ranges without elements become the standard empty range.
+// The receiver is left untouched.
+func (r Range[T, S]) Canonical() Range[T, S] {
+	return FromPgtypeRange(r.ro.Rewrite(r.r), r.ro)
+}
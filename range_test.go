@@ -0,0 +1,1426 @@
+package pro
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/shopspring/decimal"
+)
+
+func TestContainsAllAndAny(t *testing.T) {
+	tests := []struct {
+		r           IntegerRange
+		elems       []int
+		expectedAll bool
+		expectedAny bool
+		expectedErr bool
+	}{
+		{
+			r:           NewIntegerRange(1, 5),
+			elems:       []int{1, 2, 4},
+			expectedAll: true,
+			expectedAny: true,
+		},
+		{
+			r:           NewIntegerRange(1, 5),
+			elems:       []int{1, 5},
+			expectedAll: false,
+			expectedAny: true,
+		},
+		{
+			r:           NewIntegerRange(1, 5, WithLowerType[int, int](pgtype.Exclusive), WithUpperType[int, int](pgtype.Inclusive)),
+			elems:       []int{2, 5},
+			expectedAll: true,
+			expectedAny: true,
+		},
+		{
+			r:           NewIntegerRange(1, 5, WithLowerType[int, int](pgtype.Exclusive), WithUpperType[int, int](pgtype.Inclusive)),
+			elems:       []int{1, 6, 0},
+			expectedAll: false,
+			expectedAny: false,
+		},
+		{
+			r:           NewIntegerRange(1, 5),
+			elems:       nil,
+			expectedAll: true,
+			expectedAny: false,
+		},
+		{
+			r:           NewIntegerRange(1, 5, WithInvalid[int, int]()),
+			elems:       []int{1},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		all, err := tt.r.ContainsAll(tt.elems)
+		if err == nil && tt.expectedErr {
+			t.Errorf("`%v` contains all `%v`: expected error, got none", tt.r.r, tt.elems)
+		}
+		if err != nil && !tt.expectedErr {
+			t.Errorf("`%v` contains all `%v`: expected no error, got `%v`", tt.r.r, tt.elems, err)
+		}
+		some, err := tt.r.ContainsAny(tt.elems)
+		if err == nil && tt.expectedErr {
+			t.Errorf("`%v` contains any `%v`: expected error, got none", tt.r.r, tt.elems)
+		}
+		if err != nil && !tt.expectedErr {
+			t.Errorf("`%v` contains any `%v`: expected no error, got `%v`", tt.r.r, tt.elems, err)
+		}
+		if tt.expectedErr {
+			continue
+		}
+		if tt.expectedAll != all {
+			t.Errorf("`%v` contains all `%v`: expected result `%v`, got `%v`", tt.r.r, tt.elems, tt.expectedAll, all)
+		}
+		if tt.expectedAny != some {
+			t.Errorf("`%v` contains any `%v`: expected result `%v`, got `%v`", tt.r.r, tt.elems, tt.expectedAny, some)
+		}
+	}
+}
+
+func TestOverlapsAny(t *testing.T) {
+	r := NewIntegerRange(10, 20)
+	tests := []struct {
+		others      []IntegerRange
+		expected    bool
+		expectedErr bool
+	}{
+		{
+			others:   []IntegerRange{NewIntegerRange(0, 5), NewIntegerRange(15, 25), NewIntegerRange(30, 40)},
+			expected: true,
+		},
+		{
+			// adjacent and empty ranges don't overlap
+			others:   []IntegerRange{NewIntegerRange(0, 10), NewIntegerRange(20, 30), NewEmptyIntegerRange(), NewIntegerRange(0, 9, WithUpperType[int, int](pgtype.Inclusive))},
+			expected: false,
+		},
+		{
+			others:   nil,
+			expected: false,
+		},
+		{
+			// the check stops at the first overlapping range
+			others:   []IntegerRange{NewIntegerRange(19, 21), NewIntegerRange(1, 5, WithInvalid[int, int]())},
+			expected: true,
+		},
+		{
+			others:      []IntegerRange{NewIntegerRange(0, 5), NewIntegerRange(1, 5, WithInvalid[int, int]()), NewIntegerRange(15, 25)},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		result, err := r.OverlapsAny(tt.others...)
+		if err == nil && tt.expectedErr {
+			t.Errorf("`%v` overlaps any `%v`: expected error, got none", r.r, tt.others)
+		}
+		if err != nil && !tt.expectedErr {
+			t.Errorf("`%v` overlaps any `%v`: expected no error, got `%v`", r.r, tt.others, err)
+		}
+		if err != nil || tt.expectedErr {
+			continue
+		}
+		if result != tt.expected {
+			t.Errorf("`%v` overlaps any `%v`: expected result `%v`, got `%v`", r.r, tt.others, tt.expected, result)
+		}
+	}
+
+	invalid := NewIntegerRange(1, 5, WithInvalid[int, int]())
+	if _, err := invalid.OverlapsAny(); !errors.Is(err, ErrInvalidRange) {
+		t.Errorf("`%v` overlaps any: expected error `%v`, got `%v`", invalid.r, ErrInvalidRange, err)
+	}
+}
+
+func TestContainsAliases(t *testing.T) {
+	ranges := []IntegerRange{
+		NewIntegerRange(1, 5),
+		NewIntegerRange(1, 5, WithLowerType[int, int](pgtype.Exclusive), WithUpperType[int, int](pgtype.Inclusive)),
+		NewIntegerRange(0, 3, WithLowerInf[int, int]()),
+		NewEmptyIntegerRange(),
+		NewIntegerRange(1, 5, WithInvalid[int, int]()),
+	}
+	for _, r := range ranges {
+		for _, other := range ranges {
+			expected, expectedErr := r.Contain(other)
+			if result, err := r.ContainsRange(other); result != expected || (err == nil) != (expectedErr == nil) {
+				t.Errorf("contains range `%v` and `%v`: expected `%v` (error `%v`), got `%v` (error `%v`)", r.r, other.r, expected, expectedErr, result, err)
+			}
+		}
+		for _, v := range []int{0, 1, 3, 5, 6} {
+			expected, expectedErr := r.ContainElement(v)
+			if result, err := r.ContainsValue(v); result != expected || (err == nil) != (expectedErr == nil) {
+				t.Errorf("contains value `%v` and `%v`: expected `%v` (error `%v`), got `%v` (error `%v`)", r.r, v, expected, expectedErr, result, err)
+			}
+		}
+		for _, values := range [][]int{nil, {2, 3}, {1, 5}, {3, 6}} {
+			expected, expectedErr := r.ContainsAll(values)
+			if result, err := r.ContainsValues(values...); result != expected || (err == nil) != (expectedErr == nil) {
+				t.Errorf("contains values `%v` and `%v`: expected `%v` (error `%v`), got `%v` (error `%v`)", r.r, values, expected, expectedErr, result, err)
+			}
+		}
+	}
+}
+
+func TestBeforeAfter(t *testing.T) {
+	start := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	ranges := []TimeRange{
+		NewTimeRange(start, start.Add(time.Hour)),
+		NewTimeRange(start.Add(time.Hour), start.Add(2*time.Hour)),
+		NewTimeRange(start.Add(time.Hour), start.Add(2*time.Hour), WithLowerType[time.Time, time.Duration](pgtype.Exclusive)),
+		NewTimeRange(start, start.Add(time.Hour), WithUpperType[time.Time, time.Duration](pgtype.Inclusive)),
+		NewTimeRange(start.Add(30*time.Minute), start.Add(90*time.Minute)),
+		NewTimeRange(start, start.Add(time.Hour), WithLowerInf[time.Time, time.Duration]()),
+		NewTimeRange(start, start, WithUpperType[time.Time, time.Duration](pgtype.Unbounded)),
+		NewEmptyTimeRange(),
+		NewTimeRange(start, start.Add(time.Hour), WithInvalid[time.Time, time.Duration]()),
+	}
+	for _, r := range ranges {
+		for _, other := range ranges {
+			expected, expectedErr := r.LeftOf(other)
+			if result, err := r.Before(other); result != expected || (err == nil) != (expectedErr == nil) {
+				t.Errorf("`%v` before `%v`: expected `%v` (error `%v`), got `%v` (error `%v`)", r.r, other.r, expected, expectedErr, result, err)
+			}
+			expected, expectedErr = r.RightOf(other)
+			if result, err := r.After(other); result != expected || (err == nil) != (expectedErr == nil) {
+				t.Errorf("`%v` after `%v`: expected `%v` (error `%v`), got `%v` (error `%v`)", r.r, other.r, expected, expectedErr, result, err)
+			}
+		}
+	}
+
+	// adjacent ranges are ordered, ranges sharing a value are not
+	if before, err := ranges[0].Before(ranges[1]); err != nil || !before {
+		t.Errorf("`%v` before `%v`: expected `true`, got `%v` (error `%v`)", ranges[0].r, ranges[1].r, before, err)
+	}
+	if after, err := ranges[1].After(ranges[0]); err != nil || !after {
+		t.Errorf("`%v` after `%v`: expected `true`, got `%v` (error `%v`)", ranges[1].r, ranges[0].r, after, err)
+	}
+	if before, err := ranges[3].Before(ranges[1]); err != nil || before {
+		t.Errorf("`%v` before `%v`: expected `false`, got `%v` (error `%v`)", ranges[3].r, ranges[1].r, before, err)
+	}
+}
+
+func TestClone(t *testing.T) {
+	original := NewIntegerRange(1, 5)
+	clone := original.Clone()
+	clone.SetLower(3).SetUpperInf()
+
+	if lower, _ := original.Lower(); lower != 1 {
+		t.Errorf("clone: expected lower of original to be `%v`, got `%v`", 1, lower)
+	}
+	if upper, _ := original.Upper(); upper != 5 {
+		t.Errorf("clone: expected upper of original to be `%v`, got `%v`", 5, upper)
+	}
+	if lower, _ := clone.Lower(); lower != 3 {
+		t.Errorf("clone: expected lower of clone to be `%v`, got `%v`", 3, lower)
+	}
+}
+
+func TestUnwrap(t *testing.T) {
+	original := pgtype.Range[int]{Lower: 1, LowerType: pgtype.Exclusive, Upper: 5, UpperType: pgtype.Inclusive, Valid: true}
+	wrapped := FromPgtypeRange(original, NewInteger())
+
+	unwrapped := wrapped.Unwrap()
+	if unwrapped != original {
+		t.Errorf("unwrap: expected `%v`, got `%v`", original, unwrapped)
+	}
+
+	unwrapped.Lower = 3
+	if lower, _ := wrapped.Lower(); lower != 1 {
+		t.Errorf("unwrap: expected lower of wrapped range to be `%v`, got `%v`", 1, lower)
+	}
+}
+
+func TestEmptyRange(t *testing.T) {
+	empty := NewEmptyIntegerRange()
+	if e, err := empty.Empty(); err != nil || !e {
+		t.Errorf("`%v` empty: expected `true`, got `%v` (error `%v`)", empty.r, e, err)
+	}
+	if size, err := empty.Size(); err != nil || size != 0 {
+		t.Errorf("`%v` size: expected `0`, got `%v` (error `%v`)", empty.r, size, err)
+	}
+
+	for _, other := range []IntegerRange{
+		NewIntegerRange(1, 5),
+		NewIntegerRange(0, 0, WithLowerInf[int, int](), WithUpperType[int, int](pgtype.Unbounded)),
+		NewEmptyIntegerRange(),
+	} {
+		if overlap, err := empty.Overlap(other); err != nil || overlap {
+			t.Errorf("`%v` overlaps `%v`: expected `false`, got `%v` (error `%v`)", empty.r, other.r, overlap, err)
+		}
+		if contain, err := other.Contain(empty); err != nil || !contain {
+			t.Errorf("`%v` contains `%v`: expected `true`, got `%v` (error `%v`)", other.r, empty.r, contain, err)
+		}
+	}
+
+	emptyTime := NewEmptyTimeRange()
+	if e, err := emptyTime.Empty(); err != nil || !e {
+		t.Errorf("`%v` empty: expected `true`, got `%v` (error `%v`)", emptyTime.r, e, err)
+	}
+	if size, err := emptyTime.Size(); err != nil || size != 0 {
+		t.Errorf("`%v` size: expected `0`, got `%v` (error `%v`)", emptyTime.r, size, err)
+	}
+	other := NewTimeRange(time.Unix(0, 0), time.Unix(60, 0))
+	if contain, err := other.Contain(emptyTime); err != nil || !contain {
+		t.Errorf("`%v` contains `%v`: expected `true`, got `%v` (error `%v`)", other.r, emptyTime.r, contain, err)
+	}
+}
+
+func TestMutationOnError(t *testing.T) {
+	tests := []struct {
+		name string
+		r    IntegerRange
+		f    func(IntegerRange) (IntegerRange, error)
+	}{
+		{
+			name: "union",
+			r:    NewIntegerRange(1, 5),
+			f:    func(r IntegerRange) (IntegerRange, error) { return r.Union(NewIntegerRange(10, 15)) },
+		},
+		{
+			name: "difference",
+			r:    NewIntegerRange(1, 15),
+			f:    func(r IntegerRange) (IntegerRange, error) { return r.Difference(NewIntegerRange(5, 10)) },
+		},
+		{
+			name: "intersect",
+			r:    NewIntegerRange(1, 5),
+			f: func(r IntegerRange) (IntegerRange, error) {
+				return r.Intersect(NewIntegerRange(1, 5, WithInvalid[int, int]()))
+			},
+		},
+		{
+			name: "merge",
+			r:    NewIntegerRange(1, 5),
+			f: func(r IntegerRange) (IntegerRange, error) {
+				return r.Merge(NewIntegerRange(1, 5, WithInvalid[int, int]()))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		result, err := tt.f(tt.r)
+		if err == nil {
+			t.Errorf("%s `%v`: expected error, got none", tt.name, tt.r.r)
+			continue
+		}
+		if result.r != tt.r.r {
+			t.Errorf("%s `%v`: expected range to be unchanged, got `%v`", tt.name, tt.r.r, result.r)
+		}
+		if !result.r.Valid {
+			t.Errorf("%s `%v`: expected range to stay valid", tt.name, tt.r.r)
+		}
+	}
+}
+
+func TestNewRange(t *testing.T) {
+	// float ranges are continuous, the size is measured in thousandths
+	fro := NewWithAdd(
+		cmp.Compare[float64],
+		func(a, b float64) int64 { return int64((a - b) * 1000) },
+		func(a float64, d int64) float64 { return a + float64(d)/1000 },
+		false,
+	)
+
+	r := NewRange(fro, 0.5, 1.5)
+	if r.r.LowerType != pgtype.Inclusive || r.r.UpperType != pgtype.Exclusive || !r.r.Valid {
+		t.Errorf("new range: expected `[0.5,1.5)`, got `%v`", r.r)
+	}
+
+	tests := []struct {
+		other           Range[float64, int64]
+		expectedOverlap bool
+		expectedContain bool
+	}{
+		{
+			other:           NewRange(fro, 0.75, 1.25),
+			expectedOverlap: true,
+			expectedContain: true,
+		},
+		{
+			other:           NewRange(fro, 1.25, 2.0),
+			expectedOverlap: true,
+			expectedContain: false,
+		},
+		{
+			other:           NewRange(fro, 1.5, 2.0),
+			expectedOverlap: false,
+			expectedContain: false,
+		},
+		{
+			other:           NewRange(fro, 0.5, 1.5, WithUpperType[float64, int64](pgtype.Inclusive)),
+			expectedOverlap: true,
+			expectedContain: false,
+		},
+	}
+
+	for _, tt := range tests {
+		overlap, err := r.Overlap(tt.other)
+		if err != nil {
+			t.Errorf("`%v` overlaps `%v`: expected no error, got `%v`", r.r, tt.other.r, err)
+		} else if overlap != tt.expectedOverlap {
+			t.Errorf("`%v` overlaps `%v`: expected result `%v`, got `%v`", r.r, tt.other.r, tt.expectedOverlap, overlap)
+		}
+		contain, err := r.Contain(tt.other)
+		if err != nil {
+			t.Errorf("`%v` contains `%v`: expected no error, got `%v`", r.r, tt.other.r, err)
+		} else if contain != tt.expectedContain {
+			t.Errorf("`%v` contains `%v`: expected result `%v`, got `%v`", r.r, tt.other.r, tt.expectedContain, contain)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		r           IntegerRange
+		expectedErr error
+	}{
+		{
+			r: NewIntegerRange(1, 10),
+		},
+		{
+			r: NewIntegerRange(5, 5),
+		},
+		{
+			r: NewIntegerRange(10, 1, WithLowerInf[int, int]()),
+		},
+		{
+			r: NewEmptyIntegerRange(),
+		},
+		{
+			r:           NewIntegerRange(10, 1),
+			expectedErr: ErrMalformedRange,
+		},
+		{
+			r:           NewIntegerRange(1, 10, WithLowerType[int, int](pgtype.Empty)),
+			expectedErr: ErrMalformedRange,
+		},
+		{
+			r:           NewIntegerRange(1, 10, WithUpperType[int, int](pgtype.Empty)),
+			expectedErr: ErrMalformedRange,
+		},
+		{
+			r:           NewIntegerRange(1, 10, WithInvalid[int, int]()),
+			expectedErr: ErrInvalidRange,
+		},
+	}
+
+	for _, tt := range tests {
+		err := tt.r.Validate()
+		if tt.expectedErr == nil && err != nil {
+			t.Errorf("validate `%v`: expected no error, got `%v`", tt.r.r, err)
+		}
+		if tt.expectedErr != nil && !errors.Is(err, tt.expectedErr) {
+			t.Errorf("validate `%v`: expected error `%v`, got `%v`", tt.r.r, tt.expectedErr, err)
+		}
+	}
+}
+
+func TestWithValidity(t *testing.T) {
+	other := NewIntegerRange(1, 5)
+	for _, r := range []IntegerRange{
+		NewIntegerRange(1, 5, WithInvalid[int, int]()),
+		NewIntegerRange(1, 5, WithInvalid[int, int](), WithLowerType[int, int](pgtype.Exclusive)),
+		NewIntegerRange(1, 5, WithLowerType[int, int](pgtype.Exclusive), WithInvalid[int, int]()),
+		NewIntegerRange(1, 5, WithUpperType[int, int](pgtype.Unbounded), WithInvalid[int, int]()),
+		NewIntegerRange(1, 5, WithEmpty[int, int](), WithInvalid[int, int]()),
+		NewIntegerRange(1, 5, WithValidity[int, int](true), WithValidity[int, int](false)),
+	} {
+		if r.r.Valid {
+			t.Errorf("with invalid `%v`: expected range to be not valid", r.r)
+		}
+		for name, fn := range map[string]func() error{
+			"empty":     func() error { _, err := r.Empty(); return err },
+			"size":      func() error { _, err := r.Size(); return err },
+			"equal":     func() error { _, err := r.Equal(other); return err },
+			"overlap":   func() error { _, err := other.Overlap(r); return err },
+			"contain":   func() error { _, err := r.ContainElement(3); return err },
+			"union":     func() error { _, err := r.Union(other); return err },
+			"intersect": func() error { _, err := other.Intersect(r); return err },
+		} {
+			if err := fn(); !errors.Is(err, ErrInvalidRange) {
+				t.Errorf("%s `%v`: expected error `%v`, got `%v`", name, r.r, ErrInvalidRange, err)
+			}
+		}
+	}
+
+	// a later option wins
+	r := NewIntegerRange(1, 5, WithInvalid[int, int](), WithLowerType[int, int](pgtype.Exclusive), WithValidity[int, int](true))
+	expected := pgtype.Range[int]{Lower: 1, LowerType: pgtype.Exclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true}
+	if r.r != expected {
+		t.Errorf("with validity: expected `%v`, got `%v`", expected, r.r)
+	}
+}
+
+func TestWithOperator(t *testing.T) {
+	start := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	other := NewTimeRange(start.Add(6*time.Minute), start.Add(10*time.Minute))
+
+	// [10:00,10:05] and [10:06,10:10) are adjacent when time is counted in minutes
+	r := NewTimeRange(start, start.Add(5*time.Minute), WithUpperType[time.Time, time.Duration](pgtype.Inclusive))
+	if adjacent, err := r.Adjacent(other); err != nil || adjacent {
+		t.Errorf("adjacent `%v` and `%v`: expected result `false`, got `%v` (error `%v`)", r, other, adjacent, err)
+	}
+	r = NewTimeRange(start, start.Add(5*time.Minute), WithUpperType[time.Time, time.Duration](pgtype.Inclusive), WithOperator(NewTimeWithStep(time.Minute)))
+	if adjacent, err := r.Adjacent(other); err != nil || !adjacent {
+		t.Errorf("adjacent `%v` and `%v` in minutes: expected result `true`, got `%v` (error `%v`)", r, other, adjacent, err)
+	}
+	if size, err := r.Size(); err != nil || size != 6 {
+		t.Errorf("size `%v` in minutes: expected result `6`, got `%v` (error `%v`)", r, size, err)
+	}
+}
+
+func TestNewRangeChecked(t *testing.T) {
+	tests := []struct {
+		lower       int
+		upper       int
+		expectedErr bool
+	}{
+		{lower: 1, upper: 10},
+		{lower: 5, upper: 5},
+		{lower: 10, upper: 1, expectedErr: true},
+	}
+
+	for _, tt := range tests {
+		result, err := NewIntegerRangeChecked(tt.lower, tt.upper)
+		if err == nil && tt.expectedErr {
+			t.Errorf("new checked `%v` `%v`: expected error, got none", tt.lower, tt.upper)
+		}
+		if err != nil && !tt.expectedErr {
+			t.Errorf("new checked `%v` `%v`: expected no error, got `%v`", tt.lower, tt.upper, err)
+		}
+		if err != nil || tt.expectedErr {
+			continue
+		}
+		if expected := NewIntegerRange(tt.lower, tt.upper); result.r != expected.r {
+			t.Errorf("new checked `%v` `%v`: expected result `%v`, got `%v`", tt.lower, tt.upper, expected.r, result.r)
+		}
+	}
+
+	if _, err := NewTimeRangeChecked(time.Unix(60, 0), time.Unix(0, 0)); !errors.Is(err, ErrMalformedRange) {
+		t.Errorf("new checked time: expected error `%v`, got `%v`", ErrMalformedRange, err)
+	}
+	if _, err := NewTimeRangeChecked(time.Unix(0, 0), time.Unix(60, 0)); err != nil {
+		t.Errorf("new checked time: expected no error, got `%v`", err)
+	}
+}
+
+func TestCanonical(t *testing.T) {
+	tests := []struct {
+		r        IntegerRange
+		expected IntegerRange
+	}{
+		{
+			r:        NewIntegerRange(1, 5, WithLowerType[int, int](pgtype.Exclusive), WithUpperType[int, int](pgtype.Inclusive)),
+			expected: NewIntegerRange(2, 6),
+		},
+		{
+			r:        NewIntegerRange(2, 6),
+			expected: NewIntegerRange(2, 6),
+		},
+		{
+			r:        NewIntegerRange(5, 5),
+			expected: NewEmptyIntegerRange(),
+		},
+		{
+			r:        NewIntegerRange(4, 5, WithLowerType[int, int](pgtype.Exclusive)),
+			expected: NewEmptyIntegerRange(),
+		},
+		{
+			r:        NewEmptyIntegerRange(),
+			expected: NewEmptyIntegerRange(),
+		},
+		{
+			r:        NewIntegerRange(0, 5, WithLowerInf[int, int](), WithUpperType[int, int](pgtype.Inclusive)),
+			expected: NewIntegerRange(0, 6, WithLowerInf[int, int]()),
+		},
+	}
+
+	for _, tt := range tests {
+		original := tt.r.r
+		result := tt.r.Canonical()
+		if result.r != tt.expected.r {
+			t.Errorf("canonical `%v`: expected result `%v`, got `%v`", tt.r.r, tt.expected.r, result.r)
+		}
+		if tt.r.r != original {
+			t.Errorf("canonical `%v`: expected receiver to be unchanged, got `%v`", original, tt.r.r)
+		}
+	}
+}
+
+func TestLowerUpperInc(t *testing.T) {
+	tests := []struct {
+		r             IntegerRange
+		expectedLower bool
+		expectedUpper bool
+	}{
+		{
+			r:             NewIntegerRange(1, 5),
+			expectedLower: true,
+			expectedUpper: false,
+		},
+		{
+			r:             NewIntegerRange(1, 5, WithLowerType[int, int](pgtype.Exclusive), WithUpperType[int, int](pgtype.Inclusive)),
+			expectedLower: true,
+			expectedUpper: false,
+		},
+		{
+			r:             NewIntegerRange(0, 5, WithLowerInf[int, int]()),
+			expectedLower: false,
+			expectedUpper: false,
+		},
+		{
+			r:             NewIntegerRange(1, 0, WithUpperType[int, int](pgtype.Unbounded)),
+			expectedLower: true,
+			expectedUpper: false,
+		},
+		{
+			r:             NewEmptyIntegerRange(),
+			expectedLower: false,
+			expectedUpper: false,
+		},
+		{
+			r:             NewIntegerRange(5, 5),
+			expectedLower: false,
+			expectedUpper: false,
+		},
+		{
+			r:             NewIntegerRange(1, 5, WithInvalid[int, int]()),
+			expectedLower: false,
+			expectedUpper: false,
+		},
+	}
+
+	for _, tt := range tests {
+		if result := tt.r.LowerInc(); result != tt.expectedLower {
+			t.Errorf("lower_inc `%v`: expected result `%v`, got `%v`", tt.r.r, tt.expectedLower, result)
+		}
+		if result := tt.r.UpperInc(); result != tt.expectedUpper {
+			t.Errorf("upper_inc `%v`: expected result `%v`, got `%v`", tt.r.r, tt.expectedUpper, result)
+		}
+	}
+
+	// continuous ranges keep their bound types
+	r := NewTimeRange(time.Unix(0, 0), time.Unix(60, 0), WithLowerType[time.Time, time.Duration](pgtype.Exclusive), WithUpperType[time.Time, time.Duration](pgtype.Inclusive))
+	if r.LowerInc() || !r.UpperInc() {
+		t.Errorf("lower_inc and upper_inc `%v`: expected results `false` and `true`, got `%v` and `%v`", r.r, r.LowerInc(), r.UpperInc())
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		r        pgtype.Range[int]
+		expected pgtype.Range[int]
+	}{
+		{
+			// valid range stays the same
+			r:        pgtype.Range[int]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+			expected: pgtype.Range[int]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+		},
+		{
+			// not valid range with proper bound types is made valid
+			r:        pgtype.Range[int]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive},
+			expected: pgtype.Range[int]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+		},
+		{
+			// lower bound empty only
+			r:        pgtype.Range[int]{Lower: 1, LowerType: pgtype.Empty, Upper: 5, UpperType: pgtype.Exclusive, Valid: true},
+			expected: pgtype.Range[int]{Lower: 1, LowerType: pgtype.Empty, Upper: 5, UpperType: pgtype.Exclusive},
+		},
+		{
+			// upper bound empty only
+			r:        pgtype.Range[int]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Empty, Valid: true},
+			expected: pgtype.Range[int]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Empty},
+		},
+		{
+			// empty range with bound values and without valid
+			r:        pgtype.Range[int]{Lower: 1, LowerType: pgtype.Empty, Upper: 5, UpperType: pgtype.Empty},
+			expected: pgtype.Range[int]{LowerType: pgtype.Empty, UpperType: pgtype.Empty, Valid: true},
+		},
+		{
+			// unbounded bounds with values
+			r:        pgtype.Range[int]{Lower: 1, LowerType: pgtype.Unbounded, Upper: 5, UpperType: pgtype.Unbounded},
+			expected: pgtype.Range[int]{LowerType: pgtype.Unbounded, UpperType: pgtype.Unbounded, Valid: true},
+		},
+	}
+
+	for _, tt := range tests {
+		r := FromPgtypeRange(tt.r, NewInteger())
+		if result := r.Normalize().r; result != tt.expected {
+			t.Errorf("normalize `%v`: expected result `%v`, got `%v`", tt.r, tt.expected, result)
+		}
+	}
+}
+
+func TestInterval(t *testing.T) {
+	start := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	r := NewTimeRange(start, start.Add(time.Hour))
+	lower, dur, err := r.Interval()
+	if err != nil || !lower.Equal(start) || dur != time.Hour {
+		t.Errorf("interval `%v`: expected `%v` and `%v`, got `%v` and `%v` (error `%v`)", r.r, start, time.Hour, lower, dur, err)
+	}
+
+	// discrete ranges are canonicalized first
+	lowerInt, size, err := NewIntegerRange(1, 5, WithLowerType[int, int](pgtype.Exclusive), WithUpperType[int, int](pgtype.Inclusive)).Interval()
+	if err != nil || lowerInt != 2 || size != 4 {
+		t.Errorf("interval `(1,5]`: expected `2` and `4`, got `%v` and `%v` (error `%v`)", lowerInt, size, err)
+	}
+
+	for _, tt := range []struct {
+		r        TimeRange
+		expected error
+	}{
+		{r: NewEmptyTimeRange(), expected: ErrEmptyUndefined},
+		{r: NewTimeRange(start, start), expected: ErrEmptyUndefined},
+		{r: NewTimeRange(start, start, WithLowerInf[time.Time, time.Duration]()), expected: ErrUnboundedRange},
+		{r: NewTimeRange(start, start, WithUpperType[time.Time, time.Duration](pgtype.Unbounded)), expected: ErrUnboundedRange},
+		{r: NewTimeRange(start, start.Add(time.Hour), WithInvalid[time.Time, time.Duration]()), expected: ErrInvalidRange},
+	} {
+		if _, _, err := tt.r.Interval(); !errors.Is(err, tt.expected) {
+			t.Errorf("interval `%v`: expected error `%v`, got `%v`", tt.r.r, tt.expected, err)
+		}
+	}
+}
+
+func TestWindow(t *testing.T) {
+	window := struct{ Start, End time.Time }{
+		Start: time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, 3, 1, 17, 30, 0, 0, time.UTC),
+	}
+	r, err := TimeRangeFromWindow(window.Start, window.End)
+	if err != nil {
+		t.Fatalf("range from window `%v`: expected no error, got `%v`", window, err)
+	}
+	if !r.LowerInc() || r.UpperInc() {
+		t.Errorf("range from window `%v`: expected bounds `[)`, got `%v`", window, r)
+	}
+	start, end, err := r.Window()
+	if err != nil || !start.Equal(window.Start) || !end.Equal(window.End) {
+		t.Errorf("window `%v`: expected `%v` and `%v`, got `%v` and `%v` (error `%v`)", r, window.Start, window.End, start, end, err)
+	}
+
+	// a window without duration is the empty range
+	if r, err := TimeRangeFromWindow(window.Start, window.Start); err != nil || !r.IsEmpty() {
+		t.Errorf("range from window `%v` `%v`: expected empty range, got `%v` (error `%v`)", window.Start, window.Start, r, err)
+	}
+	if _, err := TimeRangeFromWindow(window.End, window.Start); !errors.Is(err, ErrMalformedRange) {
+		t.Errorf("range from window `%v` `%v`: expected error `%v`, got `%v`", window.End, window.Start, ErrMalformedRange, err)
+	}
+
+	// discrete ranges are canonicalized first
+	lower, upper, err := NewIntegerRange(1, 5, WithLowerType[int, int](pgtype.Exclusive), WithUpperType[int, int](pgtype.Inclusive)).Window()
+	if err != nil || lower != 2 || upper != 6 {
+		t.Errorf("window `(1,5]`: expected `2` and `6`, got `%v` and `%v` (error `%v`)", lower, upper, err)
+	}
+
+	for _, tt := range []struct {
+		r        TimeRange
+		expected error
+	}{
+		{r: NewEmptyTimeRange(), expected: ErrEmptyUndefined},
+		{r: NewTimeRange(window.Start, window.End, WithLowerInf[time.Time, time.Duration]()), expected: ErrUnboundedRange},
+		{r: NewTimeRange(window.Start, window.End, WithInvalid[time.Time, time.Duration]()), expected: ErrInvalidRange},
+	} {
+		if _, _, err := tt.r.Window(); !errors.Is(err, tt.expected) {
+			t.Errorf("window `%v`: expected error `%v`, got `%v`", tt.r.r, tt.expected, err)
+		}
+	}
+	closed := NewTimeRange(window.Start, window.End, WithUpperType[time.Time, time.Duration](pgtype.Inclusive))
+	if _, _, err := closed.Window(); err == nil {
+		t.Errorf("window `%v`: expected error, got none", closed)
+	}
+}
+
+func TestLowerUpperBound(t *testing.T) {
+	boundTypes := []pgtype.BoundType{pgtype.Inclusive, pgtype.Exclusive, pgtype.Unbounded, pgtype.Empty}
+	for _, lowerType := range boundTypes {
+		for _, upperType := range boundTypes {
+			r := FromPgtypeRange(pgtype.Range[int]{Lower: 1, LowerType: lowerType, Upper: 5, UpperType: upperType, Valid: true}, NewInteger())
+
+			expectedOk := lowerType == pgtype.Inclusive || lowerType == pgtype.Exclusive
+			expectedValue := 0
+			if expectedOk {
+				expectedValue = 1
+			}
+			if value, boundType, ok := r.LowerBound(); value != expectedValue || boundType != lowerType || ok != expectedOk {
+				t.Errorf("lower bound `%v`: expected `%v`, `%v` and `%v`, got `%v`, `%v` and `%v`", r.r, expectedValue, lowerType, expectedOk, value, boundType, ok)
+			}
+
+			expectedOk = upperType == pgtype.Inclusive || upperType == pgtype.Exclusive
+			expectedValue = 0
+			if expectedOk {
+				expectedValue = 5
+			}
+			if value, boundType, ok := r.UpperBound(); value != expectedValue || boundType != upperType || ok != expectedOk {
+				t.Errorf("upper bound `%v`: expected `%v`, `%v` and `%v`, got `%v`, `%v` and `%v`", r.r, expectedValue, upperType, expectedOk, value, boundType, ok)
+			}
+		}
+	}
+
+	r := NewIntegerRange(1, 5, WithInvalid[int, int]())
+	if _, _, ok := r.LowerBound(); ok {
+		t.Errorf("lower bound `%v`: expected not ok for an invalid range", r.r)
+	}
+	if _, _, ok := r.UpperBound(); ok {
+		t.Errorf("upper bound `%v`: expected not ok for an invalid range", r.r)
+	}
+}
+
+func TestTypedBounds(t *testing.T) {
+	start := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	boundTypes := []pgtype.BoundType{pgtype.Inclusive, pgtype.Exclusive, pgtype.Unbounded, pgtype.Empty}
+	for _, lowerType := range boundTypes {
+		for _, upperType := range boundTypes {
+			r := FromPgtypeRange(pgtype.Range[time.Time]{Lower: start, LowerType: lowerType, Upper: end, UpperType: upperType, Valid: true}, NewTime())
+
+			expectedLower := time.Time{}
+			if lowerType == pgtype.Inclusive || lowerType == pgtype.Exclusive {
+				expectedLower = start
+			}
+			expectedUpper := time.Time{}
+			if upperType == pgtype.Inclusive || upperType == pgtype.Exclusive {
+				expectedUpper = end
+			}
+			lower, upper, resultLowerType, resultUpperType := r.TypedBounds()
+			if !lower.Equal(expectedLower) || !upper.Equal(expectedUpper) || resultLowerType != lowerType || resultUpperType != upperType {
+				t.Errorf("typed bounds `%v`: expected `%v`, `%v`, `%v` and `%v`, got `%v`, `%v`, `%v` and `%v`", r.r, expectedLower, expectedUpper, lowerType, upperType, lower, upper, resultLowerType, resultUpperType)
+			}
+		}
+	}
+
+	r := NewIntegerRange(1, 5, WithInvalid[int, int]())
+	if lower, upper, _, _ := r.TypedBounds(); lower != 0 || upper != 0 {
+		t.Errorf("typed bounds `%v`: expected `0` and `0` for an invalid range, got `%v` and `%v`", r.r, lower, upper)
+	}
+}
+
+func TestIsFull(t *testing.T) {
+	tests := []struct {
+		r        IntegerRange
+		expected bool
+	}{
+		{r: NewIntegerRange(0, 0, WithLowerInf[int, int](), WithUpperType[int, int](pgtype.Unbounded)), expected: true},
+		{r: NewIntegerRange(0, 5, WithLowerInf[int, int]()), expected: false},
+		{r: NewIntegerRange(1, 0, WithUpperType[int, int](pgtype.Unbounded)), expected: false},
+		{r: NewIntegerRange(1, 5), expected: false},
+		{r: NewEmptyIntegerRange(), expected: false},
+		{r: NewIntegerRange(0, 0, WithLowerInf[int, int](), WithUpperType[int, int](pgtype.Unbounded), WithInvalid[int, int]()), expected: false},
+	}
+	for _, tt := range tests {
+		if result := tt.r.IsFull(); result != tt.expected {
+			t.Errorf("is full `%v`: expected result `%v`, got `%v`", tt.r.r, tt.expected, result)
+		}
+	}
+
+	// infinite float bounds are unbounded
+	inf := float32(math.Inf(1))
+	if r := NewRealRange(-inf, inf); !r.IsFull() {
+		t.Errorf("is full `%v`: expected result `true`, got `false`", r.r)
+	}
+	if r := NewRealRange(-inf, 0); r.IsFull() {
+		t.Errorf("is full `%v`: expected result `false`, got `true`", r.r)
+	}
+}
+
+func TestIsEmpty(t *testing.T) {
+	tests := []struct {
+		r        IntegerRange
+		expected bool
+	}{
+		{r: NewEmptyIntegerRange(), expected: true},
+		{r: NewIntegerRange(5, 5), expected: true},
+		{r: NewIntegerRange(5, 6, WithLowerType[int, int](pgtype.Exclusive)), expected: true},
+		{r: NewIntegerRange(1, 5), expected: false},
+		{r: NewIntegerRange(5, 5, WithUpperType[int, int](pgtype.Inclusive)), expected: false},
+		{r: NewIntegerRange(0, 5, WithLowerInf[int, int]()), expected: false},
+		{r: NewIntegerRange(5, 5, WithInvalid[int, int]()), expected: false},
+		{r: NewIntegerRange(1, 5, WithEmpty[int, int](), WithInvalid[int, int]()), expected: false},
+	}
+	for _, tt := range tests {
+		if result := tt.r.IsEmpty(); result != tt.expected {
+			t.Errorf("is empty `%v`: expected result `%v`, got `%v`", tt.r.r, tt.expected, result)
+		}
+	}
+
+	// a NaN bound is not empty
+	if r := NewRealRange(float32(math.NaN()), 1); r.IsEmpty() {
+		t.Errorf("is empty `%v`: expected result `false`, got `true`", r.r)
+	}
+}
+
+func TestDateRange(t *testing.T) {
+	jan1 := time.Date(2025, time.January, 1, 13, 30, 0, 0, time.UTC)
+	jan5 := time.Date(2025, time.January, 5, 0, 0, 0, 0, time.UTC)
+	jan10 := time.Date(2025, time.January, 10, 23, 59, 0, 0, time.UTC)
+
+	first := NewDateRange(jan1, jan5)
+	if size, err := first.Size(); err != nil || size != 4 {
+		t.Errorf("size `%v`: expected result `4`, got `%v` (error `%v`)", first, size, err)
+	}
+	if first.r.Lower != time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC) {
+		t.Errorf("lower `%v`: expected midnight, got `%v`", first, first.r.Lower)
+	}
+	second := NewDateRange(jan5, jan10)
+	if adjacent, err := first.Adjacent(second); err != nil || !adjacent {
+		t.Errorf("`%v` -|- `%v`: expected result `true`, got `%v` (error `%v`)", first, second, adjacent, err)
+	}
+	if overlap, err := first.Overlap(second); err != nil || overlap {
+		t.Errorf("`%v` && `%v`: expected result `false`, got `%v` (error `%v`)", first, second, overlap, err)
+	}
+
+	// an inclusive upper bound is canonicalized to the next day
+	inclusive := NewDateRange(jan1, jan5, WithUpperType[time.Time, int](pgtype.Inclusive))
+	if size, err := inclusive.Size(); err != nil || size != 5 {
+		t.Errorf("size `%v`: expected result `5`, got `%v` (error `%v`)", inclusive, size, err)
+	}
+	if adjacent, err := inclusive.Adjacent(NewDateRange(jan5.AddDate(0, 0, 1), jan10)); err != nil || !adjacent {
+		t.Errorf("`%v` -|- `[2025-01-06,2025-01-10)`: expected result `true`, got `%v` (error `%v`)", inclusive, adjacent, err)
+	}
+
+	// the time of day is ignored
+	if contains, err := first.ContainElement(jan5.Add(-time.Minute)); err != nil || !contains {
+		t.Errorf("`%v` @> `%v`: expected result `true`, got `%v` (error `%v`)", first, jan5.Add(-time.Minute), contains, err)
+	}
+	if result := first.String(); result != "[2025-01-01,2025-01-05)" {
+		t.Errorf("string: expected result `[2025-01-01,2025-01-05)`, got `%v`", result)
+	}
+
+	// days before the Unix epoch
+	old := NewDateRange(time.Date(1969, time.December, 31, 12, 0, 0, 0, time.UTC), time.Date(1970, time.January, 2, 0, 0, 0, 0, time.UTC))
+	if size, err := old.Size(); err != nil || size != 2 {
+		t.Errorf("size `%v`: expected result `2`, got `%v` (error `%v`)", old, size, err)
+	}
+}
+
+func TestOperatorForType(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		check func(any) bool
+	}{
+		{name: "int4range", check: func(ro any) bool { _, ok := ro.(operator[int32, int32]); return ok }},
+		{name: "int8range", check: func(ro any) bool { _, ok := ro.(operator[int, int]); return ok }},
+		{name: "numrange", check: func(ro any) bool { _, ok := ro.(operator[decimal.Decimal, float64]); return ok }},
+		{name: "tsrange", check: func(ro any) bool { _, ok := ro.(operator[time.Time, time.Duration]); return ok }},
+		{name: "tstzrange", check: func(ro any) bool { _, ok := ro.(operator[time.Time, time.Duration]); return ok }},
+		{name: "daterange", check: func(ro any) bool { _, ok := ro.(operator[time.Time, int]); return ok }},
+	} {
+		ro, err := OperatorForType(tt.name)
+		if err != nil {
+			t.Errorf("operator for `%s`: expected no error, got `%v`", tt.name, err)
+			continue
+		}
+		if !tt.check(ro) {
+			t.Errorf("operator for `%s`: unexpected operator type `%T`", tt.name, ro)
+		}
+	}
+
+	if _, err := OperatorForType("int8multirange"); err == nil {
+		t.Errorf("operator for `int8multirange`: expected error, got none")
+	}
+}
+
+func TestImmutable(t *testing.T) {
+	r := NewIntegerRange(1, 5)
+	other := NewIntegerRange(3, 8, WithUpperType[int, int](pgtype.Inclusive))
+	before := r.r
+
+	for _, tt := range []struct {
+		description string
+		fn          func(IntegerRange) (IntegerRange, error)
+		expected    IntegerRange
+	}{
+		{description: "unioned", fn: r.Unioned, expected: NewIntegerRange(1, 9)},
+		{description: "merged", fn: r.Merged, expected: NewIntegerRange(1, 9)},
+		{description: "intersected", fn: r.Intersected, expected: NewIntegerRange(3, 5)},
+		{description: "differenced", fn: r.Differenced, expected: NewIntegerRange(1, 3)},
+	} {
+		result, err := tt.fn(other)
+		if err != nil {
+			t.Errorf("%s: expected no error, got `%v`", tt.description, err)
+			continue
+		}
+		if result.r != tt.expected.r {
+			t.Errorf("%s: expected result `%v`, got `%v`", tt.description, tt.expected, result)
+		}
+		if r.r != before {
+			t.Errorf("%s: expected receiver `%v`, got `%v`", tt.description, before, r.r)
+		}
+	}
+
+	// the zero range is returned on an error
+	if result, err := r.Unioned(NewIntegerRange(7, 9)); err == nil || result.r != (pgtype.Range[int]{}) {
+		t.Errorf("unioned: expected error and the zero range, got `%v` (error `%v`)", result, err)
+	}
+
+	// the bounds of the receiver are not shared when they are changed
+	ptro := New[*int, int](
+		func(a, b *int) int {
+			return cmp.Compare(*a, *b)
+		},
+		func(a, b *int) int {
+			return *a - *b
+		},
+		func(a *int) *int {
+			v := *a + 1
+			return &v
+		},
+		true,
+	)
+	one, five, three, eight := 1, 5, 3, 8
+	p := NewRange(ptro, &one, &five, WithUpperType[*int, int](pgtype.Inclusive))
+	result, err := p.Unioned(NewRange(ptro, &three, &eight))
+	if err != nil {
+		t.Errorf("unioned pointers: expected no error, got `%v`", err)
+	} else if *result.r.Lower != 1 || *result.r.Upper != 8 {
+		t.Errorf("unioned pointers: expected result `[1,8)`, got `[%d,%d)`", *result.r.Lower, *result.r.Upper)
+	}
+	if p.r.Lower != &one || p.r.Upper != &five || p.r.UpperType != pgtype.Inclusive || one != 1 || five != 5 {
+		t.Errorf("unioned pointers: expected the receiver to be unchanged, got `[%d,%d%c`", *p.r.Lower, *p.r.Upper, p.r.UpperType)
+	}
+}
+
+func TestRealRange(t *testing.T) {
+	r := NewRealRange(0.5, 1.5)
+	if contain, err := r.ContainElement(1); err != nil || !contain {
+		t.Errorf("`%v` contains `1`: expected `true`, got `%v` (error `%v`)", r.r, contain, err)
+	}
+	if contain, err := r.ContainElement(1.5); err != nil || contain {
+		t.Errorf("`%v` contains `1.5`: expected `false`, got `%v` (error `%v`)", r.r, contain, err)
+	}
+}
+
+func TestValueAndScan(t *testing.T) {
+	tests := []struct {
+		r        IntegerRange
+		expected any
+	}{
+		{r: NewIntegerRange(1, 5), expected: "[1,5)"},
+		{r: NewIntegerRange(1, 5, WithLowerType[int, int](pgtype.Exclusive), WithUpperType[int, int](pgtype.Inclusive)), expected: "(1,5]"},
+		{r: NewIntegerRange(0, 5, WithLowerInf[int, int]()), expected: "(,5)"},
+		{r: NewIntegerRange(1, 5, WithInvalid[int, int]()), expected: nil},
+	}
+
+	for _, tt := range tests {
+		value, err := tt.r.Value()
+		if err != nil {
+			t.Errorf("value `%v`: expected no error, got `%v`", tt.r, err)
+			continue
+		}
+		if value != tt.expected {
+			t.Errorf("value `%v`: expected result `%v`, got `%v`", tt.r, tt.expected, value)
+		}
+
+		result := NewIntegerRange(0, 0)
+		if err := result.Scan(value); err != nil {
+			t.Errorf("scan `%v`: expected no error, got `%v`", value, err)
+			continue
+		}
+		if value == nil && result.r.Valid {
+			t.Errorf("scan `%v`: expected an invalid range, got `%v`", value, result.r)
+		}
+		if value != nil && result.r != tt.r.r {
+			t.Errorf("scan `%v`: expected result `%v`, got `%v`", value, tt.r.r, result.r)
+		}
+	}
+
+	timeRange := NewTimeRange(time.Unix(0, 0).UTC(), time.Unix(3600, 0).UTC())
+	value, err := timeRange.Value()
+	if err != nil {
+		t.Errorf("value `%v`: expected no error, got `%v`", timeRange, err)
+	}
+	result := NewTimeRange(time.Time{}, time.Time{})
+	if err := result.Scan(value); err != nil {
+		t.Errorf("scan `%v`: expected no error, got `%v`", value, err)
+	}
+	if equal, _ := timeRange.Equal(result); !equal {
+		t.Errorf("scan `%v`: expected result `%v`, got `%v`", value, timeRange, result)
+	}
+}
+
+func TestMarshalText(t *testing.T) {
+	tests := []struct {
+		r        IntegerRange
+		expected string
+	}{
+		{r: NewIntegerRange(1, 5), expected: "[1,5)"},
+		{r: NewIntegerRange(1, 5, WithLowerType[int, int](pgtype.Exclusive), WithUpperType[int, int](pgtype.Inclusive)), expected: "(1,5]"},
+		{r: NewIntegerRange(0, 5, WithLowerInf[int, int]()), expected: "(,5)"},
+		{r: NewIntegerRange(1, 0, WithUpperType[int, int](pgtype.Unbounded)), expected: "[1,)"},
+		{r: NewEmptyIntegerRange(), expected: "empty"},
+		{r: NewIntegerRange(1, 5, WithInvalid[int, int]()), expected: ""},
+	}
+
+	for _, tt := range tests {
+		text, err := tt.r.MarshalText()
+		if err != nil {
+			t.Errorf("marshal text `%v`: expected no error, got `%v`", tt.r.r, err)
+			continue
+		}
+		if string(text) != tt.expected {
+			t.Errorf("marshal text `%v`: expected result `%v`, got `%v`", tt.r.r, tt.expected, string(text))
+		}
+
+		result := NewIntegerRange(0, 0)
+		if err := result.UnmarshalText(text); err != nil {
+			t.Errorf("unmarshal text `%s`: expected no error, got `%v`", text, err)
+			continue
+		}
+		if result.r.Valid != tt.r.r.Valid || (result.r.Valid && result.r != tt.r.r) {
+			t.Errorf("unmarshal text `%s`: expected result `%v`, got `%v`", text, tt.r.r, result.r)
+		}
+	}
+
+	start := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	for _, r := range []TimeRange{
+		NewTimeRange(start, start.Add(time.Hour)),
+		NewTimeRange(start, start, WithLowerInf[time.Time, time.Duration]()),
+		NewEmptyTimeRange(),
+	} {
+		text, err := r.MarshalText()
+		if err != nil {
+			t.Errorf("marshal text `%v`: expected no error, got `%v`", r, err)
+			continue
+		}
+		result := NewEmptyTimeRange()
+		if err := result.UnmarshalText(text); err != nil {
+			t.Errorf("unmarshal text `%s`: expected no error, got `%v`", text, err)
+			continue
+		}
+		if equal, err := r.Equal(result); err != nil || !equal {
+			t.Errorf("unmarshal text `%s`: expected result `%v`, got `%v`", text, r, result)
+		}
+	}
+
+	var result IntegerRange
+	if err := result.UnmarshalText([]byte("[1,")); err == nil {
+		t.Errorf("unmarshal text `[1,`: expected error, got none")
+	}
+}
+
+func TestMarshalBinary(t *testing.T) {
+	tests := []struct {
+		r        IntegerRange
+		expected []byte
+	}{
+		{r: NewIntegerRange(1, 5), expected: []byte{0b01001, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 5}},
+		{r: NewIntegerRange(-1, 5, WithLowerType[int, int](pgtype.Exclusive), WithUpperType[int, int](pgtype.Inclusive))},
+		{r: NewIntegerRange(0, 5, WithLowerInf[int, int]()), expected: []byte{0b01101, 0, 0, 0, 0, 0, 0, 0, 5}},
+		{r: NewIntegerRange(1, 0, WithUpperType[int, int](pgtype.Unbounded))},
+		{r: NewIntegerRange(0, 0, WithLowerInf[int, int](), WithUpperType[int, int](pgtype.Unbounded))},
+		{r: NewEmptyIntegerRange(), expected: []byte{0b11111}},
+		{r: NewIntegerRange(1, 5, WithInvalid[int, int]()), expected: []byte{0}},
+	}
+
+	for _, tt := range tests {
+		data, err := tt.r.MarshalBinary()
+		if err != nil {
+			t.Errorf("marshal binary `%v`: expected no error, got `%v`", tt.r.r, err)
+			continue
+		}
+		if tt.expected != nil && !bytes.Equal(data, tt.expected) {
+			t.Errorf("marshal binary `%v`: expected result `%v`, got `%v`", tt.r.r, tt.expected, data)
+		}
+
+		result := NewIntegerRange(7, 7)
+		if err := result.UnmarshalBinary(data); err != nil {
+			t.Errorf("unmarshal binary `%v`: expected no error, got `%v`", data, err)
+			continue
+		}
+		if result.r.Valid != tt.r.r.Valid || (result.r.Valid && result.r != tt.r.r) {
+			t.Errorf("unmarshal binary `%v`: expected result `%v`, got `%v`", data, tt.r.r, result.r)
+		}
+	}
+
+	start := time.Date(2024, 3, 1, 9, 0, 0, 0, time.FixedZone("CET", 3600))
+	for _, r := range []TimeRange{
+		NewTimeRange(start, start.Add(time.Hour)),
+		NewTimeRange(start, start, WithLowerInf[time.Time, time.Duration]()),
+		NewEmptyTimeRange(),
+	} {
+		data, err := r.MarshalBinary()
+		if err != nil {
+			t.Errorf("marshal binary `%v`: expected no error, got `%v`", r, err)
+			continue
+		}
+		var result TimeRange
+		if err := result.UnmarshalBinary(data); err != nil {
+			t.Errorf("unmarshal binary `%v`: expected no error, got `%v`", data, err)
+			continue
+		}
+		if !result.r.Lower.Equal(r.r.Lower) || !result.r.Upper.Equal(r.r.Upper) || result.r.LowerType != r.r.LowerType || result.r.UpperType != r.r.UpperType {
+			t.Errorf("unmarshal binary `%v`: expected result `%v`, got `%v`", data, r, result)
+		}
+	}
+
+	// a custom encoder of the operator
+	ro := NewFloat64().WithBinary(
+		func(v float64) ([]byte, error) {
+			return []byte(strconv.FormatFloat(v, 'g', -1, 64)), nil
+		},
+		func(data []byte) (float64, error) {
+			return strconv.ParseFloat(string(data), 64)
+		},
+	)
+	r := NewRange(ro, 0.5, 1.5)
+	data, err := r.MarshalBinary()
+	if err != nil {
+		t.Errorf("marshal binary `%v`: expected no error, got `%v`", r, err)
+	} else if !bytes.Equal(data, []byte{0b01001, 3, '0', '.', '5', 3, '1', '.', '5'}) {
+		t.Errorf("marshal binary `%v`: expected custom encoding, got `%v`", r, data)
+	}
+	result := NewRange(ro, 0, 0)
+	if err := result.UnmarshalBinary(data); err != nil || result.r != r.r {
+		t.Errorf("unmarshal binary `%v`: expected result `%v`, got `%v` (error `%v`)", data, r, result, err)
+	}
+
+	for _, data := range [][]byte{
+		{},
+		{0b00011, 0, 0, 0, 0, 0, 0, 0, 1},
+		{0b11111, 0},
+		{0b100001},
+		{2},
+	} {
+		var result IntegerRange
+		if err := result.UnmarshalBinary(data); err == nil {
+			t.Errorf("unmarshal binary `%v`: expected error, got none", data)
+		}
+	}
+	if _, err := NewRange(New[string, int](strings.Compare, nil, nil, false), "a", "b").MarshalBinary(); err == nil {
+		t.Errorf("marshal binary string range: expected error, got none")
+	}
+}
+
+func TestFormat(t *testing.T) {
+	start := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		r        TimeRange
+		expected string
+	}{
+		{r: NewTimeRange(start, start.Add(time.Hour)), expected: "[2024-03-01T09:00:00Z,2024-03-01T10:00:00Z)"},
+		{r: NewTimeRange(start, start.Add(1500*time.Millisecond), WithUpperType[time.Time, time.Duration](pgtype.Inclusive)), expected: "[2024-03-01T09:00:00Z,2024-03-01T09:00:01.5Z]"},
+		{r: NewTimeRange(start, time.Time{}, WithUpperType[time.Time, time.Duration](pgtype.Unbounded)), expected: "[2024-03-01T09:00:00Z,)"},
+		{r: NewEmptyTimeRange(), expected: "empty"},
+	}
+	for _, tt := range tests {
+		if result := tt.r.String(); result != tt.expected {
+			t.Errorf("string `%v`: expected result `%v`, got `%v`", tt.r.r, tt.expected, result)
+		}
+		result := NewEmptyTimeRange()
+		if err := result.UnmarshalText([]byte(tt.expected)); err != nil {
+			t.Errorf("unmarshal text `%v`: expected no error, got `%v`", tt.expected, err)
+		} else if result.r != tt.r.r {
+			t.Errorf("unmarshal text `%v`: expected result `%v`, got `%v`", tt.expected, tt.r.r, result.r)
+		}
+	}
+
+	// bounds are quoted when needed
+	ro := NewInteger().WithFormat(
+		func(v int) string { return fmt.Sprintf("#%d, \"n\"", v) },
+		func(text string) (int, error) {
+			var v int
+			_, err := fmt.Sscanf(text, "#%d, \"n\"", &v)
+			return v, err
+		},
+	)
+	r := NewRange(ro, 1, 5, WithLowerType[int, int](pgtype.Exclusive))
+	expected := `("#1, ""n""","#5, ""n""")`
+	if result := r.String(); result != expected {
+		t.Errorf("string `%v`: expected result `%v`, got `%v`", r.r, expected, result)
+	}
+	result := NewRange(ro, 0, 0)
+	if err := result.UnmarshalText([]byte(expected)); err != nil {
+		t.Errorf("unmarshal text `%v`: expected no error, got `%v`", expected, err)
+	} else if result.r != r.r {
+		t.Errorf("unmarshal text `%v`: expected result `%v`, got `%v`", expected, r.r, result.r)
+	}
+
+	for _, text := range []string{"1,5)", "[1,5", "[1;5)", "[1,5)x", "[\"1,5)"} {
+		if err := result.UnmarshalText([]byte(text)); err == nil {
+			t.Errorf("unmarshal text `%v`: expected error, got none", text)
+		}
+	}
+}
+
+func TestDatabaseSQL(t *testing.T) {
+	db := stdlib.OpenDBFromPool(conn)
+	defer db.Close()
+
+	integerRange := NewIntegerRange(-3, 5, WithLowerType[int, int](pgtype.Exclusive), WithUpperType[int, int](pgtype.Inclusive))
+	integerResult := NewIntegerRange(0, 0)
+	if err := db.QueryRow(`SELECT $1::int8range`, integerRange).Scan(&integerResult); err != nil {
+		t.Fatalf("int8range: expected no error, got `%v`", err)
+	}
+	if equal, _ := integerRange.Equal(integerResult); !equal {
+		t.Errorf("int8range: expected result `%v`, got `%v`", integerRange, integerResult)
+	}
+
+	timeRange := NewTimeRange(time.Unix(100, 0), time.Unix(200, 0), WithUpperType[time.Time, time.Duration](pgtype.Inclusive))
+	timeResult := NewTimeRange(time.Time{}, time.Time{})
+	if err := db.QueryRow(`SELECT $1::tstzrange`, timeRange).Scan(&timeResult); err != nil {
+		t.Fatalf("tstzrange: expected no error, got `%v`", err)
+	}
+	if equal, _ := timeRange.Equal(timeResult); !equal {
+		t.Errorf("tstzrange: expected result `%v`, got `%v`", timeRange, timeResult)
+	}
+}
+
+func TestRegisterRangeTypes(t *testing.T) {
+	m := pgtype.NewMap()
+	RegisterRangeTypes(m)
+
+	tests := []struct {
+		value    any
+		expected uint32
+	}{
+		{value: IntegerRange{}, expected: pgtype.Int8rangeOID},
+		{value: &IntegerRange{}, expected: pgtype.Int8rangeOID},
+		{value: TimeRange{}, expected: pgtype.TstzrangeOID},
+		{value: &TimeRange{}, expected: pgtype.TstzrangeOID},
+		{value: DecimalRange{}, expected: pgtype.NumrangeOID},
+		{value: &DecimalRange{}, expected: pgtype.NumrangeOID},
+	}
+
+	for _, tt := range tests {
+		dt, ok := m.TypeForValue(tt.value)
+		if !ok {
+			t.Errorf("register `%T`: expected a registered type, got none", tt.value)
+			continue
+		}
+		if dt.OID != tt.expected {
+			t.Errorf("register `%T`: expected OID `%v`, got `%v`", tt.value, tt.expected, dt.OID)
+		}
+	}
+}
+
+func TestRowToRange(t *testing.T) {
+	ctx := context.Background()
+
+	rows, err := conn.Query(ctx, `SELECT int8range(i, i + 10, '(]') FROM generate_series(1, 3) AS i`)
+	if err != nil {
+		t.Fatalf("int8range: expected no error, got `%v`", err)
+	}
+	integerRanges, err := pgx.CollectRows(rows, RowToIntegerRange)
+	if err != nil {
+		t.Fatalf("int8range: expected no error, got `%v`", err)
+	}
+	for i, expected := range []IntegerRange{NewIntegerRange(2, 12), NewIntegerRange(3, 13), NewIntegerRange(4, 14)} {
+		if i >= len(integerRanges) {
+			t.Errorf("int8range: expected range `%v`, got none", expected)
+			continue
+		}
+		if equal, err := expected.Equal(integerRanges[i]); err != nil || !equal {
+			t.Errorf("int8range: expected range `%v`, got `%v` (error `%v`)", expected, integerRanges[i], err)
+		}
+	}
+
+	rows, err = conn.Query(ctx, `SELECT tstzrange(to_timestamp(i * 60), to_timestamp(i * 60 + 60)) FROM generate_series(0, 1) AS i`)
+	if err != nil {
+		t.Fatalf("tstzrange: expected no error, got `%v`", err)
+	}
+	timeRanges, err := pgx.CollectRows(rows, RowToTimeRange)
+	if err != nil {
+		t.Fatalf("tstzrange: expected no error, got `%v`", err)
+	}
+	for i, expected := range []TimeRange{NewTimeRange(time.Unix(0, 0), time.Unix(60, 0)), NewTimeRange(time.Unix(60, 0), time.Unix(120, 0))} {
+		if i >= len(timeRanges) {
+			t.Errorf("tstzrange: expected range `%v`, got none", expected)
+			continue
+		}
+		if equal, err := expected.Equal(timeRanges[i]); err != nil || !equal {
+			t.Errorf("tstzrange: expected range `%v`, got `%v` (error `%v`)", expected, timeRanges[i], err)
+		}
+	}
+}
+
+func TestCopyFromRanges(t *testing.T) {
+	ctx := context.Background()
+	c, err := conn.Acquire(ctx)
+	if err != nil {
+		t.Fatalf("acquire: expected no error, got `%v`", err)
+	}
+	defer c.Release()
+
+	if _, err := c.Exec(ctx, `CREATE TEMPORARY TABLE copy_from_ranges (r int8range)`); err != nil {
+		t.Fatalf("create table: expected no error, got `%v`", err)
+	}
+	defer c.Exec(ctx, `DROP TABLE copy_from_ranges`)
+
+	rs := make([]IntegerRange, 10_000)
+	for i := range rs {
+		rs[i] = NewIntegerRange(i, i+10, WithUpperType[int, int](pgtype.Inclusive))
+	}
+	rs[1] = NewEmptyIntegerRange()
+	rs[2] = NewIntegerRange(0, 5, WithLowerInf[int, int]())
+
+	n, err := c.Conn().CopyFrom(ctx, pgx.Identifier{"copy_from_ranges"}, []string{"r"}, CopyFromRanges(rs))
+	if err != nil {
+		t.Fatalf("copy from: expected no error, got `%v`", err)
+	}
+	if n != int64(len(rs)) {
+		t.Errorf("copy from: expected `%d` rows, got `%d`", len(rs), n)
+	}
+
+	var count int64
+	if err := c.QueryRow(ctx, `SELECT count(*) FROM copy_from_ranges`).Scan(&count); err != nil {
+		t.Fatalf("count: expected no error, got `%v`", err)
+	}
+	if count != int64(len(rs)) {
+		t.Errorf("count: expected `%d` rows, got `%d`", len(rs), count)
+	}
+
+	for _, expected := range []IntegerRange{rs[1], rs[2], rs[9_999]} {
+		result := NewEmptyIntegerRange()
+		if err := c.QueryRow(ctx, `SELECT r FROM copy_from_ranges WHERE r = $1`, expected).Scan(&result); err != nil {
+			t.Errorf("select `%v`: expected no error, got `%v`", expected, err)
+			continue
+		}
+		if equal, err := expected.Equal(result); err != nil || !equal {
+			t.Errorf("select `%v`: expected result `%v`, got `%v` (error `%v`)", expected, expected, result, err)
+		}
+	}
+}
+
+func TestRegisterRangeTypesRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	c, err := conn.Acquire(ctx)
+	if err != nil {
+		t.Fatalf("acquire: expected no error, got `%v`", err)
+	}
+	defer c.Release()
+	RegisterRangeTypes(c.Conn().TypeMap())
+
+	if _, err := c.Exec(ctx, `CREATE TEMPORARY TABLE register_range_types (i int8range, t tstzrange)`); err != nil {
+		t.Fatalf("create table: expected no error, got `%v`", err)
+	}
+	defer c.Exec(ctx, `DROP TABLE register_range_types`)
+
+	integerRange := NewIntegerRange(-3, 5, WithUpperType[int, int](pgtype.Inclusive))
+	timeRange := NewTimeRange(time.Unix(100, 0), time.Unix(200, 0))
+	if _, err := c.Exec(ctx, `INSERT INTO register_range_types VALUES ($1, $2)`, integerRange, timeRange); err != nil {
+		t.Fatalf("insert: expected no error, got `%v`", err)
+	}
+
+	integerResult := NewIntegerRange(0, 0)
+	timeResult := NewTimeRange(time.Time{}, time.Time{})
+	if err := c.QueryRow(ctx, `SELECT i, t FROM register_range_types`).Scan(&integerResult, &timeResult); err != nil {
+		t.Fatalf("select: expected no error, got `%v`", err)
+	}
+	if equal, _ := integerRange.Equal(integerResult); !equal {
+		t.Errorf("int8range: expected result `%v`, got `%v`", integerRange, integerResult)
+	}
+	if equal, _ := timeRange.Equal(timeResult); !equal {
+		t.Errorf("tstzrange: expected result `%v`, got `%v`", timeRange, timeResult)
+	}
+}
@@ -0,0 +1,271 @@
+package pro
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"reflect"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// VerifyingOperator wraps an operator and runs the equivalent SQL expression for every operation
+// on a live database. A warning is logged when the results of Go and PostgreSQL disagree, the
+// result of the wrapped operator is returned either way. Every operation costs a round trip to
+// the database, so it is meant for staging environments and rollouts only.
+type VerifyingOperator[T any, S Number] struct {
+	ro      operator[T, S]
+	pool    *pgxpool.Pool
+	sqlType string
+	logger  *slog.Logger
+}
+
+// NewVerifying creates a verifying operator that compares the results of ro with the results of
+// PostgreSQL for ranges of sqlType, for example int8range or tstzrange. Mismatches are logged with
+// the default logger.
+func NewVerifying[T any, S Number](ro operator[T, S], pool *pgxpool.Pool, sqlType string) VerifyingOperator[T, S] {
+	return VerifyingOperator[T, S]{
+		ro:      ro,
+		pool:    pool,
+		sqlType: sqlType,
+		logger:  slog.Default(),
+	}
+}
+
+// WithLogger returns a copy of the verifying operator that logs mismatches with logger.
+func (v VerifyingOperator[T, S]) WithLogger(logger *slog.Logger) VerifyingOperator[T, S] {
+	v.logger = logger
+	return v
+}
+
+// Operator returns the wrapped operator.
+func (v VerifyingOperator[T, S]) Operator() operator[T, S] {
+	return v.ro
+}
+
+func (v VerifyingOperator[T, S]) Empty(ctx context.Context, r pgtype.Range[T]) (bool, error) {
+	result, err := v.ro.Empty(r)
+	verifyFunction(ctx, v, "isempty", r, result, err)
+	return result, err
+}
+
+func (v VerifyingOperator[T, S]) LowerInf(ctx context.Context, r pgtype.Range[T]) bool {
+	result := v.ro.LowerInf(r)
+	if r.Valid {
+		verifyFunction(ctx, v, "lower_inf", r, result, nil)
+	}
+	return result
+}
+
+func (v VerifyingOperator[T, S]) UpperInf(ctx context.Context, r pgtype.Range[T]) bool {
+	result := v.ro.UpperInf(r)
+	if r.Valid {
+		verifyFunction(ctx, v, "upper_inf", r, result, nil)
+	}
+	return result
+}
+
+func (v VerifyingOperator[T, S]) LowerInc(ctx context.Context, r pgtype.Range[T]) bool {
+	result := v.ro.LowerInc(r)
+	if r.Valid {
+		verifyFunction(ctx, v, "lower_inc", r, result, nil)
+	}
+	return result
+}
+
+func (v VerifyingOperator[T, S]) UpperInc(ctx context.Context, r pgtype.Range[T]) bool {
+	result := v.ro.UpperInc(r)
+	if r.Valid {
+		verifyFunction(ctx, v, "upper_inc", r, result, nil)
+	}
+	return result
+}
+
+func (v VerifyingOperator[T, S]) Equal(ctx context.Context, first, second pgtype.Range[T]) (bool, error) {
+	result, err := v.ro.Equal(first, second)
+	verifyOperator(ctx, v, "=", first, second, result, err)
+	return result, err
+}
+
+func (v VerifyingOperator[T, S]) LessThan(ctx context.Context, first, second pgtype.Range[T]) (bool, error) {
+	result, err := v.ro.LessThan(first, second)
+	verifyOperator(ctx, v, "<", first, second, result, err)
+	return result, err
+}
+
+func (v VerifyingOperator[T, S]) LessThanOrEqualTo(ctx context.Context, first, second pgtype.Range[T]) (bool, error) {
+	result, err := v.ro.LessThanOrEqualTo(first, second)
+	verifyOperator(ctx, v, "<=", first, second, result, err)
+	return result, err
+}
+
+func (v VerifyingOperator[T, S]) GreaterThan(ctx context.Context, first, second pgtype.Range[T]) (bool, error) {
+	result, err := v.ro.GreaterThan(first, second)
+	verifyOperator(ctx, v, ">", first, second, result, err)
+	return result, err
+}
+
+func (v VerifyingOperator[T, S]) GreaterThanOrEqualTo(ctx context.Context, first, second pgtype.Range[T]) (bool, error) {
+	result, err := v.ro.GreaterThanOrEqualTo(first, second)
+	verifyOperator(ctx, v, ">=", first, second, result, err)
+	return result, err
+}
+
+func (v VerifyingOperator[T, S]) Contain(ctx context.Context, first, second pgtype.Range[T]) (bool, error) {
+	result, err := v.ro.Contain(first, second)
+	verifyOperator(ctx, v, "@>", first, second, result, err)
+	return result, err
+}
+
+// ContainElement looks up the element type of the range type before it runs the expression, so
+// it costs an extra round trip.
+func (v VerifyingOperator[T, S]) ContainElement(ctx context.Context, first pgtype.Range[T], elem T) (bool, error) {
+	result, err := v.ro.ContainElement(first, elem)
+	v.verifyElement(ctx, "`%v` @> `%v`", "SELECT @first::%[1]s @> @second::%[2]s", first, elem, result, err)
+	return result, err
+}
+
+func (v VerifyingOperator[T, S]) ContainedBy(ctx context.Context, first, second pgtype.Range[T]) (bool, error) {
+	result, err := v.ro.ContainedBy(first, second)
+	verifyOperator(ctx, v, "<@", first, second, result, err)
+	return result, err
+}
+
+// ElementContainedBy looks up the element type of the range type before it runs the expression,
+// so it costs an extra round trip.
+func (v VerifyingOperator[T, S]) ElementContainedBy(ctx context.Context, elem T, r pgtype.Range[T]) (bool, error) {
+	result, err := v.ro.ElementContainedBy(elem, r)
+	v.verifyElement(ctx, "`%[2]v` <@ `%[1]v`", "SELECT @second::%[2]s <@ @first::%[1]s", r, elem, result, err)
+	return result, err
+}
+
+func (v VerifyingOperator[T, S]) Overlap(ctx context.Context, first, second pgtype.Range[T]) (bool, error) {
+	result, err := v.ro.Overlap(first, second)
+	verifyOperator(ctx, v, "&&", first, second, result, err)
+	return result, err
+}
+
+func (v VerifyingOperator[T, S]) LeftOf(ctx context.Context, first, second pgtype.Range[T]) (bool, error) {
+	result, err := v.ro.LeftOf(first, second)
+	verifyOperator(ctx, v, "<<", first, second, result, err)
+	return result, err
+}
+
+func (v VerifyingOperator[T, S]) RightOf(ctx context.Context, first, second pgtype.Range[T]) (bool, error) {
+	result, err := v.ro.RightOf(first, second)
+	verifyOperator(ctx, v, ">>", first, second, result, err)
+	return result, err
+}
+
+func (v VerifyingOperator[T, S]) NotExtendRight(ctx context.Context, first, second pgtype.Range[T]) (bool, error) {
+	result, err := v.ro.NotExtendRight(first, second)
+	verifyOperator(ctx, v, "&<", first, second, result, err)
+	return result, err
+}
+
+func (v VerifyingOperator[T, S]) NotExtendLeft(ctx context.Context, first, second pgtype.Range[T]) (bool, error) {
+	result, err := v.ro.NotExtendLeft(first, second)
+	verifyOperator(ctx, v, "&>", first, second, result, err)
+	return result, err
+}
+
+func (v VerifyingOperator[T, S]) Adjacent(ctx context.Context, first, second pgtype.Range[T]) (bool, error) {
+	result, err := v.ro.Adjacent(first, second)
+	verifyOperator(ctx, v, "-|-", first, second, result, err)
+	return result, err
+}
+
+func (v VerifyingOperator[T, S]) Union(ctx context.Context, first, second pgtype.Range[T]) (pgtype.Range[T], error) {
+	result, err := v.ro.Union(first, second)
+	verifyOperator(ctx, v, "+", first, second, result, err)
+	return result, err
+}
+
+func (v VerifyingOperator[T, S]) Merge(ctx context.Context, first, second pgtype.Range[T]) (pgtype.Range[T], error) {
+	result, err := v.ro.Merge(first, second)
+	verify(ctx, v, fmt.Sprintf("range_merge(`%v`, `%v`)", first, second),
+		fmt.Sprintf("SELECT range_merge(@first::%[1]s, @second::%[1]s)", v.sqlType),
+		pgx.NamedArgs{"first": first, "second": second}, result, err)
+	return result, err
+}
+
+func (v VerifyingOperator[T, S]) Intersect(ctx context.Context, first, second pgtype.Range[T]) (pgtype.Range[T], error) {
+	result, err := v.ro.Intersect(first, second)
+	verifyOperator(ctx, v, "*", first, second, result, err)
+	return result, err
+}
+
+func (v VerifyingOperator[T, S]) Difference(ctx context.Context, first, second pgtype.Range[T]) (pgtype.Range[T], error) {
+	result, err := v.ro.Difference(first, second)
+	verifyOperator(ctx, v, "-", first, second, result, err)
+	return result, err
+}
+
+// verifyElement runs query, formatted with the range type and its element type, for an operation
+// on a range and an element
+func (v VerifyingOperator[T, S]) verifyElement(ctx context.Context, description, query string, r pgtype.Range[T], elem T, result bool, err error) {
+	var elemType string
+	if qErr := v.pool.QueryRow(
+		ctx,
+		"SELECT format_type(rngsubtype, NULL) FROM pg_range WHERE rngtypid = $1::regtype",
+		v.sqlType,
+	).Scan(&elemType); qErr != nil {
+		v.logger.WarnContext(ctx, "range verification failed", "operation", fmt.Sprintf(description, r, elem), "error", qErr)
+		return
+	}
+	verify(ctx, v, fmt.Sprintf(description, r, elem), fmt.Sprintf(query, v.sqlType, elemType),
+		pgx.NamedArgs{"first": r, "second": elem}, result, err)
+}
+
+// verifyFunction verifies the outcome of a function with a single range argument
+func verifyFunction[T any, S Number, R any](ctx context.Context, v VerifyingOperator[T, S], sqlFunction string, r pgtype.Range[T], result R, err error) {
+	verify(ctx, v, fmt.Sprintf("%s(`%v`)", sqlFunction, r),
+		fmt.Sprintf("SELECT %s(@first::%s)", sqlFunction, v.sqlType),
+		pgx.NamedArgs{"first": r}, result, err)
+}
+
+// verifyOperator verifies the outcome of an operator with two range operands
+func verifyOperator[T any, S Number, R any](ctx context.Context, v VerifyingOperator[T, S], sqlOperator string, first, second pgtype.Range[T], result R, err error) {
+	verify(ctx, v, fmt.Sprintf("`%v` %s `%v`", first, sqlOperator, second),
+		fmt.Sprintf("SELECT @first::%[1]s %[2]s @second::%[1]s", v.sqlType, sqlOperator),
+		pgx.NamedArgs{"first": first, "second": second}, result, err)
+}
+
+// verify runs the query and logs a warning if its outcome differs from the outcome in Go, both
+// the result and whether an error occurred. Range results are compared with Equal so
+// equivalent ranges with different bounds match.
+func verify[T any, S Number, R any](ctx context.Context, v VerifyingOperator[T, S], description, query string, args pgx.NamedArgs, result R, err error) {
+	var expected R
+	expectedErr := func() error {
+		rows, err := v.pool.Query(ctx, query, args)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		expected, err = pgx.CollectExactlyOneRow(rows, pgx.RowTo[R])
+		return err
+	}()
+	if ctx.Err() != nil {
+		v.logger.WarnContext(ctx, "range verification failed", "operation", description, "error", ctx.Err())
+		return
+	}
+
+	switch {
+	case err == nil && expectedErr != nil:
+		v.logger.WarnContext(ctx, "range verification mismatch", "operation", description, "result", result, "expected_error", expectedErr)
+	case err != nil && expectedErr == nil:
+		v.logger.WarnContext(ctx, "range verification mismatch", "operation", description, "error", err, "expected", expected)
+	case err == nil && !v.equalResults(result, expected):
+		v.logger.WarnContext(ctx, "range verification mismatch", "operation", description, "result", result, "expected", expected)
+	}
+}
+
+func (v VerifyingOperator[T, S]) equalResults(result, expected any) bool {
+	if r, ok := result.(pgtype.Range[T]); ok {
+		equal, err := v.ro.Equal(r, expected.(pgtype.Range[T]))
+		return err == nil && equal
+	}
+	return reflect.DeepEqual(result, expected)
+}
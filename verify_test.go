@@ -0,0 +1,53 @@
+package pro
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestVerifying(t *testing.T) {
+	ctx := context.Background()
+	first := pgtype.Range[int64]{Lower: 1, LowerType: pgtype.Inclusive, Upper: 5, UpperType: pgtype.Exclusive, Valid: true}
+	second := pgtype.Range[int64]{Lower: 3, LowerType: pgtype.Exclusive, Upper: 9, UpperType: pgtype.Inclusive, Valid: true}
+
+	var buf bytes.Buffer
+	v := NewVerifying(iro, conn, "int8range").WithLogger(slog.New(slog.NewTextHandler(&buf, nil)))
+	v.LessThan(ctx, first, second)
+	v.Overlap(ctx, first, second)
+	v.Contain(ctx, first, second)
+	v.ContainElement(ctx, first, 4)
+	v.ElementContainedBy(ctx, 5, first)
+	v.Union(ctx, first, second)
+	v.Intersect(ctx, first, second)
+	v.Merge(ctx, first, second)
+	v.Empty(ctx, first)
+	v.LowerInc(ctx, first)
+	// both fail for an invalid range
+	v.Equal(ctx, first, pgtype.Range[int64]{})
+	if buf.Len() != 0 {
+		t.Errorf("verifying a correct operator: expected no warnings, got `%s`", buf.String())
+	}
+
+	// an operator that orders the elements the wrong way around
+	wrong := iro
+	wrong.cmp = func(a, b int64) int {
+		return cmp.Compare(b, a)
+	}
+	buf.Reset()
+	v = NewVerifying(wrong, conn, "int8range").WithLogger(slog.New(slog.NewTextHandler(&buf, nil)))
+	v.LessThan(ctx, first, second)
+	if !strings.Contains(buf.String(), "range verification mismatch") {
+		t.Errorf("verifying a wrong operator: expected a mismatch warning, got `%s`", buf.String())
+	}
+	buf.Reset()
+	v.ContainElement(ctx, first, 4)
+	if !strings.Contains(buf.String(), "range verification mismatch") {
+		t.Errorf("verifying a wrong operator: expected a mismatch warning, got `%s`", buf.String())
+	}
+}